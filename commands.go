@@ -0,0 +1,183 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/nozomi-koborinai/gcp-cost-mcp-server/internal/pricing"
+)
+
+// runSnapshotCommand implements the "snapshot" subcommand, which crawls the Cloud Billing
+// catalog into a snapshot file for offline mode (GCP_COST_SNAPSHOT)
+func runSnapshotCommand(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("snapshot", flag.ContinueOnError)
+	output := fs.String("o", "catalog-snapshot.json.gz", "output file (gzip-compressed when it ends in .gz)")
+	services := fs.String("services", "", "comma-separated service IDs or display names to include (default: all services)")
+	currencies := fs.String("currency", "USD", "comma-separated currency codes to fetch prices in")
+	concurrency := fs.Int("concurrency", pricing.DefaultBuildConcurrency, "maximum number of concurrent price requests")
+	resume := fs.Bool("resume", true, "resume an interrupted run from its progress file")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s snapshot [flags]\n\nCrawls the Cloud Billing catalog into a snapshot file.\n\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+
+	client, err := pricing.NewClient(ctx, clientOptionsFromEnv()...)
+	if err != nil {
+		return fmt.Errorf("failed to create Pricing API client: %w", err)
+	}
+
+	// Progress is checkpointed after every service so an interrupted run can pick up where it stopped
+	progressPath := *output + ".partial"
+	opts := pricing.BuildOptions{
+		Services:      splitList(*services),
+		CurrencyCodes: splitList(*currencies),
+		Concurrency:   *concurrency,
+		Checkpoint: func(snapshot *pricing.Snapshot, done, total int) error {
+			log.Printf("Snapshot: %d/%d services done", done, total)
+			return pricing.SaveSnapshot(progressPath, snapshot)
+		},
+	}
+
+	if *resume {
+		partial, err := pricing.LoadSnapshot(progressPath)
+		switch {
+		case err == nil:
+			log.Printf("Snapshot: resuming from %s (%d services done)", progressPath, len(partial.SKUs))
+			opts.Resume = partial
+		case !errors.Is(err, os.ErrNotExist):
+			return err
+		}
+	}
+
+	snapshot, err := pricing.BuildSnapshot(ctx, client, opts)
+	if err != nil {
+		return err
+	}
+
+	if err := pricing.SaveSnapshot(*output, snapshot); err != nil {
+		return err
+	}
+	if err := os.Remove(progressPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		log.Printf("Warning: failed to remove progress file %s: %v", progressPath, err)
+	}
+
+	log.Printf("Snapshot written to %s (%d services)", *output, len(snapshot.Services))
+	return nil
+}
+
+// runDiffCommand implements the "diff" subcommand, which reports catalog changes between two
+// snapshots, or between a snapshot and the live catalog
+func runDiffCommand(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("diff", flag.ContinueOnError)
+	currency := fs.String("currency", "USD", "currency code of the prices to compare")
+	asJSON := fs.Bool("json", false, "print the diff as JSON")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s diff [flags] <baseline snapshot> [<compare snapshot>]\n\n"+
+			"Reports added/removed SKUs and price changes. Without a second snapshot,\n"+
+			"the baseline is compared with the live catalog.\n\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if fs.NArg() < 1 || fs.NArg() > 2 {
+		fs.Usage()
+		return fmt.Errorf("expected one or two snapshot files")
+	}
+
+	baseline, err := pricing.LoadSnapshot(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	var diff *pricing.SnapshotDiff
+	if fs.NArg() == 2 {
+		compare, err := pricing.LoadSnapshot(fs.Arg(1))
+		if err != nil {
+			return err
+		}
+		diff = pricing.DiffSnapshots(baseline, compare, *currency)
+	} else {
+		client, err := pricing.NewClient(ctx, clientOptionsFromEnv()...)
+		if err != nil {
+			return fmt.Errorf("failed to create Pricing API client: %w", err)
+		}
+		diff, err = pricing.DiffLive(ctx, baseline, client, *currency, pricing.DefaultBuildConcurrency)
+		if err != nil {
+			return err
+		}
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(diff)
+	}
+	writeDiffReport(os.Stdout, diff)
+	return nil
+}
+
+// writeDiffReport prints a human-readable catalog diff
+func writeDiffReport(w io.Writer, diff *pricing.SnapshotDiff) {
+	fmt.Fprintf(w, "Catalog diff (%s): %d SKUs added, %d removed, %d with price changes\n",
+		diff.CurrencyCode, diff.AddedSKUs, diff.RemovedSKUs, diff.ChangedSKUs)
+
+	for _, service := range diff.Services {
+		fmt.Fprintf(w, "\n%s (%s)\n", service.DisplayName, service.ServiceID)
+		for _, region := range service.Regions {
+			fmt.Fprintf(w, "  %s\n", region.Region)
+			for _, sku := range region.Added {
+				fmt.Fprintf(w, "    + %s %s\n", sku.SKUID, sku.DisplayName)
+			}
+			for _, sku := range region.Removed {
+				fmt.Fprintf(w, "    - %s %s\n", sku.SKUID, sku.DisplayName)
+			}
+			for _, sku := range region.Changed {
+				fmt.Fprintf(w, "    ~ %s %s\n", sku.SKUID, sku.DisplayName)
+				for _, c := range sku.Changes {
+					fmt.Fprintf(w, "        %s tier from %s: %s -> %s", c.ConsumptionModel,
+						strings.TrimSpace(c.StartAmount+" "+c.Unit), formatPrice(c.OldPrice), formatPrice(c.NewPrice))
+					if c.ChangePercent != 0 {
+						fmt.Fprintf(w, " (%+.2f%%)", c.ChangePercent)
+					}
+					fmt.Fprintln(w)
+				}
+			}
+		}
+	}
+}
+
+// formatPrice formats an optional per-unit price
+func formatPrice(price *float64) string {
+	if price == nil {
+		return "none"
+	}
+	return fmt.Sprintf("%g", *price)
+}
+
+// splitList splits a comma-separated flag value, dropping empty entries
+func splitList(v string) []string {
+	var items []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,142 @@
+package freetier
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Conditions of free tiers, as worded in FreeTierInfo.Conditions
+const (
+	ConditionPerBillingAccount = "Free tier usage is per billing account, shared by all of its projects"
+	ConditionPerProject        = "Free tier usage is per project"
+	ConditionNoCommittedUse    = "Not available with committed use discounts"
+	ConditionNoRequestBased    = "Does not apply to request-based billing"
+	ConditionNoInstanceBased   = "Does not apply to instance-based billing"
+	ConditionNoGPUs            = "GPUs and TPUs are not included"
+	ConditionNoCarryOver       = "Unused free tier usage does not carry over to the next period"
+)
+
+// conditionPatterns recognize the caveats of free tiers in documentation
+var conditionPatterns = []struct {
+	Regex     *regexp.Regexp
+	Condition string
+}{
+	{regexp.MustCompile(`(?i)per\s+billing\s+account|across\s+all\s+(?:of\s+your\s+)?projects`), ConditionPerBillingAccount},
+	{regexp.MustCompile(`(?i)per\s+project\b`), ConditionPerProject},
+	{regexp.MustCompile(`(?i)(?:not|n't)\s+(?:available|eligible|apply|applicable|included)\s+(?:\w+\s+){0,3}committed[- ]use|committed[- ]use\s+(?:discounts?\s+)?(?:are|is)\s+not\s+eligible`), ConditionNoCommittedUse},
+	{regexp.MustCompile(`(?i)(?:not|n't)\s+apply\s+to\s+(?:the\s+)?requests?[- ]based\s+billing`), ConditionNoRequestBased},
+	{regexp.MustCompile(`(?i)(?:not|n't)\s+apply\s+to\s+(?:the\s+)?instance[- ]based\s+billing`), ConditionNoInstanceBased},
+	{regexp.MustCompile(`(?i)\b(?:GPUs?|TPUs?)\b[^.]{0,40}\bnot\s+included`), ConditionNoGPUs},
+	{regexp.MustCompile(`(?i)(?:not|n't)\s+(?:roll|carry)\s+over`), ConditionNoCarryOver},
+}
+
+// freeTierMention recognizes text that talks about a free allowance
+var freeTierMention = regexp.MustCompile(`(?i)\bfree\b|at\s+no\s+(?:charge|cost)`)
+
+// sentencePattern splits plain text into sentences
+var sentencePattern = regexp.MustCompile(`[^.!?]+[.!?]*`)
+
+// freeTierPassages returns the text of a page that states its free tier, where the caveats of the
+// allowances are written: the sections whose heading names a free tier or whose prose or tables
+// list free tier items, and elsewhere only the paragraphs that mention a free allowance. Pages
+// without sections fall back to the sentences around mentions of a free allowance. Conditions are
+// extracted from these passages only, so that e.g. "GPUs are not included" under an unrelated
+// heading does not become a condition of the free tier.
+func freeTierPassages(page *Page) string {
+	if len(page.Sections) == 0 {
+		return sentencesAroundFreeTier(page.Text)
+	}
+
+	var passages []string
+	for _, section := range page.Sections {
+		text := strings.Join(section.Blocks, "\n")
+		if freeTierMention.MatchString(section.Heading) || len(ExtractFreeTierItems(text, section.Tables...)) > 0 {
+			passages = append(passages, section.Blocks...)
+			continue
+		}
+		for _, block := range section.Blocks {
+			if freeTierMention.MatchString(block) {
+				passages = append(passages, block)
+			}
+		}
+	}
+	return strings.Join(passages, "\n")
+}
+
+// sentencesAroundFreeTier returns the sentences of text that mention a free allowance, along
+// with the sentence before and after each of them
+func sentencesAroundFreeTier(text string) string {
+	sentences := sentencePattern.FindAllString(text, -1)
+	var passages []string
+	for i := range sentences {
+		for j := max(0, i-1); j <= min(len(sentences)-1, i+1); j++ {
+			if freeTierMention.MatchString(sentences[j]) {
+				passages = append(passages, strings.TrimSpace(sentences[i]))
+				break
+			}
+		}
+	}
+	return strings.Join(passages, " ")
+}
+
+// ExtractConditions returns the free tier conditions stated in documentation content
+func ExtractConditions(content string) []string {
+	var conditions []string
+	for _, pattern := range conditionPatterns {
+		if pattern.Regex.MatchString(content) && !slices.Contains(conditions, pattern.Condition) {
+			conditions = append(conditions, pattern.Condition)
+		}
+	}
+	return conditions
+}
+
+// RegionConditions describes the region restrictions of items, one condition per set of regions
+// (e.g. "Only applies in us-west1, us-central1, us-east1: e2-micro-vcpu-hours, e2-micro-ram-hours")
+func RegionConditions(items []FreeTierItem) []string {
+	var regionSets []string
+	resources := make(map[string][]string)
+	for _, item := range items {
+		if len(item.Regions) == 0 {
+			continue
+		}
+		regions := strings.Join(item.Regions, ", ")
+		if _, ok := resources[regions]; !ok {
+			regionSets = append(regionSets, regions)
+		}
+		resources[regions] = append(resources[regions], item.Resource)
+	}
+
+	conditions := make([]string, 0, len(regionSets))
+	for _, regions := range regionSets {
+		conditions = append(conditions, fmt.Sprintf("Only applies in %s: %s", regions, strings.Join(resources[regions], ", ")))
+	}
+	return conditions
+}
+
+// addConditions completes info.Conditions with the conditions stated in content, the scope of
+// the allowances and their region restrictions
+func (info *FreeTierInfo) addConditions(content string) {
+	conditions := append([]string(nil), info.Conditions...)
+	conditions = append(conditions, ExtractConditions(content)...)
+	switch info.Scope {
+	case "account":
+		conditions = append(conditions, ConditionPerBillingAccount)
+	case "project":
+		conditions = append(conditions, ConditionPerProject)
+	}
+	conditions = append(conditions, RegionConditions(info.Items)...)
+
+	info.Conditions = nil
+	for _, condition := range conditions {
+		// The page may state both scopes; the scope of the allowances decides
+		if (condition == ConditionPerBillingAccount && info.Scope == "project") ||
+			(condition == ConditionPerProject && info.Scope == "account") {
+			continue
+		}
+		if !slices.Contains(info.Conditions, condition) {
+			info.Conditions = append(info.Conditions, condition)
+		}
+	}
+}
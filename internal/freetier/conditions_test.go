@@ -0,0 +1,112 @@
+package freetier
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestExtractConditions(t *testing.T) {
+	tests := []struct {
+		content  string
+		expected []string
+	}{
+		{"Free tier usage is aggregated across all projects of a billing account.", []string{ConditionPerBillingAccount}},
+		{"Free tier usage is per billing account.", []string{ConditionPerBillingAccount}},
+		{"The free tier is applied per project.", []string{ConditionPerProject}},
+		{"The free tier is not available with committed use discounts.", []string{ConditionNoCommittedUse}},
+		{"Committed use discounts are not eligible for the free tier.", []string{ConditionNoCommittedUse}},
+		{"The free tier does not apply to request-based billing.", []string{ConditionNoRequestBased}},
+		{"The free tier doesn't apply to instance-based billing.", []string{ConditionNoInstanceBased}},
+		{"GPUs and TPUs are not included in the free tier offer.", []string{ConditionNoGPUs}},
+		{"Unused free tier usage does not roll over to the next month.", []string{ConditionNoCarryOver}},
+		{
+			"Usage is per billing account and does not apply to requests-based billing.",
+			[]string{ConditionPerBillingAccount, ConditionNoRequestBased},
+		},
+		{"The first 2 million requests per month are free.", nil},
+	}
+	for _, tt := range tests {
+		if got := ExtractConditions(tt.content); !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("ExtractConditions(%q) = %q, want %q", tt.content, got, tt.expected)
+		}
+	}
+}
+
+func TestFreeTierPassages(t *testing.T) {
+	page := &Page{Sections: []Section{
+		{Heading: "Cloud Run pricing", Blocks: []string{
+			"The first 2 million requests per month are free.",
+			"The free tier does not apply to instance-based billing.",
+		}},
+		{Heading: "GPU pricing", Blocks: []string{"GPUs are not included in committed use discounts."}},
+		{Heading: "Billing", Blocks: []string{
+			"Usage of all projects is billed together.",
+			"Free tier usage is per billing account.",
+		}},
+	}}
+
+	// The GPU caveat is not about the free tier
+	got := ExtractConditions(freeTierPassages(page))
+	expected := []string{ConditionPerBillingAccount, ConditionNoInstanceBased}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("conditions = %q, want %q", got, expected)
+	}
+
+	// Without sections, only the sentences around free tier mentions are used
+	page = &Page{Text: "GPUs are not included in sustained use discounts. Prices vary by region. " +
+		"Jobs are billed per second. The first 50 hours per month are free. Unused hours do not roll over."}
+	got = ExtractConditions(freeTierPassages(page))
+	if !reflect.DeepEqual(got, []string{ConditionNoCarryOver}) {
+		t.Errorf("conditions = %q, want only %q", got, ConditionNoCarryOver)
+	}
+}
+
+func TestRegionConditions(t *testing.T) {
+	items := []FreeTierItem{
+		{Resource: "e2-micro-vcpu-hours", Regions: []string{"us-west1", "us-central1"}},
+		{Resource: "egress"},
+		{Resource: "e2-micro-ram-hours", Regions: []string{"us-west1", "us-central1"}},
+		{Resource: "snapshot-storage", Regions: []string{"asia-east1"}},
+	}
+	expected := []string{
+		"Only applies in us-west1, us-central1: e2-micro-vcpu-hours, e2-micro-ram-hours",
+		"Only applies in asia-east1: snapshot-storage",
+	}
+	if got := RegionConditions(items); !reflect.DeepEqual(got, expected) {
+		t.Errorf("RegionConditions() = %q, want %q", got, expected)
+	}
+}
+
+func TestFreeTierInfo_AddConditions(t *testing.T) {
+	info := &FreeTierInfo{Scope: "project", Conditions: []string{ConditionNoGPUs}}
+
+	// The page states both scopes; the scope of the allowances wins and nothing is repeated
+	info.addConditions("Usage is per billing account. GPUs are not included. Free tier usage is per project.")
+	expected := []string{ConditionNoGPUs, ConditionPerProject}
+	if !reflect.DeepEqual(info.Conditions, expected) {
+		t.Errorf("Conditions = %q, want %q", info.Conditions, expected)
+	}
+}
+
+func TestDataset_LookupConditions(t *testing.T) {
+	info := DefaultDataset().Lookup("Compute Engine")
+	if info == nil {
+		t.Fatal("Lookup(Compute Engine) = nil")
+	}
+	for _, condition := range []string{
+		ConditionNoGPUs,
+		ConditionPerBillingAccount,
+		"Only applies in us-west1, us-central1, us-east1: e2-micro-vcpu-hours, e2-micro-ram-hours, standard-persistent-disk-storage",
+	} {
+		if !slices.Contains(info.Conditions, condition) {
+			t.Errorf("Conditions = %q, missing %q", info.Conditions, condition)
+		}
+	}
+
+	// Lookups return copies; the derived conditions are not stored in the dataset
+	info.Conditions = append(info.Conditions, "modified")
+	if again := DefaultDataset().Lookup("Compute Engine"); !reflect.DeepEqual(again.Conditions, info.Conditions[:len(info.Conditions)-1]) {
+		t.Errorf("Conditions = %q after modifying a lookup", again.Conditions)
+	}
+}
@@ -0,0 +1,108 @@
+package freetier
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// datasetJSON is the curated free tier dataset shipped with the server
+//
+//go:embed dataset.json
+var datasetJSON []byte
+
+// Dataset is a versioned set of curated free tiers. It is consulted before scraping documentation,
+// so that well-known free tiers are available offline and do not depend on page layouts.
+type Dataset struct {
+	Version  string           `json:"version"`
+	Services []DatasetService `json:"services"`
+}
+
+// DatasetService is the free tier of one service in a Dataset
+type DatasetService struct {
+	FreeTierInfo
+	// Aliases are other names the service is looked up by (e.g. "GKE" for "Kubernetes Engine")
+	Aliases []string `json:"aliases,omitempty"`
+}
+
+// ParseDataset parses and validates a dataset in JSON form
+func ParseDataset(data []byte) (*Dataset, error) {
+	var dataset Dataset
+	if err := json.Unmarshal(data, &dataset); err != nil {
+		return nil, fmt.Errorf("failed to parse free tier dataset: %w", err)
+	}
+	if dataset.Version == "" {
+		return nil, fmt.Errorf("free tier dataset has no version")
+	}
+
+	seen := make(map[string]string)
+	for _, service := range dataset.Services {
+		if service.ServiceName == "" || len(service.Items) == 0 {
+			return nil, fmt.Errorf("free tier dataset entry %q needs a service name and items", service.ServiceName)
+		}
+		for _, name := range append([]string{service.ServiceName}, service.Aliases...) {
+			key := normalizeServiceName(name)
+			if other, ok := seen[key]; ok {
+				return nil, fmt.Errorf("free tier dataset name %q is used by both %q and %q", name, other, service.ServiceName)
+			}
+			seen[key] = service.ServiceName
+		}
+		for _, item := range service.Items {
+			if item.Resource == "" || item.Amount <= 0 {
+				return nil, fmt.Errorf("free tier dataset entry %q has an item without resource or amount", service.ServiceName)
+			}
+		}
+	}
+	return &dataset, nil
+}
+
+// DefaultDataset returns the dataset embedded in the binary
+var DefaultDataset = sync.OnceValue(func() *Dataset {
+	dataset, err := ParseDataset(datasetJSON)
+	if err != nil {
+		panic(err)
+	}
+	return dataset
+})
+
+// Lookup returns the free tier of a service by name or alias, or nil if the dataset does not
+// cover the service. The result is a copy that callers may modify.
+func (d *Dataset) Lookup(serviceName string) *FreeTierInfo {
+	service := findService(d.Services, serviceName)
+	if service == nil {
+		return nil
+	}
+	info := service.clone()
+	info.addConditions("")
+	info.Source = SourceDataset
+	info.DatasetVersion = d.Version
+	return info
+}
+
+// findService returns the service with the given name or alias, or nil
+func findService(services []DatasetService, serviceName string) *DatasetService {
+	key := normalizeServiceName(serviceName)
+	for i := range services {
+		service := &services[i]
+		for _, name := range append([]string{service.ServiceName}, service.Aliases...) {
+			if normalizeServiceName(name) == key {
+				return service
+			}
+		}
+	}
+	return nil
+}
+
+// clone returns a copy of the service's free tier that shares no slices with it
+func (service *DatasetService) clone() *FreeTierInfo {
+	info := service.FreeTierInfo
+	info.Items = make([]FreeTierItem, len(service.Items))
+	for j, item := range service.Items {
+		item.SKUIDs = append([]string(nil), item.SKUIDs...)
+		item.Regions = append([]string(nil), item.Regions...)
+		info.Items[j] = item
+	}
+	info.Conditions = append([]string(nil), service.Conditions...)
+	return &info
+}
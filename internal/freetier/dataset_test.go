@@ -0,0 +1,109 @@
+package freetier
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nozomi-koborinai/gcp-cost-mcp-server/internal/pricing"
+)
+
+func TestDefaultDataset(t *testing.T) {
+	dataset := DefaultDataset()
+	if dataset.Version == "" {
+		t.Error("embedded dataset has no version")
+	}
+
+	for _, service := range dataset.Services {
+		if service.SourceURL == "" {
+			t.Errorf("%s has no source URL", service.ServiceName)
+		}
+		if service.Scope != "account" && service.Scope != "project" {
+			t.Errorf("%s has scope %q", service.ServiceName, service.Scope)
+		}
+		for _, item := range service.Items {
+			switch service.ItemPeriod(&item) {
+			case PeriodDay, PeriodMonth, PeriodAlways:
+			default:
+				t.Errorf("%s %s has period %q", service.ServiceName, item.Resource, service.ItemPeriod(&item))
+			}
+			// Every unit must be a Billing unit the estimator can convert
+			if _, err := ItemUnit(&item); err != nil {
+				t.Errorf("%s %s: %v", service.ServiceName, item.Resource, err)
+			}
+		}
+	}
+}
+
+func TestDataset_Lookup(t *testing.T) {
+	dataset := DefaultDataset()
+
+	info := dataset.Lookup("cloud run")
+	if info == nil {
+		t.Fatal("Lookup(cloud run) = nil")
+	}
+	if info.ServiceName != "Cloud Run" || info.Source != SourceDataset || info.DatasetVersion != dataset.Version {
+		t.Errorf("Lookup(cloud run) = %+v", info)
+	}
+
+	// Aliases resolve to the same entry
+	if gke := dataset.Lookup("GKE"); gke == nil || gke.ServiceName != "Kubernetes Engine" {
+		t.Errorf("Lookup(GKE) = %+v, want Kubernetes Engine", gke)
+	}
+	if dataset.Lookup("Unknown Service") != nil {
+		t.Error("Lookup(Unknown Service) should be nil")
+	}
+
+	// Results are copies
+	info.Items[0].Amount = 1
+	if dataset.Lookup("Cloud Run").Items[0].Amount == 1 {
+		t.Error("modifying a lookup result changed the dataset")
+	}
+}
+
+func TestParseDataset_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"not json":        `{`,
+		"no version":      `{"services": []}`,
+		"no items":        `{"version": "1", "services": [{"service_name": "A"}]}`,
+		"no amount":       `{"version": "1", "services": [{"service_name": "A", "items": [{"resource": "r"}]}]}`,
+		"duplicate alias": `{"version": "1", "services": [{"service_name": "A", "items": [{"resource": "r", "amount": 1}]}, {"service_name": "B", "aliases": ["a"], "items": [{"resource": "r", "amount": 1}]}]}`,
+	}
+	for name, data := range tests {
+		if _, err := ParseDataset([]byte(data)); err == nil {
+			t.Errorf("%s: ParseDataset succeeded, want error", name)
+		}
+	}
+}
+
+func TestService_GetFreeTierFromDataset(t *testing.T) {
+	svc := NewService()
+
+	// Covered services are answered from the dataset without searching documentation
+	info, err := svc.GetFreeTier(context.Background(), "Cloud Firestore")
+	if err != nil || info == nil {
+		t.Fatalf("GetFreeTier() = %v, %v", info, err)
+	}
+	if info.Source != SourceDataset {
+		t.Errorf("Source = %q, want %q", info.Source, SourceDataset)
+	}
+
+	// Item periods override the service period
+	var storage, reads *FreeTierItem
+	for i := range info.Items {
+		switch info.Items[i].Resource {
+		case "storage":
+			storage = &info.Items[i]
+		case "document-reads":
+			reads = &info.Items[i]
+		}
+	}
+	if storage == nil || reads == nil {
+		t.Fatalf("Firestore items = %+v", info.Items)
+	}
+	if info.ItemPeriod(storage) != PeriodMonth || info.ItemPeriod(reads) != PeriodDay {
+		t.Errorf("periods = %q, %q, want month and day", info.ItemPeriod(storage), info.ItemPeriod(reads))
+	}
+	if amount, _ := AllowanceForPeriod(info, reads, pricing.UsageMonths(1)); amount.String() != "1520833.333333333333333333" {
+		t.Errorf("monthly reads allowance = %s", amount)
+	}
+}
@@ -0,0 +1,129 @@
+package freetier
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/nozomi-koborinai/gcp-cost-mcp-server/internal/money"
+)
+
+// LedgerKey identifies one free tier allowance shared by line items
+type LedgerKey struct {
+	Service  string `json:"service"`
+	Resource string `json:"resource"`
+	// Scope is "account", or "project:<project>" for project-scoped allowances
+	Scope string `json:"scope"`
+}
+
+// NewLedgerKey returns the key of a free tier item. Account-scoped allowances are shared by all
+// line items of the service; project-scoped allowances only by line items of the same project.
+func NewLedgerKey(serviceName string, item *FreeTierItem, scope string, project string) LedgerKey {
+	key := LedgerKey{
+		Service:  normalizeServiceName(serviceName),
+		Resource: strings.ToLower(strings.TrimSpace(item.Resource)),
+		Scope:    "account",
+	}
+	if strings.EqualFold(scope, "project") {
+		key.Scope = "project:" + strings.ToLower(strings.TrimSpace(project))
+	}
+	return key
+}
+
+// Allocation is the part of an allowance granted to one line item
+type Allocation struct {
+	LineItem  string        `json:"line_item"`
+	Requested money.Decimal `json:"requested"`
+	Granted   money.Decimal `json:"granted"`
+}
+
+// AllowanceReport describes how one allowance was allocated
+type AllowanceReport struct {
+	LedgerKey
+	Total       money.Decimal `json:"total"`
+	Used        money.Decimal `json:"used"`
+	Remaining   money.Decimal `json:"remaining"`
+	Allocations []Allocation  `json:"allocations"`
+}
+
+// allowance is the state of one allowance in a Ledger
+type allowance struct {
+	total       money.Decimal
+	used        money.Decimal
+	allocations []Allocation
+}
+
+// Ledger tracks free tier allowances consumed across the line items of an estimate session,
+// so that an allowance shared by several line items is only granted once in total
+type Ledger struct {
+	allowances map[LedgerKey]*allowance
+	mutex      sync.Mutex
+}
+
+// NewLedger creates an empty Ledger
+func NewLedger() *Ledger {
+	return &Ledger{allowances: make(map[LedgerKey]*allowance)}
+}
+
+// Remaining returns the unused amount of an allowance. total is the allowance's full amount,
+// used when the allowance has not been seen before.
+func (l *Ledger) Remaining(key LedgerKey, total money.Decimal) money.Decimal {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	a, ok := l.allowances[key]
+	if !ok {
+		return total
+	}
+	return a.total.Sub(a.used)
+}
+
+// Allocate grants up to requested from an allowance to a line item and returns the granted amount.
+// total is the allowance's full amount; the first allocation of a key fixes it.
+func (l *Ledger) Allocate(key LedgerKey, total money.Decimal, lineItem string, requested money.Decimal) money.Decimal {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	a, ok := l.allowances[key]
+	if !ok {
+		a = &allowance{total: money.Max(money.Zero, total), used: money.Zero}
+		l.allowances[key] = a
+	}
+
+	granted := money.Max(money.Zero, money.Min(requested, a.total.Sub(a.used)))
+	a.used = a.used.Add(granted)
+	a.allocations = append(a.allocations, Allocation{
+		LineItem:  lineItem,
+		Requested: requested,
+		Granted:   granted,
+	})
+	return granted
+}
+
+// Report returns the allocation of every allowance, sorted by service, resource and scope
+func (l *Ledger) Report() []AllowanceReport {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	reports := make([]AllowanceReport, 0, len(l.allowances))
+	for key, a := range l.allowances {
+		reports = append(reports, AllowanceReport{
+			LedgerKey:   key,
+			Total:       a.total,
+			Used:        a.used,
+			Remaining:   a.total.Sub(a.used),
+			Allocations: append([]Allocation(nil), a.allocations...),
+		})
+	}
+	sort.Slice(reports, func(i, j int) bool {
+		a, b := reports[i].LedgerKey, reports[j].LedgerKey
+		if a.Service != b.Service {
+			return a.Service < b.Service
+		}
+		if a.Resource != b.Resource {
+			return a.Resource < b.Resource
+		}
+		return a.Scope < b.Scope
+	})
+	return reports
+}
@@ -0,0 +1,72 @@
+package freetier
+
+import (
+	"testing"
+
+	"github.com/nozomi-koborinai/gcp-cost-mcp-server/internal/money"
+)
+
+func TestNewLedgerKey(t *testing.T) {
+	item := &FreeTierItem{Resource: "vCPU-seconds", Amount: 180000}
+
+	account := NewLedgerKey(" Cloud Run", item, "account", "web")
+	if account != (LedgerKey{Service: "cloud-run", Resource: "vcpu-seconds", Scope: "account"}) {
+		t.Errorf("account key = %+v", account)
+	}
+	// Account-scoped allowances ignore the project
+	if other := NewLedgerKey("cloud run", item, "Account", "batch"); other != account {
+		t.Errorf("account keys differ: %+v vs %+v", other, account)
+	}
+
+	web := NewLedgerKey("Cloud Run", item, "project", "web")
+	batch := NewLedgerKey("Cloud Run", item, "project", "batch")
+	if web.Scope != "project:web" || web == batch {
+		t.Errorf("project keys = %+v, %+v", web, batch)
+	}
+}
+
+func TestLedger_Allocate(t *testing.T) {
+	ledger := NewLedger()
+	key := LedgerKey{Service: "cloud-run", Resource: "vcpu-seconds", Scope: "account"}
+	total := money.FromInt(180000)
+
+	if got := ledger.Remaining(key, total); got.Cmp(total) != 0 {
+		t.Errorf("Remaining before allocation = %s, want %s", got, total)
+	}
+
+	allocations := []struct {
+		lineItem  string
+		requested int64
+		want      int64
+	}{
+		{"api", 100000, 100000},
+		{"worker", 100000, 80000},
+		{"batch", 50000, 0},
+	}
+	for _, a := range allocations {
+		if got := ledger.Allocate(key, total, a.lineItem, money.FromInt(a.requested)); got.Cmp(money.FromInt(a.want)) != 0 {
+			t.Errorf("Allocate(%s, %d) = %s, want %d", a.lineItem, a.requested, got, a.want)
+		}
+	}
+	if got := ledger.Remaining(key, total); !got.IsZero() {
+		t.Errorf("Remaining after allocation = %s, want 0", got)
+	}
+
+	// A separate allowance is unaffected
+	other := LedgerKey{Service: "cloud-run", Resource: "gib-seconds", Scope: "account"}
+	if got := ledger.Allocate(other, money.FromInt(360000), "api", money.FromInt(1000)); got.Cmp(money.FromInt(1000)) != 0 {
+		t.Errorf("Allocate(other) = %s, want 1000", got)
+	}
+
+	report := ledger.Report()
+	if len(report) != 2 || report[0].Resource != "gib-seconds" || report[1].Resource != "vcpu-seconds" {
+		t.Fatalf("Report = %+v", report)
+	}
+	vcpu := report[1]
+	if vcpu.Used.Cmp(total) != 0 || !vcpu.Remaining.IsZero() || len(vcpu.Allocations) != 3 {
+		t.Errorf("vcpu report = %+v", vcpu)
+	}
+	if vcpu.Allocations[1].LineItem != "worker" || vcpu.Allocations[1].Granted.Cmp(money.FromInt(80000)) != 0 {
+		t.Errorf("worker allocation = %+v", vcpu.Allocations[1])
+	}
+}
@@ -0,0 +1,187 @@
+package freetier
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// SKUPredicate selects the SKUs a free tier item applies to by display name and category.
+// All non-empty conditions must hold; matching is case-insensitive.
+type SKUPredicate struct {
+	// DisplayNameContains requires the display name to contain at least one of these
+	DisplayNameContains []string `json:"display_name_contains,omitempty"`
+	// DisplayNameExcludes requires the display name to contain none of these
+	DisplayNameExcludes []string `json:"display_name_excludes,omitempty"`
+	// Categories requires one of the SKU's taxonomy categories to equal one of these
+	Categories []string `json:"categories,omitempty"`
+}
+
+// Matches reports whether a SKU satisfies the predicate
+func (p *SKUPredicate) Matches(sku SKU) bool {
+	name := strings.ToLower(sku.DisplayName)
+	if name == "" {
+		return false
+	}
+	if len(p.DisplayNameContains) > 0 && !slices.ContainsFunc(p.DisplayNameContains, func(s string) bool {
+		return strings.Contains(name, strings.ToLower(s))
+	}) {
+		return false
+	}
+	if slices.ContainsFunc(p.DisplayNameExcludes, func(s string) bool {
+		return strings.Contains(name, strings.ToLower(s))
+	}) {
+		return false
+	}
+	if len(p.Categories) > 0 && !slices.ContainsFunc(p.Categories, func(c string) bool {
+		return slices.ContainsFunc(sku.Categories, func(sc string) bool { return strings.EqualFold(c, sc) })
+	}) {
+		return false
+	}
+	return true
+}
+
+// SKU describes the SKU a free tier is matched against. DisplayName and Categories may be empty
+// when the SKU's details are unknown, in which case only SKU IDs and units are matched.
+type SKU struct {
+	ID          string
+	DisplayName string
+	Categories  []string
+	// Unit is the SKU's usage unit (e.g. "s", "GiBy.mo")
+	Unit string
+}
+
+// How a free tier item was matched to a SKU, reported in SKUMatch.By
+const (
+	MatchedBySKUID     = "sku_id"
+	MatchedByPredicate = "predicate"
+	MatchedByUnit      = "unit"
+)
+
+// SKUMatch is the free tier item that applies to a SKU
+type SKUMatch struct {
+	// Item is nil if no item applies, or if several did and the choice would be a guess
+	Item *FreeTierItem
+	By   string
+	// Warning explains why no item was applied although some looked applicable
+	Warning string
+}
+
+// bound reports whether an item applies only to specific SKUs
+func (item *FreeTierItem) bound() bool {
+	return len(item.SKUIDs) > 0 || item.Match != nil
+}
+
+// HasSKUBindings reports whether any item is bound to specific SKUs, so that matching needs the
+// SKU's display name and categories
+func (info *FreeTierInfo) HasSKUBindings() bool {
+	for i := range info.Items {
+		if info.Items[i].bound() {
+			return true
+		}
+	}
+	return false
+}
+
+// HasRegionRestrictions reports whether any item only applies in specific regions, so that
+// applying it needs the usage's region
+func (info *FreeTierInfo) HasRegionRestrictions() bool {
+	for i := range info.Items {
+		if len(info.Items[i].Regions) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchSKU finds the free tier item that applies to a SKU. Items bound to SKU IDs are matched
+// first, then items bound by predicates; items bound to other SKUs never apply. Unbound items
+// (e.g. scraped from documentation) are matched by usage unit as a last resort.
+//
+// When several items match at the same step, none is applied and SKUMatch.Warning names them,
+// because deducting an arbitrary one could apply e.g. a vCPU allowance to memory usage.
+func MatchSKU(info *FreeTierInfo, sku SKU) SKUMatch {
+	if info == nil || len(info.Items) == 0 {
+		return SKUMatch{}
+	}
+
+	var byID, byPredicate, unbound []*FreeTierItem
+	predicatesSkipped := false
+	for i := range info.Items {
+		item := &info.Items[i]
+		switch {
+		case slices.Contains(item.SKUIDs, sku.ID):
+			byID = append(byID, item)
+		case item.Match != nil && sku.DisplayName == "":
+			predicatesSkipped = true
+		case item.Match != nil && item.Match.Matches(sku):
+			byPredicate = append(byPredicate, item)
+		case !item.bound():
+			unbound = append(unbound, item)
+		}
+	}
+
+	if len(byID) > 0 {
+		return choose(byID, MatchedBySKUID, sku)
+	}
+	if len(byPredicate) > 0 {
+		return choose(byPredicate, MatchedByPredicate, sku)
+	}
+
+	// Prefer items whose amount converts to the SKU's unit
+	var candidates, convertibleCandidates []*FreeTierItem
+	for _, item := range unitCandidates(unbound, sku.Unit) {
+		candidates = append(candidates, item)
+		if convertible(item, sku.Unit) {
+			convertibleCandidates = append(convertibleCandidates, item)
+		}
+	}
+	if len(convertibleCandidates) > 0 {
+		candidates = convertibleCandidates
+	}
+	if len(candidates) > 0 {
+		return choose(candidates, MatchedByUnit, sku)
+	}
+
+	if predicatesSkipped {
+		return SKUMatch{Warning: fmt.Sprintf("free tier items of %s apply to specific SKUs, but the details of SKU %s are unknown", info.ServiceName, sku.ID)}
+	}
+	return SKUMatch{}
+}
+
+// choose returns the only matching item, or a warning if the match is ambiguous
+func choose(items []*FreeTierItem, by string, sku SKU) SKUMatch {
+	if len(items) == 1 {
+		return SKUMatch{Item: items[0], By: by}
+	}
+	resources := make([]string, len(items))
+	for i, item := range items {
+		resources[i] = item.Resource
+	}
+	return SKUMatch{
+		By:      by,
+		Warning: fmt.Sprintf("SKU %s matches several free tier items (%s) by %s; none was applied", sku.ID, strings.Join(resources, ", "), by),
+	}
+}
+
+// unitCandidates returns the items whose resource name suggests they are measured in usageUnit,
+// or whose unit equals it
+func unitCandidates(items []*FreeTierItem, usageUnit string) []*FreeTierItem {
+	var candidates []*FreeTierItem
+	possibleResources, ok := unitMapping[usageUnit]
+	for _, item := range items {
+		if !ok {
+			if strings.EqualFold(item.Unit, usageUnit) || convertible(item, usageUnit) {
+				candidates = append(candidates, item)
+			}
+			continue
+		}
+		for _, resource := range possibleResources {
+			if strings.Contains(strings.ToLower(item.Resource), strings.ToLower(resource)) {
+				candidates = append(candidates, item)
+				break
+			}
+		}
+	}
+	return candidates
+}
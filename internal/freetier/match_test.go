@@ -0,0 +1,185 @@
+package freetier
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nozomi-koborinai/gcp-cost-mcp-server/internal/money"
+)
+
+func TestSKUPredicate_Matches(t *testing.T) {
+	p := &SKUPredicate{
+		DisplayNameContains: []string{"Storage PD Capacity"},
+		DisplayNameExcludes: []string{"SSD"},
+		Categories:          []string{"Storage"},
+	}
+	tests := []struct {
+		sku  SKU
+		want bool
+	}{
+		{SKU{DisplayName: "Storage PD Capacity in Iowa", Categories: []string{"Storage"}}, true},
+		{SKU{DisplayName: "storage pd capacity", Categories: []string{"storage"}}, true},
+		{SKU{DisplayName: "SSD backed Storage PD Capacity", Categories: []string{"Storage"}}, false},
+		{SKU{DisplayName: "Storage PD Capacity", Categories: []string{"Compute"}}, false},
+		{SKU{DisplayName: "E2 Instance Core", Categories: []string{"Storage"}}, false},
+		{SKU{}, false},
+	}
+	for _, tt := range tests {
+		if got := p.Matches(tt.sku); got != tt.want {
+			t.Errorf("Matches(%+v) = %v, want %v", tt.sku, got, tt.want)
+		}
+	}
+}
+
+func TestMatchSKU(t *testing.T) {
+	bound := &FreeTierInfo{
+		ServiceName: "Cloud Run",
+		Items: []FreeTierItem{
+			{Resource: "vCPU-seconds", Amount: 180000, Unit: "s", Match: &SKUPredicate{DisplayNameContains: []string{"CPU"}}},
+			{Resource: "GiB-seconds", Amount: 360000, Unit: "GiBy.s", Match: &SKUPredicate{DisplayNameContains: []string{"Memory"}}},
+			{Resource: "requests", Amount: 2000000, Unit: "count", SKUIDs: []string{"REQ-1"}},
+		},
+	}
+	unbound := &FreeTierInfo{
+		ServiceName: "Cloud Storage",
+		Items: []FreeTierItem{
+			{Resource: "class-a-operations", Amount: 5000, Unit: "count"},
+			{Resource: "class-b-operations", Amount: 50000, Unit: "count"},
+			{Resource: "storage", Amount: 5, Unit: "GiB"},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		info     *FreeTierInfo
+		sku      SKU
+		resource string
+		by       string
+		warning  string
+	}{
+		{"SKU ID", bound, SKU{ID: "REQ-1", DisplayName: "Requests", Unit: "count"}, "requests", MatchedBySKUID, ""},
+		{"predicate", bound, SKU{ID: "CPU-1", DisplayName: "CPU Allocation Time", Unit: "s"}, "vCPU-seconds", MatchedByPredicate, ""},
+		// A memory SKU billed in seconds must not receive the vCPU allowance
+		{"predicate, same unit", bound, SKU{ID: "MEM-1", DisplayName: "Memory Allocation Time", Unit: "s"}, "GiB-seconds", MatchedByPredicate, ""},
+		{"bound to other SKUs", bound, SKU{ID: "REQ-2", DisplayName: "Requests", Unit: "count"}, "", "", ""},
+		{"unknown SKU details", bound, SKU{ID: "CPU-1", Unit: "s"}, "", "", "details of SKU CPU-1 are unknown"},
+		{"unit", unbound, SKU{ID: "GCS-1", Unit: "GiBy.mo"}, "storage", MatchedByUnit, ""},
+		{"ambiguous unit", unbound, SKU{ID: "GCS-2", Unit: "count"}, "", MatchedByUnit, "class-a-operations, class-b-operations"},
+		{"no match", unbound, SKU{ID: "GCS-3", Unit: "h"}, "", "", ""},
+		{"nil", nil, SKU{ID: "X", Unit: "s"}, "", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match := MatchSKU(tt.info, tt.sku)
+			resource := ""
+			if match.Item != nil {
+				resource = match.Item.Resource
+			}
+			if resource != tt.resource || match.By != tt.by {
+				t.Errorf("MatchSKU() = %q by %q, want %q by %q", resource, match.By, tt.resource, tt.by)
+			}
+			if (tt.warning == "") != (match.Warning == "") || !strings.Contains(match.Warning, tt.warning) {
+				t.Errorf("Warning = %q, want %q", match.Warning, tt.warning)
+			}
+		})
+	}
+}
+
+func TestDataset_PredicatesAreUnambiguous(t *testing.T) {
+	// Typical SKU names must match exactly one item of their service
+	tests := []struct {
+		service     string
+		displayName string
+		unit        string
+		resource    string
+	}{
+		{"Cloud Run", "CPU Allocation Time", "s", "vCPU-seconds"},
+		{"Cloud Run", "Memory Allocation Time", "GiBy.s", "GiB-seconds"},
+		{"Cloud Run", "Requests", "count", "requests"},
+		{"Cloud Storage", "Class A Operations Standard Storage", "count", "class-a-operations"},
+		{"Cloud Storage", "Standard Storage US Regional", "GiBy.mo", "standard-storage"},
+		{"Compute Engine", "E2 Instance Core running in Americas", "h", "e2-micro-vcpu-hours"},
+		{"Compute Engine", "E2 Instance Ram running in Americas", "GiBy.h", "e2-micro-ram-hours"},
+		{"Compute Engine", "Storage PD Capacity", "GiBy.mo", "standard-persistent-disk-storage"},
+		{"Cloud Firestore", "Cloud Firestore Read Ops", "count", "document-reads"},
+		{"Cloud Pub/Sub", "Message Delivery Basic", "TiBy", "message-delivery"},
+		{"BigQuery", "Analysis", "TiBy", "query-processing"},
+		{"BigQuery", "Active Logical Storage", "GiBy.mo", "storage"},
+		{"BigQuery", "Long-Term Physical Storage", "GiBy.mo", "storage"},
+		{"Kubernetes Engine", "Autopilot Kubernetes Clusters", "h", "cluster-management-hours"},
+	}
+	for _, tt := range tests {
+		info := DefaultDataset().Lookup(tt.service)
+		match := MatchSKU(info, SKU{ID: "X", DisplayName: tt.displayName, Unit: tt.unit})
+		if match.Item == nil || match.Item.Resource != tt.resource {
+			t.Errorf("%s %q matched %+v, want %s", tt.service, tt.displayName, match, tt.resource)
+		}
+	}
+}
+
+func TestDataset_PredicatesExcludeOtherSKUs(t *testing.T) {
+	// SKUs whose names resemble a free tier item but that the free tier does not cover
+	tests := []struct {
+		service     string
+		displayName string
+		unit        string
+	}{
+		{"BigQuery", "BigQuery Storage API - Read", "TiBy"},
+		{"BigQuery", "BigQuery Storage API - Write", "GiBy"},
+		{"BigQuery", "BI Engine Analysis", "TiBy"},
+		{"Cloud Storage", "Nearline Class A Operations", "count"},
+		{"Cloud Storage", "Multi-Region Standard Class B Operations", "count"},
+		{"Cloud Storage", "Standard Storage US Multi-Region", "GiBy.mo"},
+		{"Compute Engine", "Commitment v1: E2 Cpu in Americas for 1 Year", "h"},
+		{"Compute Engine", "Network Inter Zone Egress", "GiBy"},
+		{"Kubernetes Engine", "Regional Kubernetes Clusters", "h"},
+	}
+	for _, tt := range tests {
+		info := DefaultDataset().Lookup(tt.service)
+		if match := MatchSKU(info, SKU{ID: "X", DisplayName: tt.displayName, Unit: tt.unit}); match.Item != nil {
+			t.Errorf("%s %q matched %s, want no item", tt.service, tt.displayName, match.Item.Resource)
+		}
+	}
+}
+
+func TestDataset_SKUIDs(t *testing.T) {
+	// Well-known SKUs are bound by ID, even when their display name is unknown
+	tests := []struct {
+		service  string
+		skuID    string
+		resource string
+	}{
+		{"BigQuery", "F17B-412E-CB64", "query-processing"},
+		{"Compute Engine", "CF4E-A0C7-E3BF", "e2-micro-vcpu-hours"},
+		{"Compute Engine", "F449-33EC-A5EF", "e2-micro-ram-hours"},
+	}
+	for _, tt := range tests {
+		match := MatchSKU(DefaultDataset().Lookup(tt.service), SKU{ID: tt.skuID})
+		if match.Item == nil || match.Item.Resource != tt.resource || match.By != MatchedBySKUID {
+			t.Errorf("%s %s matched %+v, want %s by SKU ID", tt.service, tt.skuID, match, tt.resource)
+		}
+	}
+}
+
+func TestDataset_PubSubMessageDelivery(t *testing.T) {
+	info := DefaultDataset().Lookup("Pub/Sub")
+
+	// Throughput is billed in TiBy; the 10 GiB allowance is converted to it
+	match := MatchSKU(info, SKU{ID: "X", DisplayName: "Message Delivery Basic", Unit: "TiBy"})
+	if match.Item == nil || match.By != MatchedByPredicate {
+		t.Fatalf("MatchSKU() = %+v, want message-delivery by predicate", match)
+	}
+	factor, err := ConversionFactor(match.Item, "TiBy")
+	if err != nil {
+		t.Fatalf("ConversionFactor() failed: %v", err)
+	}
+	if free := money.FromFloat(match.Item.Amount).Mul(factor); free.String() != "0.009765625" {
+		t.Errorf("free throughput = %s TiBy, want 10/1024", free)
+	}
+
+	// Message storage is not throughput
+	if match := MatchSKU(info, SKU{ID: "Y", DisplayName: "Topics message backlog", Unit: "GiBy.mo"}); match.Item != nil {
+		t.Errorf("MatchSKU(backlog storage) = %+v, want no item", match)
+	}
+}
@@ -0,0 +1,193 @@
+package freetier
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// PageFinder finds documentation pages that describe a service's pricing and free tier
+type PageFinder interface {
+	FindPages(ctx context.Context, serviceName string, limit int) ([]SearchResult, error)
+}
+
+// PageFetcher fetches a documentation page and returns its text and tables
+type PageFetcher interface {
+	FetchPage(ctx context.Context, pageURL string) (*Page, error)
+}
+
+// Ensure the built-in backends implement the interfaces
+var (
+	_ PageFinder  = (*DuckDuckGoClient)(nil)
+	_ PageFetcher = (*GCPDocScraperClient)(nil)
+	_ PageFinder  = (*StaticPageFinder)(nil)
+	_ PageFinder  = (*LocalPages)(nil)
+	_ PageFetcher = (*LocalPages)(nil)
+)
+
+// FindPages searches cloud.google.com for the pricing pages of a service
+func (c *DuckDuckGoClient) FindPages(ctx context.Context, serviceName string, limit int) ([]SearchResult, error) {
+	results, err := c.Search(ctx, fmt.Sprintf("site:cloud.google.com %s pricing", serviceName), limit)
+	if err != nil {
+		return nil, fmt.Errorf("search failed: %w", err)
+	}
+
+	var pages []SearchResult
+	for _, result := range results {
+		// Only process cloud.google.com pricing pages
+		if !strings.Contains(result.URL, "cloud.google.com") {
+			continue
+		}
+		if !strings.Contains(strings.ToLower(result.URL), "pricing") &&
+			!strings.Contains(strings.ToLower(result.Title), "pricing") {
+			continue
+		}
+		pages = append(pages, result)
+	}
+	return pages, nil
+}
+
+// StaticPageFinder finds pages in a fixed map from service name to page URLs
+type StaticPageFinder struct {
+	pages map[string][]string
+}
+
+// NewStaticPageFinder creates a StaticPageFinder. Service names are matched case-insensitively.
+func NewStaticPageFinder(pages map[string][]string) *StaticPageFinder {
+	f := &StaticPageFinder{pages: make(map[string][]string, len(pages))}
+	for name, urls := range pages {
+		key := normalizeServiceName(name)
+		f.pages[key] = append(f.pages[key], urls...)
+	}
+	return f
+}
+
+// LoadStaticPageFinder reads a StaticPageFinder from a JSON file mapping service names to URLs,
+// e.g. {"Cloud Run": ["https://cloud.google.com/run/pricing"]}
+func LoadStaticPageFinder(filename string) (*StaticPageFinder, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read page URL map: %w", err)
+	}
+	var pages map[string][]string
+	if err := json.Unmarshal(data, &pages); err != nil {
+		return nil, fmt.Errorf("failed to parse page URL map: %w", err)
+	}
+	return NewStaticPageFinder(pages), nil
+}
+
+// FindPages returns the URLs configured for the service
+func (f *StaticPageFinder) FindPages(ctx context.Context, serviceName string, limit int) ([]SearchResult, error) {
+	var results []SearchResult
+	for _, pageURL := range f.pages[normalizeServiceName(serviceName)] {
+		if len(results) >= limit {
+			break
+		}
+		results = append(results, SearchResult{URL: pageURL, Title: serviceName + " Pricing"})
+	}
+	return results, nil
+}
+
+// LocalPages serves documentation pages saved as HTML files in a directory, so that free tiers
+// can be extracted offline. A page is stored under its URL path with an ".html" extension
+// (https://cloud.google.com/run/pricing is run/pricing.html), or under the service name
+// (cloud-run.html).
+type LocalPages struct {
+	dir string
+}
+
+// NewLocalPages creates a LocalPages serving the HTML files in dir
+func NewLocalPages(dir string) *LocalPages {
+	return &LocalPages{dir: dir}
+}
+
+// FindPages returns the saved pages of a service: the page named after the service, and the
+// saved copies of its well-known pricing pages
+func (p *LocalPages) FindPages(ctx context.Context, serviceName string, limit int) ([]SearchResult, error) {
+	var results []SearchResult
+	seen := make(map[string]bool)
+	add := func(pageURL, file string) {
+		// URLs that differ only by a trailing slash are the same file
+		if len(results) >= limit || seen[file] {
+			return
+		}
+		seen[file] = true
+		if info, err := os.Stat(filepath.Join(p.dir, filepath.FromSlash(file))); err == nil && !info.IsDir() {
+			results = append(results, SearchResult{URL: pageURL, Title: serviceName + " Pricing"})
+		}
+	}
+
+	name := normalizeServiceName(serviceName)
+	add("file:///"+name+".html", name+".html")
+	for _, pageURL := range generatePricingURLs(serviceName) {
+		if file, err := p.pageFile(pageURL); err == nil {
+			add(pageURL, file)
+		}
+	}
+	return results, nil
+}
+
+// FetchAsText reads a saved page and returns its text content
+func (p *LocalPages) FetchAsText(ctx context.Context, pageURL string) (string, error) {
+	page, err := p.FetchPage(ctx, pageURL)
+	if err != nil {
+		return "", err
+	}
+	return page.Text, nil
+}
+
+// FetchPage reads a saved page and returns its text and tables. pageURL is either a
+// documentation URL or a file:/// URL relative to the directory, as returned by FindPages.
+func (p *LocalPages) FetchPage(ctx context.Context, pageURL string) (*Page, error) {
+	file, err := p.pageFile(pageURL)
+	if err != nil {
+		return nil, err
+	}
+
+	// os.Root keeps reads inside the directory, whatever the URL path contains
+	root, err := os.OpenRoot(p.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open pages directory: %w", err)
+	}
+	defer root.Close()
+
+	f, err := root.Open(filepath.FromSlash(file))
+	if err != nil {
+		return nil, fmt.Errorf("page %s is not saved in %s: %w", pageURL, p.dir, err)
+	}
+	defer f.Close()
+
+	body, err := io.ReadAll(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read page: %w", err)
+	}
+
+	page, err := parsePage(string(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract text: %w", err)
+	}
+	return page, nil
+}
+
+// pageFile returns the slash-separated path of a page's file relative to the directory
+func (p *LocalPages) pageFile(pageURL string) (string, error) {
+	u, err := url.Parse(pageURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid page URL %q: %w", pageURL, err)
+	}
+
+	file := strings.Trim(path.Clean("/"+u.Path), "/")
+	if file == "" {
+		return "", fmt.Errorf("page URL %q has no path", pageURL)
+	}
+	if u.Scheme != "file" && !strings.HasSuffix(file, ".html") {
+		file += ".html"
+	}
+	return file, nil
+}
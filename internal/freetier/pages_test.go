@@ -0,0 +1,135 @@
+package freetier
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const savedRunPricing = `<html><body><h1>Cloud Run pricing</h1>
+<p>Free tier: 180,000 vCPU-seconds per month free.</p>
+<p>The first 2 million requests per month are free. Free tier usage is per billing account.</p>
+<table><tr><th>Resource</th><th>Free tier</th><th>Price above free tier</th></tr>
+<tr><td>Memory</td><td>360,000</td><td>$0.0000025 / GiB-second</td></tr></table>
+</body></html>`
+
+// writePage saves a page under dir
+func writePage(t *testing.T, dir, name, content string) {
+	t.Helper()
+	filename := filepath.Join(dir, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(filename), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filename, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStaticPageFinder(t *testing.T) {
+	finder := NewStaticPageFinder(map[string][]string{
+		"Cloud Run": {"https://cloud.google.com/run/pricing", "https://cloud.google.com/run/docs"},
+	})
+
+	results, err := finder.FindPages(context.Background(), "cloud run", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || results[0].URL != "https://cloud.google.com/run/pricing" {
+		t.Errorf("FindPages() = %+v", results)
+	}
+	if results, _ := finder.FindPages(context.Background(), "BigQuery", 3); len(results) != 0 {
+		t.Errorf("FindPages(BigQuery) = %+v, want none", results)
+	}
+}
+
+func TestLoadStaticPageFinder(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "pages.json")
+	if err := os.WriteFile(filename, []byte(`{"BigQuery": ["https://cloud.google.com/bigquery/pricing"]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	finder, err := LoadStaticPageFinder(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if results, _ := finder.FindPages(context.Background(), "bigquery", 3); len(results) != 1 {
+		t.Errorf("FindPages() = %+v", results)
+	}
+
+	if _, err := LoadStaticPageFinder(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
+
+func TestLocalPages(t *testing.T) {
+	dir := t.TempDir()
+	writePage(t, dir, "run/pricing.html", savedRunPricing)
+	writePage(t, dir, "secret-manager.html", "<p>The first 6 active secret versions are free.</p>")
+	pages := NewLocalPages(dir)
+	ctx := context.Background()
+
+	// Pages are found under the URL path of well-known pricing pages and under the service name
+	results, err := pages.FindPages(ctx, "Cloud Run", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || results[0].URL != "https://cloud.google.com/run/pricing" {
+		t.Fatalf("FindPages(Cloud Run) = %+v", results)
+	}
+	text, err := pages.FetchAsText(ctx, results[0].URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(text, "180,000 vCPU-seconds") {
+		t.Errorf("FetchAsText() = %q", text)
+	}
+
+	results, _ = pages.FindPages(ctx, "Secret Manager", 3)
+	if len(results) != 1 || results[0].URL != "file:///secret-manager.html" {
+		t.Fatalf("FindPages(Secret Manager) = %+v", results)
+	}
+	if _, err := pages.FetchAsText(ctx, results[0].URL); err != nil {
+		t.Error(err)
+	}
+
+	// Pages outside the directory cannot be read
+	writePage(t, filepath.Dir(dir), "outside.html", "<p>secret</p>")
+	for _, pageURL := range []string{"file:///../outside.html", "https://cloud.google.com/../../outside", "https://cloud.google.com/missing"} {
+		if _, err := pages.FetchAsText(ctx, pageURL); err == nil {
+			t.Errorf("FetchAsText(%q) succeeded, want error", pageURL)
+		}
+	}
+}
+
+func TestService_GetFreeTierOffline(t *testing.T) {
+	dir := t.TempDir()
+	writePage(t, dir, "run/pricing.html", savedRunPricing)
+	pages := NewLocalPages(dir)
+
+	// Without the dataset, free tiers are extracted from the saved pages without network access
+	svc := NewService(WithDataset(nil), WithPageFinder(pages), WithPageFetcher(pages))
+	info, err := svc.GetFreeTier(context.Background(), "Cloud Run")
+	if err != nil || info == nil {
+		t.Fatalf("GetFreeTier() = %v, %v", info, err)
+	}
+	if info.Source != SourceScrape || info.SourceURL != "https://cloud.google.com/run/pricing" {
+		t.Errorf("source = %q (%s)", info.Source, info.SourceURL)
+	}
+	if item := FindMatchingFreeTierItem(info, "s"); item == nil || item.Amount != 180000 {
+		t.Errorf("vCPU item = %+v, want 180000", item)
+	}
+	// The memory allowance is only listed in a table
+	if item := FindMatchingFreeTierItem(info, "GiBy.s"); item == nil || item.Resource != "GiB-seconds" || item.Amount != 360000 {
+		t.Errorf("memory item = %+v, want 360000 GiB-seconds", item)
+	}
+	if !reflect.DeepEqual(info.Conditions, []string{ConditionPerBillingAccount}) {
+		t.Errorf("Conditions = %q", info.Conditions)
+	}
+
+	// Services without saved pages have no free tier
+	if info, _ := svc.GetFreeTier(context.Background(), "BigQuery"); info != nil {
+		t.Errorf("GetFreeTier(BigQuery) = %+v, want nil", info)
+	}
+}
@@ -122,11 +122,73 @@ var freeTierPatterns = []FreeTierPattern{
 	},
 }
 
-// ExtractFreeTierItems extracts free tier information from documentation content
-func ExtractFreeTierItems(content string) []FreeTierItem {
+// tableResources maps the wording of a table's row or column labels to free tier resources.
+// The first matching entry wins, so more specific wordings come first.
+var tableResources = []FreeTierPattern{
+	{Regex: regexp.MustCompile(`(?i)vCPU|\bCPU\b`), Resource: "vCPU-seconds", Unit: "seconds"},
+	{Regex: regexp.MustCompile(`(?i)Gi?B[- ]?seconds?|\bmemory\b`), Resource: "GiB-seconds", Unit: "seconds"},
+	{Regex: regexp.MustCompile(`(?i)build[- ]?minutes?`), Resource: "build-minutes", Unit: "min"},
+	{Regex: regexp.MustCompile(`(?i)secret\s*versions?`), Resource: "secret-versions", Unit: "count"},
+	{Regex: regexp.MustCompile(`(?i)access\s*operations?`), Resource: "access-operations", Unit: "count"},
+	{Regex: regexp.MustCompile(`(?i)\b(?:document\s*)?reads?\b`), Resource: "document-reads", Unit: "count"},
+	{Regex: regexp.MustCompile(`(?i)\b(?:document\s*)?writes?\b`), Resource: "document-writes", Unit: "count"},
+	{Regex: regexp.MustCompile(`(?i)\b(?:document\s*)?deletes?\b`), Resource: "document-deletes", Unit: "count"},
+	{Regex: regexp.MustCompile(`(?i)invocations?|requests?`), Resource: "requests", Unit: "count"},
+	{Regex: regexp.MustCompile(`(?i)quer(?:y|ies)|analysis`), Resource: "query-processing", Unit: "TiB"},
+	{Regex: regexp.MustCompile(`(?i)egress|outbound|network`), Resource: "egress", Unit: "GiB"},
+	{Regex: regexp.MustCompile(`(?i)messag`), Resource: "message-delivery", Unit: "GiB"},
+	{Regex: regexp.MustCompile(`(?i)storage|stored\s*data`), Resource: "storage", Unit: "GiB"},
+}
+
+// tableAmountRegex matches an amount in a table cell, e.g. "180,000", "2 million" or "5 GB".
+// A data unit must end the word, so that "360,000 GiB-seconds" is not read as GiB.
+var tableAmountRegex = regexp.MustCompile(`(?i)([0-9][0-9,]*(?:\.[0-9]+)?)\s*(thousand|million|billion|[KM]\b)?\s*(?:(GiB|GB|TiB|TB|MiB|MB)(?:[^\w-]|$))?`)
+
+// tableMultipliers scales the amounts of table cells
+var tableMultipliers = map[string]float64{
+	"":         1,
+	"k":        1_000,
+	"thousand": 1_000,
+	"m":        1_000_000,
+	"million":  1_000_000,
+	"billion":  1_000_000_000,
+}
+
+// tableDataUnits maps the data units of table cells to free tier units
+var tableDataUnits = map[string]string{
+	"mb":  "MiB",
+	"mib": "MiB",
+	"gb":  "GiB",
+	"gib": "GiB",
+	"tb":  "TiB",
+	"tib": "TiB",
+}
+
+// ExtractFreeTierItems extracts free tier information from documentation content and from the
+// cells of the page's tables, if any
+func ExtractFreeTierItems(content string, tables ...Table) []FreeTierItem {
 	var items []FreeTierItem
 	seen := make(map[string]bool)
+	add := func(item FreeTierItem) {
+		// Avoid duplicates
+		key := item.Resource + "-" + strconv.FormatFloat(item.Amount, 'f', -1, 64)
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+		items = append(items, item)
+	}
+
+	matchPatterns(content, add)
+	for i := range tables {
+		extractTableItems(&tables[i], add)
+	}
 
+	return items
+}
+
+// matchPatterns extracts the free tier items stated in prose
+func matchPatterns(content string, add func(FreeTierItem)) {
 	for _, pattern := range freeTierPatterns {
 		matches := pattern.Regex.FindAllStringSubmatch(content, -1)
 		for _, match := range matches {
@@ -146,27 +208,144 @@ func ExtractFreeTierItems(content string) []FreeTierItem {
 				amount *= 1_000_000
 			}
 
-			// Avoid duplicates
-			key := pattern.Resource + "-" + amountStr
-			if seen[key] {
-				continue
-			}
-			seen[key] = true
-
 			unit := pattern.Unit
 			if unit == "million" {
 				unit = "count"
 			}
 
-			items = append(items, FreeTierItem{
+			add(FreeTierItem{
 				Resource: pattern.Resource,
 				Amount:   amount,
 				Unit:     unit,
 			})
 		}
 	}
+}
 
-	return items
+// extractTableItems extracts the free tier items listed in a table. Every cell is matched like
+// prose. In addition, amounts are read from the cells of free tier columns (e.g. "Free per
+// month"), with the resource named by the row's label, and from the cells of a free tier row,
+// with the resource named by the column header.
+func extractTableItems(t *Table, add func(FreeTierItem)) {
+	for _, row := range t.Rows {
+		for _, cell := range row {
+			matchPatterns(cell, add)
+		}
+	}
+
+	// Free tier columns; the first column labels the rows. A table whose caption or first
+	// header marks the whole table as free (e.g. "Free tier" | "Quota") lists amounts in all
+	// other columns.
+	var amountColumns []int
+	wholeTable := isFreeTierLabel(t.Caption) || (len(t.Header) > 0 && isFreeTierLabel(t.Header[0]))
+	for col := 1; col < len(t.Header); col++ {
+		if isFreeTierLabel(t.Header[col]) || (wholeTable && !isPriceLabel(t.Header[col])) {
+			amountColumns = append(amountColumns, col)
+		}
+	}
+
+	for _, row := range t.Rows {
+		for _, col := range amountColumns {
+			if item, ok := tableItem(row[0], row[col], t.Header[col]); ok {
+				add(item)
+			}
+		}
+
+		// A row labeled as the free tier lists the amounts of the resources named by the headers
+		if len(amountColumns) == 0 && len(t.Header) == len(row) && isFreeTierLabel(row[0]) {
+			for col := 1; col < len(row); col++ {
+				if item, ok := tableItem(t.Header[col], row[col], row[0]); ok {
+					add(item)
+				}
+			}
+		}
+	}
+}
+
+// tableItem builds a free tier item from the label naming the resource, the cell holding the
+// amount, and the label of the amount's column or row, which may state the period
+func tableItem(label, cell, amountLabel string) (FreeTierItem, bool) {
+	if strings.Contains(cell, "$") {
+		return FreeTierItem{}, false
+	}
+
+	// The label names the resource, or else the cell does (e.g. "180,000 vCPU-seconds")
+	resource := tableResource(label)
+	if resource == nil {
+		resource = tableResource(cell)
+	}
+	if resource == nil {
+		return FreeTierItem{}, false
+	}
+
+	match := tableAmountRegex.FindStringSubmatch(cell)
+	if match == nil {
+		return FreeTierItem{}, false
+	}
+	amount, err := strconv.ParseFloat(strings.ReplaceAll(match[1], ",", ""), 64)
+	if err != nil || amount <= 0 {
+		return FreeTierItem{}, false
+	}
+	amount *= tableMultipliers[strings.ToLower(match[2])]
+
+	// Data amounts keep the cell's unit; other resources cannot be measured in data
+	unit := resource.Unit
+	if dataUnit, ok := tableDataUnits[strings.ToLower(match[3])]; ok {
+		if _, isData := tableDataUnits[strings.ToLower(unit)]; !isData {
+			return FreeTierItem{}, false
+		}
+		unit = dataUnit
+	}
+
+	return FreeTierItem{
+		Resource: resource.Resource,
+		Amount:   amount,
+		Unit:     unit,
+		Period:   statedPeriod(cell + " " + amountLabel),
+	}, true
+}
+
+// tableResource returns the resource a table label names, or nil
+func tableResource(label string) *FreeTierPattern {
+	for i := range tableResources {
+		if tableResources[i].Regex.MatchString(label) {
+			return &tableResources[i]
+		}
+	}
+	return nil
+}
+
+// isFreeTierLabel reports whether a table label refers to the free tier rather than to the
+// prices charged beyond it
+func isFreeTierLabel(label string) bool {
+	label = strings.ToLower(label)
+	if !strings.Contains(label, "free") && !strings.Contains(label, "no charge") {
+		return false
+	}
+	for _, beyond := range []string{"above", "beyond", "after", "over", "exceed"} {
+		if strings.Contains(label, beyond) {
+			return false
+		}
+	}
+	return true
+}
+
+// isPriceLabel reports whether a table label refers to prices
+func isPriceLabel(label string) bool {
+	label = strings.ToLower(label)
+	return strings.Contains(label, "price") || strings.Contains(label, "cost") || strings.Contains(label, "rate")
+}
+
+// statedPeriod returns the period a text states explicitly, or "" if it states none
+func statedPeriod(text string) string {
+	text = strings.ToLower(text)
+	switch {
+	case strings.Contains(text, "per day") || strings.Contains(text, "/day") || strings.Contains(text, "daily"):
+		return PeriodDay
+	case strings.Contains(text, "per month") || strings.Contains(text, "/month") || strings.Contains(text, "monthly"):
+		return PeriodMonth
+	}
+	return ""
 }
 
 // ExtractScope determines if free tier applies per account or per project
@@ -192,7 +371,7 @@ func ExtractPeriod(content string) string {
 	contentLower := strings.ToLower(content)
 
 	if strings.Contains(contentLower, "always free") {
-		return "always"
+		return PeriodAlways
 	}
 
 	monthCount := strings.Count(contentLower, "per month") +
@@ -204,8 +383,8 @@ func ExtractPeriod(content string) string {
 		strings.Count(contentLower, "daily")
 
 	if dayCount > 0 && dayCount > monthCount {
-		return "day"
+		return PeriodDay
 	}
 
-	return "month"
+	return PeriodMonth
 }
@@ -0,0 +1,48 @@
+package freetier
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nozomi-koborinai/gcp-cost-mcp-server/internal/money"
+	"github.com/nozomi-koborinai/gcp-cost-mcp-server/internal/pricing"
+)
+
+// Free tier periods reported in FreeTierInfo.Period
+const (
+	PeriodDay    = "day"
+	PeriodMonth  = "month"
+	PeriodAlways = "always"
+)
+
+// AllowanceForPeriod returns the free amount of an item over a usage period, with a note
+// describing how the amount was derived from the documented allowance.
+//
+// Daily allowances are granted once per day of the period and monthly allowances are pro-rated
+// by the number of (730 hour) months in the period. "always" marks an Always Free allowance,
+// whose limits renew every month, so it is treated like a monthly one.
+func AllowanceForPeriod(info *FreeTierInfo, item *FreeTierItem, period pricing.UsagePeriod) (money.Decimal, string) {
+	amount := money.FromFloat(item.Amount)
+	if period.IsZero() {
+		return amount, ""
+	}
+
+	month := pricing.UsageMonths(1).Days
+	days := period.Days
+	switch strings.ToLower(info.ItemPeriod(item)) {
+	case PeriodDay:
+		return amount.Mul(days), fmt.Sprintf("daily allowance of %s %s x %s days", amount, item.Resource, days.StringFixed(2, money.HalfEven))
+	case PeriodAlways:
+		scaled := amount.Mul(days).Quo(month)
+		if scaled.Cmp(amount) == 0 {
+			return amount, "Always Free allowance, which renews monthly"
+		}
+		return scaled, fmt.Sprintf("Always Free allowance of %s %s renews monthly; scaled to %s months", amount, item.Resource, days.Quo(month).StringFixed(2, money.HalfEven))
+	default:
+		scaled := amount.Mul(days).Quo(month)
+		if scaled.Cmp(amount) == 0 {
+			return amount, ""
+		}
+		return scaled, fmt.Sprintf("monthly allowance of %s %s scaled to %s months", amount, item.Resource, days.Quo(month).StringFixed(2, money.HalfEven))
+	}
+}
@@ -0,0 +1,48 @@
+package freetier
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nozomi-koborinai/gcp-cost-mcp-server/internal/money"
+	"github.com/nozomi-koborinai/gcp-cost-mcp-server/internal/pricing"
+)
+
+func TestAllowanceForPeriod(t *testing.T) {
+	reads := &FreeTierItem{Resource: "document-reads", Amount: 50000}
+	vcpu := &FreeTierItem{Resource: "vCPU-seconds", Amount: 180000}
+	storage := &FreeTierItem{Resource: "storage", Amount: 0.5}
+	e2micro := &FreeTierItem{Resource: "e2-micro-vcpu-hours", Amount: 182.5}
+
+	tests := []struct {
+		name     string
+		period   string
+		item     *FreeTierItem
+		usage    pricing.UsagePeriod
+		want     string
+		wantNote string
+	}{
+		{"daily allowance over a month", PeriodDay, reads, pricing.UsageDays(money.FromInt(30)), "1500000", "daily allowance"},
+		{"daily allowance over one day", PeriodDay, reads, pricing.UsageDays(money.FromInt(1)), "50000", "daily allowance"},
+		{"monthly allowance over a month", PeriodMonth, vcpu, pricing.UsageMonths(1), "180000", ""},
+		{"monthly allowance over a year", PeriodMonth, vcpu, pricing.UsageMonths(12), "2160000", "scaled to 12.00 months"},
+		{"always free renews monthly", PeriodAlways, vcpu, pricing.UsageMonths(1), "180000", "Always Free"},
+		{"always free over two months", PeriodAlways, vcpu, pricing.UsageMonths(2), "360000", "Always Free"},
+		{"unset period", PeriodDay, reads, pricing.UsagePeriod{}, "50000", ""},
+		// Fractional allowances are described exactly
+		{"fractional monthly allowance", PeriodMonth, storage, pricing.UsageMonths(2), "1", "monthly allowance of 0.5 storage"},
+		{"fractional always free allowance", PeriodAlways, e2micro, pricing.UsageMonths(2), "365", "Always Free allowance of 182.5 e2-micro-vcpu-hours"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, note := AllowanceForPeriod(&FreeTierInfo{Period: tt.period}, tt.item, tt.usage)
+			if got.Cmp(money.MustParse(tt.want)) != 0 {
+				t.Errorf("AllowanceForPeriod() = %s, want %s", got, tt.want)
+			}
+			if (tt.wantNote == "") != (note == "") || !strings.Contains(note, tt.wantNote) {
+				t.Errorf("note = %q, want it to contain %q", note, tt.wantNote)
+			}
+		})
+	}
+}
@@ -0,0 +1,272 @@
+package freetier
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// FreeProgramURL is the page of the Google Cloud Free Program that lists the Always Free usage
+// limits of all products
+const FreeProgramURL = "https://cloud.google.com/free/docs/free-cloud-features"
+
+// FreeProgram is the catalog of Always Free products built from the free program page. It is
+// consulted after the curated dataset and before searching each service's documentation.
+type FreeProgram struct {
+	SourceURL string
+	Products  []DatasetService
+}
+
+// programPattern extracts an allowance of the free program page that is not worded like the
+// allowances of pricing pages. Each match yields Items, scaled by the matched count.
+type programPattern struct {
+	Regex *regexp.Regexp
+	Items []FreeTierItem
+}
+
+// programPatterns are matched before the generic patterns, so that the items they yield are
+// bound to the SKUs they apply to like the curated dataset's
+var programPatterns = []programPattern{
+	// An e2-micro instance has 0.25 vCPU and 1 GiB of memory, running 730 hours a month
+	{
+		Regex: regexp.MustCompile(`(?i)([0-9]+)\s*(?:non-preemptible\s*)?e2-micro\s*(?:VM\s*)?instances?`),
+		Items: []FreeTierItem{
+			{Resource: "e2-micro-vcpu-hours", Amount: 182.5, Unit: "h", Match: &SKUPredicate{DisplayNameContains: []string{"E2 Instance Core"}}},
+			{Resource: "e2-micro-ram-hours", Amount: 730, Unit: "GiBy.h", Match: &SKUPredicate{DisplayNameContains: []string{"E2 Instance Ram"}}},
+		},
+	},
+	{
+		Regex: regexp.MustCompile(`(?i)([0-9.]+)\s*GB-months?\s*(?:of\s*)?standard\s*persistent\s*disk`),
+		Items: []FreeTierItem{
+			{Resource: "standard-persistent-disk-storage", Amount: 1, Unit: "GiBy.mo", Match: &SKUPredicate{
+				DisplayNameContains: []string{"Storage PD Capacity"},
+				DisplayNameExcludes: []string{"SSD", "Balanced", "Regional"},
+			}},
+		},
+	},
+	{
+		Regex: regexp.MustCompile(`(?i)([0-9.]+)\s*GB-months?\s*(?:of\s*)?(?:regional|standard)\s*storage`),
+		Items: []FreeTierItem{
+			{Resource: "standard-storage", Amount: 1, Unit: "GiBy.mo", Match: &SKUPredicate{
+				DisplayNameContains: []string{"Standard Storage"},
+				DisplayNameExcludes: []string{"Operations"},
+			}},
+		},
+	},
+	{
+		Regex: regexp.MustCompile(`(?i)([0-9,]+)\s*Class\s*A\s*operations?`),
+		Items: []FreeTierItem{
+			{Resource: "class-a-operations", Amount: 1, Unit: "count", Match: &SKUPredicate{DisplayNameContains: []string{"Class A"}}},
+		},
+	},
+	{
+		Regex: regexp.MustCompile(`(?i)([0-9,]+)\s*Class\s*B\s*operations?`),
+		Items: []FreeTierItem{
+			{Resource: "class-b-operations", Amount: 1, Unit: "count", Match: &SKUPredicate{DisplayNameContains: []string{"Class B"}}},
+		},
+	},
+}
+
+// ParseFreeProgram builds the catalog from the free program page. Every section headed by a
+// product name lists the product's limits as paragraphs, list items or tables; tables whose
+// first column is headed "Product" list one product per row. Regions named in a limit restrict
+// that limit, and regions named apart from any limit restrict all limits of the product.
+func ParseFreeProgram(page *Page, sourceURL string) (*FreeProgram, error) {
+	program := &FreeProgram{SourceURL: sourceURL}
+	for _, section := range page.Sections {
+		if section.Heading != "" {
+			url := sourceURL
+			if section.Anchor != "" {
+				url += "#" + section.Anchor
+			}
+			var tables []Table
+			for _, table := range section.Tables {
+				if !isProductTable(&table) {
+					tables = append(tables, table)
+				}
+			}
+			program.add(section.Heading, url, section.Blocks, tables)
+		}
+
+		for _, table := range section.Tables {
+			if !isProductTable(&table) {
+				continue
+			}
+			for _, row := range table.Rows {
+				program.add(row[0], sourceURL, row[1:], nil)
+			}
+		}
+	}
+
+	if len(program.Products) == 0 {
+		return nil, fmt.Errorf("no free tier products found on %s", sourceURL)
+	}
+	return program, nil
+}
+
+// FetchFreeProgram fetches the free program page and builds the catalog
+func FetchFreeProgram(ctx context.Context, fetcher PageFetcher, pageURL string) (*FreeProgram, error) {
+	page, err := fetcher.FetchPage(ctx, pageURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch free program page: %w", err)
+	}
+	return ParseFreeProgram(page, pageURL)
+}
+
+// LoadFreeProgramFile builds the catalog from a saved copy of the free program page
+func LoadFreeProgramFile(filename string) (*FreeProgram, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read free program page: %w", err)
+	}
+	page, err := parsePage(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse free program page: %w", err)
+	}
+	return ParseFreeProgram(page, FreeProgramURL)
+}
+
+// Lookup returns the free tier of a product by name, or nil if the free program does not
+// include it. The result is a copy that callers may modify.
+func (p *FreeProgram) Lookup(serviceName string) *FreeTierInfo {
+	product := findService(p.Products, serviceName)
+	if product == nil {
+		return nil
+	}
+	info := product.clone()
+	info.addConditions("")
+	info.Source = SourceFreeProgram
+	return info
+}
+
+// add adds the limits listed in blocks and tables to a product. Headings without limits (e.g.
+// "Free Tier usage limits") are not products and are ignored.
+func (p *FreeProgram) add(heading, sourceURL string, blocks []string, tables []Table) {
+	var items []FreeTierItem
+	var productRegions []string
+	seen := make(map[string]bool)
+	addItem := func(item FreeTierItem) {
+		key := item.Resource + "-" + strconv.FormatFloat(item.Amount, 'f', -1, 64)
+		if !seen[key] {
+			seen[key] = true
+			items = append(items, item)
+		}
+	}
+
+	for _, block := range blocks {
+		blockItems := programItems(block)
+		regions := regionsIn(block)
+		if len(blockItems) == 0 {
+			productRegions = append(productRegions, regions...)
+		}
+		for _, item := range blockItems {
+			if len(regions) > 0 {
+				item.Regions = append([]string(nil), regions...)
+			}
+			addItem(item)
+		}
+	}
+	for _, item := range ExtractFreeTierItems("", tables...) {
+		addItem(item)
+	}
+	if len(items) == 0 {
+		return
+	}
+
+	for i := range items {
+		if len(items[i].Regions) == 0 && len(productRegions) > 0 {
+			items[i].Regions = regionsIn(strings.Join(productRegions, " "))
+		}
+	}
+
+	// Caveats such as "GPUs are not included" are stated next to the limits
+	conditions := ExtractConditions(strings.Join(blocks, "\n"))
+
+	name, aliases := productNames(heading)
+	if existing := findService(p.Products, name); existing != nil {
+		existing.Items = append(existing.Items, items...)
+		for _, condition := range conditions {
+			if !slices.Contains(existing.Conditions, condition) {
+				existing.Conditions = append(existing.Conditions, condition)
+			}
+		}
+		return
+	}
+	p.Products = append(p.Products, DatasetService{
+		FreeTierInfo: FreeTierInfo{
+			ServiceName: name,
+			Items:       items,
+			Scope:       "account",
+			Period:      PeriodMonth,
+			Conditions:  conditions,
+			SourceURL:   sourceURL,
+		},
+		Aliases: aliases,
+	})
+}
+
+// limitSeparator separates the limits of a block; amounts such as "180,000" are not split
+var limitSeparator = regexp.MustCompile(`;\s*|,\s+`)
+
+// programItems extracts the limits stated in a paragraph or list item of the free program page.
+// A block may state several limits separated by commas or semicolons (e.g. "50,000 reads,
+// 20,000 writes per day").
+func programItems(block string) []FreeTierItem {
+	var items []FreeTierItem
+	for _, piece := range limitSeparator.Split(block, -1) {
+		// Region names hold digits that must not be read as amounts
+		piece = regionRegex.ReplaceAllString(piece, "")
+
+		matched := false
+		for _, pattern := range programPatterns {
+			match := pattern.Regex.FindStringSubmatch(piece)
+			if match == nil {
+				continue
+			}
+			count, err := strconv.ParseFloat(strings.ReplaceAll(match[1], ",", ""), 64)
+			if err != nil || count <= 0 {
+				continue
+			}
+			matched = true
+			for _, item := range pattern.Items {
+				item.Amount *= count
+				item.Period = statedPeriod(block)
+				items = append(items, item)
+			}
+		}
+		if matched {
+			continue
+		}
+		if item, ok := tableItem(piece, piece, block); ok {
+			items = append(items, item)
+		}
+	}
+
+	// Limits worded like pricing pages, e.g. "the first 2 million requests per month are free"
+	matchPatterns(block, func(item FreeTierItem) { items = append(items, item) })
+	return items
+}
+
+// isProductTable reports whether a table lists one product per row
+func isProductTable(t *Table) bool {
+	return len(t.Header) > 1 && strings.Contains(strings.ToLower(t.Header[0]), "product")
+}
+
+// productNames returns the name a product is listed under and its aliases: "Google Kubernetes
+// Engine (GKE)" is also known as "Kubernetes Engine" and "GKE"
+func productNames(heading string) (string, []string) {
+	name := strings.TrimSpace(heading)
+	var aliases []string
+	if open := strings.Index(name, "("); open > 0 && strings.HasSuffix(name, ")") {
+		aliases = append(aliases, strings.TrimSpace(name[open+1:len(name)-1]))
+		name = strings.TrimSpace(name[:open])
+	}
+	if short, ok := strings.CutPrefix(name, "Google "); ok {
+		aliases = append(aliases, short)
+	}
+	return name, aliases
+}
@@ -0,0 +1,223 @@
+package freetier
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// freeProgramPage is a trimmed copy of the free program page's layout
+const freeProgramPage = `<html><body><main>
+<h1>Free cloud features and trial offer</h1>
+<p>The Free Tier provides limited access to many Google Cloud resources, free of charge.</p>
+<h2 id="free-tier-usage-limits">Free Tier usage limits</h2>
+<p>The following products have Free Tier usage limits. Usage is per billing account.</p>
+
+<h3 id="compute">Compute Engine</h3>
+<ul>
+  <li>1 non-preemptible e2-micro VM instance per month in one of the following US regions:
+    <ul><li>Oregon: us-west1</li><li>Iowa: us-central1</li><li>South Carolina: us-east1</li></ul></li>
+  <li>30 GB-months standard persistent disk</li>
+  <li>5 GB-month snapshot storage in the following regions: us-west1, us-central1, us-east1, asia-east1, europe-west1</li>
+  <li>1 GB of outbound data transfer from North America to all region destinations (excluding China and Australia) per month</li>
+</ul>
+
+<h3 id="storage">Cloud Storage</h3>
+<ul>
+  <li>5 GB-months of regional storage per month</li>
+  <li>5,000 Class A Operations per month</li>
+  <li>50,000 Class B Operations per month</li>
+</ul>
+<p>Cloud Storage Always Free is only available in us-east1, us-west1, and us-central1.</p>
+
+<h3 id="firestore">Firestore</h3>
+<ul><li>1 GiB storage</li><li>50,000 reads, 20,000 writes, 20,000 deletes per day</li></ul>
+
+<h3 id="gke">Google Kubernetes Engine (GKE)</h3>
+<p>No cluster management fee for one Autopilot or zonal cluster per billing account; a $74.40/month credit.</p>
+
+<h2 id="more">More products</h2>
+<table>
+  <tr><th>Product</th><th>Free Tier usage limits</th></tr>
+  <tr><td>Cloud Build</td><td>2,500 build-minutes per month</td></tr>
+  <tr><td>Pub/Sub</td><td>10 GB of messages per month</td></tr>
+  <tr><td>Cloud Shell</td><td>Free access with 5 GB of persistent disk storage</td></tr>
+</table>
+</main></body></html>`
+
+// parseFreeProgram parses freeProgramPage
+func parseFreeProgram(t *testing.T) *FreeProgram {
+	t.Helper()
+	page, err := parsePage(freeProgramPage)
+	if err != nil {
+		t.Fatal(err)
+	}
+	program, err := ParseFreeProgram(page, FreeProgramURL)
+	if err != nil {
+		t.Fatalf("ParseFreeProgram failed: %v", err)
+	}
+	return program
+}
+
+func TestParseFreeProgram(t *testing.T) {
+	program := parseFreeProgram(t)
+
+	var names []string
+	for _, product := range program.Products {
+		names = append(names, product.ServiceName)
+	}
+	expected := []string{"Compute Engine", "Cloud Storage", "Firestore", "Google Kubernetes Engine", "Cloud Build", "Pub/Sub", "Cloud Shell"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("products = %q, want %q", names, expected)
+	}
+
+	usRegions := []string{"us-west1", "us-central1", "us-east1"}
+	tests := []struct {
+		service  string
+		expected []FreeTierItem
+	}{
+		{
+			service: "Compute Engine",
+			expected: []FreeTierItem{
+				{Resource: "e2-micro-vcpu-hours", Amount: 182.5, Unit: "h", Period: PeriodMonth, Regions: usRegions, Match: programPatterns[0].Items[0].Match},
+				{Resource: "e2-micro-ram-hours", Amount: 730, Unit: "GiBy.h", Period: PeriodMonth, Regions: usRegions, Match: programPatterns[0].Items[1].Match},
+				{Resource: "standard-persistent-disk-storage", Amount: 30, Unit: "GiBy.mo", Match: programPatterns[1].Items[0].Match},
+				{Resource: "storage", Amount: 5, Unit: "GiB", Regions: []string{"us-west1", "us-central1", "us-east1", "asia-east1", "europe-west1"}},
+				{Resource: "egress", Amount: 1, Unit: "GiB", Period: PeriodMonth},
+			},
+		},
+		{
+			// Regions named apart from the limits restrict all of them
+			service: "Cloud Storage",
+			expected: []FreeTierItem{
+				{Resource: "standard-storage", Amount: 5, Unit: "GiBy.mo", Period: PeriodMonth, Regions: []string{"us-east1", "us-west1", "us-central1"}, Match: programPatterns[2].Items[0].Match},
+				{Resource: "class-a-operations", Amount: 5000, Unit: "count", Period: PeriodMonth, Regions: []string{"us-east1", "us-west1", "us-central1"}, Match: programPatterns[3].Items[0].Match},
+				{Resource: "class-b-operations", Amount: 50000, Unit: "count", Period: PeriodMonth, Regions: []string{"us-east1", "us-west1", "us-central1"}, Match: programPatterns[4].Items[0].Match},
+			},
+		},
+		{
+			// Several limits in one list item share its period
+			service: "Firestore",
+			expected: []FreeTierItem{
+				{Resource: "storage", Amount: 1, Unit: "GiB"},
+				{Resource: "document-reads", Amount: 50000, Unit: "count", Period: PeriodDay},
+				{Resource: "document-writes", Amount: 20000, Unit: "count", Period: PeriodDay},
+				{Resource: "document-deletes", Amount: 20000, Unit: "count", Period: PeriodDay},
+			},
+		},
+		{
+			service:  "GKE",
+			expected: []FreeTierItem{{Resource: "cluster-credit", Amount: 74.40, Unit: "USD"}},
+		},
+		{
+			service:  "Cloud Build",
+			expected: []FreeTierItem{{Resource: "build-minutes", Amount: 2500, Unit: "min", Period: PeriodMonth}},
+		},
+		{
+			service:  "Pub/Sub",
+			expected: []FreeTierItem{{Resource: "message-delivery", Amount: 10, Unit: "GiB", Period: PeriodMonth}},
+		},
+	}
+	for _, tt := range tests {
+		info := program.Lookup(tt.service)
+		if info == nil {
+			t.Errorf("Lookup(%q) = nil", tt.service)
+			continue
+		}
+		if info.Source != SourceFreeProgram {
+			t.Errorf("%s: Source = %q", tt.service, info.Source)
+		}
+		if !reflect.DeepEqual(info.Items, tt.expected) {
+			t.Errorf("%s items:\n got %+v\nwant %+v", tt.service, info.Items, tt.expected)
+		}
+	}
+
+	if program.Lookup("Free Tier usage limits") != nil {
+		t.Error("headings without limits are not products")
+	}
+
+	// Sections link to their anchor on the page
+	if info := program.Lookup("Compute Engine"); info.SourceURL != FreeProgramURL+"#compute" {
+		t.Errorf("SourceURL = %q", info.SourceURL)
+	}
+}
+
+func TestParseFreeProgram_NoProducts(t *testing.T) {
+	page, err := parsePage(`<h1>Pricing</h1><p>See the pricing pages of each product.</p>`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ParseFreeProgram(page, FreeProgramURL); err == nil {
+		t.Error("expected an error for a page without products")
+	}
+}
+
+func TestLoadFreeProgramFile(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "free-cloud-features.html")
+	if err := os.WriteFile(filename, []byte(freeProgramPage), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	program, err := LoadFreeProgramFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if program.SourceURL != FreeProgramURL || program.Lookup("Compute Engine") == nil {
+		t.Errorf("LoadFreeProgramFile() = %+v", program)
+	}
+}
+
+func TestService_GetFreeTierFromFreeProgram(t *testing.T) {
+	// The free program page is fetched with the page fetcher, here from saved pages
+	dir := t.TempDir()
+	writePage(t, dir, "free/docs/free-cloud-features.html", freeProgramPage)
+	pages := NewLocalPages(dir)
+	svc := NewService(WithPageFinder(NewStaticPageFinder(nil)), WithPageFetcher(pages))
+
+	// The curated dataset comes first
+	if info, _ := svc.GetFreeTier(context.Background(), "Compute Engine"); info == nil || info.Source != SourceDataset {
+		t.Errorf("GetFreeTier(Compute Engine) = %+v, want the dataset", info)
+	}
+
+	info, err := svc.GetFreeTier(context.Background(), "Cloud Shell")
+	if err != nil || info == nil {
+		t.Fatalf("GetFreeTier(Cloud Shell) = %v, %v", info, err)
+	}
+	if info.Source != SourceFreeProgram || len(info.Items) != 1 || info.Items[0].Resource != "storage" {
+		t.Errorf("GetFreeTier(Cloud Shell) = %+v", info)
+	}
+
+	// Without the dataset, the free program page provides the region restrictions
+	svc = NewService(WithDataset(nil), WithFreeProgram(parseFreeProgram(t)))
+	info, _ = svc.GetFreeTier(context.Background(), "Compute Engine")
+	if info == nil || info.Source != SourceFreeProgram || !reflect.DeepEqual(info.Items[0].Regions, []string{"us-west1", "us-central1", "us-east1"}) {
+		t.Errorf("GetFreeTier(Compute Engine) = %+v", info)
+	}
+}
+
+func TestFreeTierItem_AvailableIn(t *testing.T) {
+	restricted := &FreeTierItem{Resource: "e2-micro-vcpu-hours", Regions: []string{"us-west1", "us-central1", "us-east1"}}
+	tests := []struct {
+		item   *FreeTierItem
+		region string
+		want   bool
+	}{
+		{restricted, "us-central1", true},
+		{restricted, "US-CENTRAL1", true},
+		{restricted, "us-central1-a", true},
+		{restricted, "asia-northeast1", false},
+		{restricted, "us", false},
+		{restricted, "", false},
+		{&FreeTierItem{Resource: "requests"}, "asia-northeast1", true},
+		{&FreeTierItem{Resource: "requests"}, "", true},
+	}
+	for _, tt := range tests {
+		if got := tt.item.AvailableIn(tt.region); got != tt.want {
+			t.Errorf("AvailableIn(%q) = %v, want %v", tt.region, got, tt.want)
+		}
+		if err := tt.item.CheckRegion(tt.region); (err == nil) != tt.want {
+			t.Errorf("CheckRegion(%q) = %v", tt.region, err)
+		}
+	}
+}
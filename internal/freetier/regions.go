@@ -0,0 +1,49 @@
+package freetier
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// regionRegex matches Google Cloud region names such as "us-west1" or "northamerica-northeast2",
+// and the region part of zone names such as "us-central1-a"
+var regionRegex = regexp.MustCompile(`\b(?:us|europe|asia|australia|northamerica|southamerica|me|africa)-[a-z]+[0-9]+\b`)
+
+// regionsIn returns the region names mentioned in a text, in order and without duplicates
+func regionsIn(text string) []string {
+	var regions []string
+	for _, region := range regionRegex.FindAllString(strings.ToLower(text), -1) {
+		if !slices.Contains(regions, region) {
+			regions = append(regions, region)
+		}
+	}
+	return regions
+}
+
+// AvailableIn reports whether the allowance applies to usage in a region. Items without regions
+// apply everywhere; restricted items never apply to usage in an unknown region (""). A zone
+// (e.g. "us-central1-a") is in the region it belongs to.
+func (item *FreeTierItem) AvailableIn(region string) bool {
+	if len(item.Regions) == 0 {
+		return true
+	}
+	region = regionRegex.FindString(strings.ToLower(region))
+	if region == "" {
+		return false
+	}
+	return slices.ContainsFunc(item.Regions, func(r string) bool { return strings.EqualFold(r, region) })
+}
+
+// CheckRegion returns an error explaining why the allowance does not apply to usage in a region,
+// or nil if it does
+func (item *FreeTierItem) CheckRegion(region string) error {
+	if item.AvailableIn(region) {
+		return nil
+	}
+	if region == "" {
+		return fmt.Errorf("it only applies in %s and the region of this usage is unknown", strings.Join(item.Regions, ", "))
+	}
+	return fmt.Errorf("it only applies in %s, not in %s", strings.Join(item.Regions, ", "), region)
+}
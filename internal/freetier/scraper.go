@@ -26,16 +26,48 @@ func NewGCPDocScraperClient() *GCPDocScraperClient {
 	}
 }
 
+// Page is the content of a documentation page
+type Page struct {
+	// Text is the readable text of the page, with tables flattened into it
+	Text string
+	// Tables holds the page's tables, where most free tiers and per-region prices are listed
+	Tables []Table
+	// Sections holds the page's content grouped by heading
+	Sections []Section
+}
+
+// Section is the content under one heading of a page, up to the next heading
+type Section struct {
+	Heading string
+	// Level is the heading's level, from 1 for <h1> to 6 for <h6>, or 0 for the content before
+	// the first heading
+	Level int
+	// Anchor is the heading's id, which links to the section
+	Anchor string
+	// Blocks holds the text of the section's paragraphs and list items in order
+	Blocks []string
+	Tables []Table
+}
+
 // FetchAsText fetches a GCP documentation page and returns the text content
 func (c *GCPDocScraperClient) FetchAsText(ctx context.Context, url string) (string, error) {
+	page, err := c.FetchPage(ctx, url)
+	if err != nil {
+		return "", err
+	}
+	return page.Text, nil
+}
+
+// FetchPage fetches a GCP documentation page and returns its text and tables
+func (c *GCPDocScraperClient) FetchPage(ctx context.Context, url string) (*Page, error) {
 	// Validate URL is from cloud.google.com
 	if !strings.HasPrefix(url, "https://cloud.google.com") {
-		return "", fmt.Errorf("URL must be from cloud.google.com")
+		return nil, fmt.Errorf("URL must be from cloud.google.com")
 	}
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
-		return "", fmt.Errorf("failed to create request: %w", err)
+		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	// Set headers to mimic a browser request
@@ -45,26 +77,95 @@ func (c *GCPDocScraperClient) FetchAsText(ctx context.Context, url string) (stri
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("failed to fetch page: %w", err)
+		return nil, fmt.Errorf("failed to fetch page: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("page returned status %d", resp.StatusCode)
+		return nil, fmt.Errorf("page returned status %d", resp.StatusCode)
 	}
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return "", fmt.Errorf("failed to read response body: %w", err)
+		return nil, fmt.Errorf("failed to read response body: %w", err)
 	}
 
-	// Parse HTML and extract text content
-	text, err := extractTextFromHTML(string(body))
+	// Parse HTML and extract text content and tables
+	page, err := parsePage(string(body))
 	if err != nil {
-		return "", fmt.Errorf("failed to extract text: %w", err)
+		return nil, fmt.Errorf("failed to extract text: %w", err)
 	}
 
-	return text, nil
+	return page, nil
+}
+
+// skipTags are the elements whose content is not part of a page's text or tables
+var skipTags = map[string]bool{
+	"script":   true,
+	"style":    true,
+	"nav":      true,
+	"header":   true,
+	"footer":   true,
+	"noscript": true,
+	"svg":      true,
+	"path":     true,
+	"meta":     true,
+	"link":     true,
+}
+
+// parsePage extracts the readable text and the tables of an HTML page
+func parsePage(htmlContent string) (*Page, error) {
+	doc, err := html.Parse(strings.NewReader(htmlContent))
+	if err != nil {
+		return nil, err
+	}
+	return &Page{Text: documentText(doc), Tables: extractTables(doc), Sections: extractSections(doc)}, nil
+}
+
+// extractSections groups the paragraphs, list items and tables of a parsed HTML document by
+// the heading they follow
+func extractSections(doc *html.Node) []Section {
+	sections := []Section{{}}
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			current := &sections[len(sections)-1]
+			switch n.Data {
+			case "h1", "h2", "h3", "h4", "h5", "h6":
+				section := Section{Heading: nodeText(n), Level: int(n.Data[1] - '0')}
+				for _, attr := range n.Attr {
+					if attr.Key == "id" {
+						section.Anchor = attr.Val
+					}
+				}
+				sections = append(sections, section)
+				return
+			case "p", "li", "dt", "dd":
+				// Nested lists are part of their item's text
+				if text := nodeText(n); text != "" {
+					current.Blocks = append(current.Blocks, text)
+				}
+				return
+			case "table":
+				if table, ok := parseTable(n); ok {
+					current.Tables = append(current.Tables, table)
+				}
+				return
+			}
+			if skipTags[n.Data] {
+				return
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(doc)
+
+	if len(sections[0].Blocks) == 0 && len(sections[0].Tables) == 0 {
+		sections = sections[1:]
+	}
+	return sections
 }
 
 // extractTextFromHTML extracts readable text from HTML content
@@ -73,24 +174,14 @@ func extractTextFromHTML(htmlContent string) (string, error) {
 	if err != nil {
 		return "", err
 	}
+	return documentText(doc), nil
+}
 
+// documentText extracts readable text from a parsed HTML document
+func documentText(doc *html.Node) string {
 	var textBuilder strings.Builder
 	var extractText func(*html.Node)
 
-	// Tags to skip
-	skipTags := map[string]bool{
-		"script":   true,
-		"style":    true,
-		"nav":      true,
-		"header":   true,
-		"footer":   true,
-		"noscript": true,
-		"svg":      true,
-		"path":     true,
-		"meta":     true,
-		"link":     true,
-	}
-
 	extractText = func(n *html.Node) {
 		if n.Type == html.ElementNode {
 			// Skip certain tags
@@ -136,7 +227,7 @@ func extractTextFromHTML(htmlContent string) (string, error) {
 	text := textBuilder.String()
 	text = cleanText(text)
 
-	return text, nil
+	return text
 }
 
 // cleanText cleans up extracted text content
@@ -157,6 +248,11 @@ func cleanText(text string) string {
 
 // ExtractPricingSection tries to extract specifically the pricing/free tier section
 func (c *GCPDocScraperClient) ExtractPricingSection(content string) string {
+	return extractPricingSection(content)
+}
+
+// extractPricingSection returns the part of a page's text around its first pricing/free tier marker
+func extractPricingSection(content string) string {
 	// Look for common pricing section indicators
 	sectionMarkers := []string{
 		"pricing",
@@ -194,4 +290,3 @@ func (c *GCPDocScraperClient) ExtractPricingSection(content string) string {
 
 	return content[startIdx:endIdx]
 }
-
@@ -14,6 +14,15 @@ type FreeTierItem struct {
 	Resource string  `json:"resource"`
 	Amount   float64 `json:"amount"`
 	Unit     string  `json:"unit"`
+	// SKUIDs are the SKUs the allowance applies to, when known
+	SKUIDs []string `json:"sku_ids,omitempty"`
+	// Period and Scope override the service's period and scope for this item
+	Period string `json:"period,omitempty"`
+	Scope  string `json:"scope,omitempty"`
+	// Regions restricts the allowance to usage in these regions
+	Regions []string `json:"regions,omitempty"`
+	// Match selects the SKUs the allowance applies to by display name and category
+	Match *SKUPredicate `json:"match,omitempty"`
 }
 
 // FreeTierInfo contains all free tier information for a service
@@ -24,6 +33,32 @@ type FreeTierInfo struct {
 	Period      string         `json:"period"` // "month", "day", or "always"
 	Conditions  []string       `json:"conditions,omitempty"`
 	SourceURL   string         `json:"source_url"`
+	// Source is where the information came from: SourceDataset, SourceFreeProgram or SourceScrape
+	Source         string `json:"source"`
+	DatasetVersion string `json:"dataset_version,omitempty"`
+}
+
+// Sources of free tier information reported in FreeTierInfo.Source
+const (
+	SourceDataset     = "dataset"
+	SourceFreeProgram = "free_program"
+	SourceScrape      = "scrape"
+)
+
+// ItemPeriod returns the period of an item's allowance, which defaults to the service's period
+func (info *FreeTierInfo) ItemPeriod(item *FreeTierItem) string {
+	if item.Period != "" {
+		return item.Period
+	}
+	return info.Period
+}
+
+// ItemScope returns the scope of an item's allowance, which defaults to the service's scope
+func (info *FreeTierInfo) ItemScope(item *FreeTierItem) string {
+	if item.Scope != "" {
+		return item.Scope
+	}
+	return info.Scope
 }
 
 // CachedFreeTier wraps FreeTierInfo with cache metadata
@@ -40,25 +75,105 @@ func (c *CachedFreeTier) IsExpired() bool {
 
 // Service provides free tier information retrieval with caching
 type Service struct {
-	searchClient  *DuckDuckGoClient
-	scraperClient *GCPDocScraperClient
+	searchClient  PageFinder
+	scraperClient PageFetcher
 	cache         map[string]*CachedFreeTier
 	cacheMutex    sync.RWMutex
 	cacheTTL      time.Duration
+	// dataset is consulted before scraping documentation
+	dataset *Dataset
+
+	// program is the free program catalog, consulted after the dataset. Unless set by
+	// WithFreeProgram, it is fetched from programURL on first use.
+	program        *FreeProgram
+	programURL     string
+	programMutex   sync.Mutex
+	programRetryAt time.Time
 }
 
-// NewService creates a new FreeTierService
-func NewService() *Service {
-	return &Service{
+// ServiceOption configures a Service created by NewService
+type ServiceOption func(*Service)
+
+// WithPageFinder replaces the DuckDuckGo search used to find documentation pages
+func WithPageFinder(finder PageFinder) ServiceOption {
+	return func(s *Service) {
+		s.searchClient = finder
+	}
+}
+
+// WithPageFetcher replaces the cloud.google.com scraper used to fetch documentation pages
+func WithPageFetcher(fetcher PageFetcher) ServiceOption {
+	return func(s *Service) {
+		s.scraperClient = fetcher
+	}
+}
+
+// WithDataset replaces the embedded curated dataset. A nil dataset disables it, so that every
+// free tier is extracted from documentation.
+func WithDataset(dataset *Dataset) ServiceOption {
+	return func(s *Service) {
+		s.dataset = dataset
+	}
+}
+
+// WithFreeProgram sets the free program catalog, e.g. one loaded from a saved page with
+// LoadFreeProgramFile, instead of fetching it
+func WithFreeProgram(program *FreeProgram) ServiceOption {
+	return func(s *Service) {
+		s.program = program
+	}
+}
+
+// WithFreeProgramURL sets the URL the free program page is fetched from with the page fetcher.
+// An empty URL disables the free program catalog.
+func WithFreeProgramURL(pageURL string) ServiceOption {
+	return func(s *Service) {
+		s.programURL = pageURL
+	}
+}
+
+// WithCacheTTL sets how long free tier information extracted from documentation is cached
+func WithCacheTTL(ttl time.Duration) ServiceOption {
+	return func(s *Service) {
+		s.cacheTTL = ttl
+	}
+}
+
+// NewService creates a new FreeTierService. By default it uses the embedded dataset, then the
+// free program page, and DuckDuckGo and the cloud.google.com scraper for services neither covers.
+func NewService(opts ...ServiceOption) *Service {
+	s := &Service{
 		searchClient:  NewDuckDuckGoClient(),
 		scraperClient: NewGCPDocScraperClient(),
 		cache:         make(map[string]*CachedFreeTier),
 		cacheTTL:      24 * time.Hour,
+		dataset:       DefaultDataset(),
+		programURL:    FreeProgramURL,
+	}
+	for _, opt := range opts {
+		opt(s)
 	}
+	return s
 }
 
-// GetFreeTier retrieves free tier information for a GCP service
+// GetFreeTier retrieves free tier information for a GCP service. The curated dataset is consulted
+// first, then the free program catalog; documentation is only searched and scraped for services
+// neither covers. FreeTierInfo.Source reports which one was used.
 func (s *Service) GetFreeTier(ctx context.Context, serviceName string) (*FreeTierInfo, error) {
+	if s.dataset != nil {
+		if info := s.dataset.Lookup(serviceName); info != nil {
+			log.Printf("FreeTierService: Using curated dataset %s for %s", s.dataset.Version, serviceName)
+			return info, nil
+		}
+	}
+
+	if program := s.freeProgram(ctx); program != nil {
+		if info := program.Lookup(serviceName); info != nil {
+			log.Printf("FreeTierService: Using free program page for %s", serviceName)
+			return info, nil
+		}
+	}
+
 	// Normalize service name for cache key
 	cacheKey := normalizeServiceName(serviceName)
 
@@ -95,15 +210,33 @@ func (s *Service) GetFreeTier(ctx context.Context, serviceName string) (*FreeTie
 	return freeTier, nil
 }
 
-// fetchFreeTierFromDocs searches for and extracts free tier info from GCP documentation
-func (s *Service) fetchFreeTierFromDocs(ctx context.Context, serviceName string) (*FreeTierInfo, error) {
-	// Build search query
-	query := fmt.Sprintf("site:cloud.google.com %s pricing", serviceName)
+// freeProgram returns the free program catalog, fetching it on first use. A failed fetch is
+// retried once the cache TTL has passed.
+func (s *Service) freeProgram(ctx context.Context) *FreeProgram {
+	s.programMutex.Lock()
+	defer s.programMutex.Unlock()
 
-	// Search for pricing pages
-	results, err := s.searchClient.Search(ctx, query, 3)
+	if s.program != nil || s.programURL == "" || time.Now().Before(s.programRetryAt) {
+		return s.program
+	}
+
+	program, err := FetchFreeProgram(ctx, s.scraperClient, s.programURL)
 	if err != nil {
-		return nil, fmt.Errorf("search failed: %w", err)
+		log.Printf("FreeTierService: Free program catalog unavailable: %v", err)
+		s.programRetryAt = time.Now().Add(s.cacheTTL)
+		return nil
+	}
+	log.Printf("FreeTierService: Loaded %d products from the free program page", len(program.Products))
+	s.program = program
+	return program
+}
+
+// fetchFreeTierFromDocs finds documentation pages and extracts free tier info from them
+func (s *Service) fetchFreeTierFromDocs(ctx context.Context, serviceName string) (*FreeTierInfo, error) {
+	// Find pricing pages
+	results, err := s.searchClient.FindPages(ctx, serviceName, 3)
+	if err != nil {
+		return nil, err
 	}
 
 	if len(results) == 0 {
@@ -113,44 +246,37 @@ func (s *Service) fetchFreeTierFromDocs(ctx context.Context, serviceName string)
 	// Try to fetch and extract from each result
 	var lastErr error
 	for _, result := range results {
-		// Only process cloud.google.com URLs
-		if !strings.Contains(result.URL, "cloud.google.com") {
-			continue
-		}
-
-		// Skip non-pricing pages
-		if !strings.Contains(strings.ToLower(result.URL), "pricing") &&
-			!strings.Contains(strings.ToLower(result.Title), "pricing") {
-			continue
-		}
-
 		log.Printf("FreeTierService: Fetching %s", result.URL)
 
 		// Fetch the page content
-		content, err := s.scraperClient.FetchAsText(ctx, result.URL)
+		page, err := s.scraperClient.FetchPage(ctx, result.URL)
 		if err != nil {
 			lastErr = err
 			continue
 		}
+		content := page.Text
 
 		// Extract pricing section
-		pricingContent := s.scraperClient.ExtractPricingSection(content)
+		pricingContent := extractPricingSection(content)
 
-		// Extract free tier items
-		items := ExtractFreeTierItems(pricingContent)
+		// Extract free tier items from the pricing section and the page's tables
+		items := ExtractFreeTierItems(pricingContent, page.Tables...)
 		if len(items) == 0 {
 			// Try with full content
 			items = ExtractFreeTierItems(content)
 		}
 
 		if len(items) > 0 {
-			return &FreeTierInfo{
+			info := &FreeTierInfo{
 				ServiceName: serviceName,
 				Items:       items,
 				Scope:       ExtractScope(content),
 				Period:      ExtractPeriod(content),
 				SourceURL:   result.URL,
-			}, nil
+				Source:      SourceScrape,
+			}
+			info.addConditions(freeTierPassages(page))
+			return info, nil
 		}
 	}
 
@@ -208,44 +334,43 @@ func (s *Service) GetCacheStats() map[string]interface{} {
 	}
 }
 
-// FindMatchingFreeTierItem finds a free tier item that matches the given usage unit
+// unitMapping maps SKU usage units to free tier resource names
+var unitMapping = map[string][]string{
+	"s":       {"vCPU-seconds", "GiB-seconds", "seconds"},
+	"GiBy":    {"GiB-seconds", "storage"},
+	"GiBy.s":  {"GiB-seconds"},
+	"GiBy.h":  {"GiB-seconds"},
+	"GiBy.mo": {"storage"},
+	"GiBy.d":  {"storage"},
+	"TiBy":    {"query-processing"},
+	"By":      {"storage", "egress"},
+	"count":   {"requests", "operations", "access-operations", "document-reads", "document-writes", "document-deletes"},
+	"1":       {"requests", "operations", "secret-versions"},
+	"h":       {"hours"},
+	"mo":      {"months"},
+}
+
+// FindMatchingFreeTierItem finds a free tier item that matches the given usage unit, preferring
+// items whose amount converts to the unit. It picks the first candidate; use MatchSKU to bind
+// free tiers to specific SKUs and detect ambiguous matches.
 func FindMatchingFreeTierItem(freeTier *FreeTierInfo, usageUnit string) *FreeTierItem {
 	if freeTier == nil || len(freeTier.Items) == 0 {
 		return nil
 	}
 
-	// Map SKU usage units to free tier resource names
-	unitMapping := map[string][]string{
-		"s":     {"vCPU-seconds", "GiB-seconds", "seconds"},
-		"GiBy":  {"GiB-seconds", "storage"},
-		"GiBy.s": {"GiB-seconds"},
-		"By":    {"storage", "egress"},
-		"count": {"requests", "operations", "access-operations", "document-reads", "document-writes", "document-deletes"},
-		"1":     {"requests", "operations", "secret-versions"},
-		"h":     {"hours"},
-		"mo":    {"months"},
-	}
-
-	possibleResources, ok := unitMapping[usageUnit]
-	if !ok {
-		// Try to find a direct match
-		for i := range freeTier.Items {
-			if strings.EqualFold(freeTier.Items[i].Unit, usageUnit) {
-				return &freeTier.Items[i]
-			}
-		}
-		return nil
-	}
-
-	// Look for matching free tier item
+	items := make([]*FreeTierItem, len(freeTier.Items))
 	for i := range freeTier.Items {
-		for _, resource := range possibleResources {
-			if strings.Contains(strings.ToLower(freeTier.Items[i].Resource), strings.ToLower(resource)) {
-				return &freeTier.Items[i]
-			}
+		items[i] = &freeTier.Items[i]
+	}
+	candidates := unitCandidates(items, usageUnit)
+	for _, candidate := range candidates {
+		if convertible(candidate, usageUnit) {
+			return candidate
 		}
 	}
+	if len(candidates) > 0 {
+		return candidates[0]
+	}
 
 	return nil
 }
-
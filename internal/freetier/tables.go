@@ -0,0 +1,270 @@
+package freetier
+
+import (
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// Table is an HTML table expanded into a rectangular grid: a cell spanning several rows or
+// columns is repeated in every position it covers
+type Table struct {
+	// Caption is the table's <caption>, if any
+	Caption string
+	// Header holds the column headers. The headers of several header rows are joined per
+	// column (e.g. "Free tier Amount"). It is nil if no header row was detected.
+	Header []string
+	// Rows holds the body rows. Every row has as many cells as the widest row of the table.
+	Rows [][]string
+}
+
+// Column returns the index of the first column whose header contains name, ignoring case,
+// or -1 if there is none
+func (t *Table) Column(name string) int {
+	name = strings.ToLower(name)
+	for i, header := range t.Header {
+		if strings.Contains(strings.ToLower(header), name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Upper bounds of colspan and rowspan, as in the HTML specification, so that a malformed page
+// cannot make the grid arbitrarily large
+const (
+	maxColspan = 1000
+	maxRowspan = 65534
+)
+
+// gridCell is a cell of a table row while the table is being expanded
+type gridCell struct {
+	text   string
+	header bool
+}
+
+// gridRow is an expanded table row
+type gridRow struct {
+	cells []gridCell
+	// inHead reports whether the row is in the table's <thead>
+	inHead bool
+}
+
+// extractTables returns the tables of a parsed HTML document, including tables nested in cells
+func extractTables(doc *html.Node) []Table {
+	var tables []Table
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			if skipTags[n.Data] {
+				return
+			}
+			if n.Data == "table" {
+				if table, ok := parseTable(n); ok {
+					tables = append(tables, table)
+				}
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(doc)
+	return tables
+}
+
+// parseTable expands a <table> element into a Table. It reports false for tables without cells.
+func parseTable(n *html.Node) (Table, bool) {
+	var table Table
+	var rows []gridRow
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type != html.ElementNode {
+			continue
+		}
+		switch c.Data {
+		case "caption":
+			table.Caption = nodeText(c)
+		case "thead", "tbody", "tfoot":
+			// Row spans never extend beyond their row group
+			for _, row := range expandRows(childElements(c, "tr")) {
+				rows = append(rows, gridRow{cells: row, inHead: c.Data == "thead"})
+			}
+		case "tr":
+			// The HTML parser wraps rows in <tbody>, but handle bare rows anyway
+			rows = append(rows, gridRow{cells: expandRows([]*html.Node{c})[0]})
+		}
+	}
+
+	width := 0
+	for _, row := range rows {
+		width = max(width, len(row.cells))
+	}
+	if width == 0 {
+		return table, false
+	}
+
+	// Header rows are the rows of <thead>, or else the leading rows made only of <th> cells
+	headerRows := 0
+	for _, row := range rows {
+		if !row.inHead && !allHeaderCells(row.cells) {
+			break
+		}
+		headerRows++
+	}
+	if headerRows == len(rows) && !rows[0].inHead {
+		// A table of <th> cells only has no body to describe
+		headerRows = 0
+	}
+
+	if headerRows > 0 {
+		table.Header = make([]string, width)
+		for col := range width {
+			var parts []string
+			for _, row := range rows[:headerRows] {
+				text := cellText(row.cells, col)
+				// A header spanning several header rows is repeated in each of them
+				if text != "" && (len(parts) == 0 || parts[len(parts)-1] != text) {
+					parts = append(parts, text)
+				}
+			}
+			table.Header[col] = strings.Join(parts, " ")
+		}
+	}
+
+	for _, row := range rows[headerRows:] {
+		cells := make([]string, width)
+		for col := range width {
+			cells[col] = cellText(row.cells, col)
+		}
+		table.Rows = append(table.Rows, cells)
+	}
+	return table, true
+}
+
+// expandRows expands the cells of a row group, repeating cells across their colspan and rowspan
+func expandRows(trs []*html.Node) [][]gridCell {
+	// carried holds, per column, a cell spanning into the following rows and how many rows it
+	// still covers
+	type carriedCell struct {
+		cell gridCell
+		rows int
+	}
+	var carried []carriedCell
+
+	rows := make([][]gridCell, 0, len(trs))
+	for _, tr := range trs {
+		var row []gridCell
+		takeCarried := func() {
+			for len(row) < len(carried) && carried[len(row)].rows > 0 {
+				carried[len(row)].rows--
+				row = append(row, carried[len(row)].cell)
+			}
+		}
+
+		for _, td := range childElements(tr, "td", "th") {
+			takeCarried()
+			cell := gridCell{text: nodeText(td), header: td.Data == "th"}
+			colspan := spanAttr(td, "colspan", maxColspan)
+			rowspan := spanAttr(td, "rowspan", maxRowspan)
+			for range colspan {
+				if rowspan > 1 {
+					for len(carried) <= len(row) {
+						carried = append(carried, carriedCell{})
+					}
+					carried[len(row)] = carriedCell{cell: cell, rows: rowspan - 1}
+				}
+				row = append(row, cell)
+			}
+		}
+
+		// Cells spanning from previous rows may also follow the row's own cells
+		for len(row) < len(carried) {
+			takeCarried()
+			if len(row) < len(carried) {
+				row = append(row, gridCell{})
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// spanAttr returns a cell's colspan or rowspan, defaulting to 1. A rowspan of 0 spans the rest
+// of the row group.
+func spanAttr(n *html.Node, name string, limit int) int {
+	for _, attr := range n.Attr {
+		if attr.Key != name {
+			continue
+		}
+		span, err := strconv.Atoi(strings.TrimSpace(attr.Val))
+		switch {
+		case err != nil || span < 0:
+			return 1
+		case span == 0:
+			if name == "rowspan" {
+				return limit
+			}
+			return 1
+		default:
+			return min(span, limit)
+		}
+	}
+	return 1
+}
+
+// allHeaderCells reports whether a row consists of <th> cells only
+func allHeaderCells(cells []gridCell) bool {
+	for _, cell := range cells {
+		if !cell.header {
+			return false
+		}
+	}
+	return len(cells) > 0
+}
+
+// cellText returns the text of a row's cell, or "" if the row is shorter
+func cellText(cells []gridCell, col int) string {
+	if col < len(cells) {
+		return cells[col].text
+	}
+	return ""
+}
+
+// childElements returns the child elements of n with one of the given tag names
+func childElements(n *html.Node, tags ...string) []*html.Node {
+	var children []*html.Node
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type != html.ElementNode {
+			continue
+		}
+		for _, tag := range tags {
+			if c.Data == tag {
+				children = append(children, c)
+				break
+			}
+		}
+	}
+	return children
+}
+
+// nodeText returns the whitespace-collapsed text of an element, leaving out nested tables
+func nodeText(n *html.Node) string {
+	var parts []string
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode && (skipTags[n.Data] || n.Data == "table") {
+			return
+		}
+		if n.Type == html.TextNode {
+			parts = append(parts, strings.Fields(n.Data)...)
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		walk(c)
+	}
+	return strings.Join(parts, " ")
+}
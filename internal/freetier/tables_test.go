@@ -0,0 +1,207 @@
+package freetier
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// parseTables returns the tables of an HTML fragment
+func parseTables(t *testing.T, htmlContent string) []Table {
+	t.Helper()
+	page, err := parsePage(htmlContent)
+	if err != nil {
+		t.Fatalf("parsePage failed: %v", err)
+	}
+	return page.Tables
+}
+
+func TestParsePage_Tables(t *testing.T) {
+	tests := []struct {
+		name   string
+		html   string
+		header []string
+		rows   [][]string
+	}{
+		{
+			name: "thead",
+			html: `<table><caption>Cloud Run</caption>
+				<thead><tr><td>Resource</td><td>Free tier</td></tr></thead>
+				<tbody><tr><td>CPU</td><td>180,000 vCPU-seconds</td></tr></tbody></table>`,
+			header: []string{"Resource", "Free tier"},
+			rows:   [][]string{{"CPU", "180,000 vCPU-seconds"}},
+		},
+		{
+			name: "leading th row",
+			html: `<table><tr><th>Resource</th><th>Price</th></tr>
+				<tr><th>Memory</th><td>$0.0000025</td></tr></table>`,
+			header: []string{"Resource", "Price"},
+			// Row headers stay in the body
+			rows: [][]string{{"Memory", "$0.0000025"}},
+		},
+		{
+			name:   "no header",
+			html:   `<table><tr><td>a</td><td>b</td></tr></table>`,
+			header: nil,
+			rows:   [][]string{{"a", "b"}},
+		},
+		{
+			name: "colspan and rowspan",
+			html: `<table>
+				<tr><th rowspan="2">Resource</th><th colspan="2">Free tier</th></tr>
+				<tr><th>Amount</th><th>Period</th></tr>
+				<tr><td rowspan="2">Operations</td><td>5,000</td><td rowspan="2">month</td></tr>
+				<tr><td>50,000</td></tr>
+				<tr><td colspan="3">Footnote</td></tr></table>`,
+			header: []string{"Resource", "Free tier Amount", "Free tier Period"},
+			rows: [][]string{
+				{"Operations", "5,000", "month"},
+				{"Operations", "50,000", "month"},
+				{"Footnote", "Footnote", "Footnote"},
+			},
+		},
+		{
+			name: "span at end of row and ragged rows",
+			html: `<table><tr><td>a</td><td rowspan="0">b</td></tr>
+				<tr><td>c</td></tr><tr><td>d</td><td>e</td><td>f</td></tr></table>`,
+			rows: [][]string{{"a", "b", "", ""}, {"c", "b", "", ""}, {"d", "b", "e", "f"}},
+		},
+		{
+			name: "invalid spans",
+			html: `<table><tr><td colspan="x">a</td><td colspan="0" rowspan="-1">b</td></tr></table>`,
+			rows: [][]string{{"a", "b"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tables := parseTables(t, tt.html)
+			if len(tables) != 1 {
+				t.Fatalf("got %d tables, want 1", len(tables))
+			}
+			if !reflect.DeepEqual(tables[0].Header, tt.header) {
+				t.Errorf("Header = %q, want %q", tables[0].Header, tt.header)
+			}
+			if !reflect.DeepEqual(tables[0].Rows, tt.rows) {
+				t.Errorf("Rows = %q, want %q", tables[0].Rows, tt.rows)
+			}
+		})
+	}
+}
+
+func TestParsePage_NestedAndSkippedTables(t *testing.T) {
+	tables := parseTables(t, `<nav><table><tr><td>menu</td></tr></table></nav>
+		<table><tr><td>outer <table><tr><td>inner</td></tr></table></td></tr></table>`)
+	if len(tables) != 2 {
+		t.Fatalf("got %d tables, want 2", len(tables))
+	}
+	// Nested tables are separate tables, not part of the cell text
+	if tables[0].Rows[0][0] != "outer" || tables[1].Rows[0][0] != "inner" {
+		t.Errorf("tables = %+v", tables)
+	}
+}
+
+func TestParsePage_SpanLimits(t *testing.T) {
+	tables := parseTables(t, `<table><tr><td colspan="100000">a</td></tr></table>`)
+	if len(tables) != 1 || len(tables[0].Rows[0]) != maxColspan {
+		t.Fatalf("colspan was not limited to %d", maxColspan)
+	}
+}
+
+func TestTable_Column(t *testing.T) {
+	table := Table{Header: []string{"Resource", "Free tier (per month)", "Price"}}
+	if got := table.Column("free TIER"); got != 1 {
+		t.Errorf("Column(free TIER) = %d, want 1", got)
+	}
+	if got := table.Column("region"); got != -1 {
+		t.Errorf("Column(region) = %d, want -1", got)
+	}
+}
+
+func TestExtractFreeTierItems_Tables(t *testing.T) {
+	tests := []struct {
+		name     string
+		html     string
+		expected []FreeTierItem
+	}{
+		{
+			name: "free tier column",
+			html: `<table><tr><th>Resource</th><th>Free tier (per month)</th><th>Price above free tier</th></tr>
+				<tr><td>CPU</td><td>180,000 vCPU-seconds</td><td>$0.000024 / vCPU-second</td></tr>
+				<tr><td>Memory</td><td>360,000</td><td>$0.0000025 / GiB-second</td></tr>
+				<tr><td>Requests</td><td>2 million</td><td>$0.40 / million</td></tr></table>`,
+			expected: []FreeTierItem{
+				{Resource: "vCPU-seconds", Amount: 180000, Unit: "seconds", Period: PeriodMonth},
+				{Resource: "GiB-seconds", Amount: 360000, Unit: "seconds", Period: PeriodMonth},
+				{Resource: "requests", Amount: 2000000, Unit: "count", Period: PeriodMonth},
+			},
+		},
+		{
+			name: "free tier table",
+			html: `<table><tr><th>Free tier</th><th>Quota</th></tr>
+				<tr><td>Stored data</td><td>1 GiB</td></tr>
+				<tr><td>Document reads</td><td>50,000 per day</td></tr>
+				<tr><td>Network egress</td><td>10 GiB per month</td></tr></table>`,
+			expected: []FreeTierItem{
+				{Resource: "storage", Amount: 1, Unit: "GiB"},
+				{Resource: "document-reads", Amount: 50000, Unit: "count", Period: PeriodDay},
+				{Resource: "egress", Amount: 10, Unit: "GiB", Period: PeriodMonth},
+			},
+		},
+		{
+			name: "free tier row",
+			html: `<table><tr><th>Tier</th><th>Queries</th><th>Storage</th></tr>
+				<tr><td>Free usage</td><td>1 TB</td><td>10 GB</td></tr>
+				<tr><td>On-demand</td><td>$6.25</td><td>$0.02</td></tr></table>`,
+			expected: []FreeTierItem{
+				{Resource: "query-processing", Amount: 1, Unit: "TiB"},
+				{Resource: "storage", Amount: 10, Unit: "GiB"},
+			},
+		},
+		{
+			name: "price table",
+			html: `<table><tr><th>Resource</th><th>Price</th></tr>
+				<tr><td>Requests</td><td>$0.40 per million</td></tr></table>`,
+			expected: nil,
+		},
+		{
+			name: "data amount for a count resource",
+			html: `<table><tr><th>Resource</th><th>Free</th></tr>
+				<tr><td>Requests</td><td>5 GB</td></tr></table>`,
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items := ExtractFreeTierItems("", parseTables(t, tt.html)...)
+			if !reflect.DeepEqual(items, tt.expected) {
+				t.Errorf("ExtractFreeTierItems() = %+v, want %+v", items, tt.expected)
+			}
+		})
+	}
+}
+
+func TestExtractFreeTierItems_ProseAndTables(t *testing.T) {
+	page, err := parsePage(`<p>The first 2 million requests per month are free.</p>
+		<table><tr><th>Resource</th><th>Free tier</th></tr>
+		<tr><td>Requests</td><td>2,000,000</td></tr>
+		<tr><td>Memory</td><td>360,000 GiB-seconds free</td></tr></table>`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(page.Text, "Memory 360,000 GiB-seconds free") {
+		t.Errorf("tables are no longer part of the text: %q", page.Text)
+	}
+
+	// An amount stated both in prose and in the table, or in a cell read as prose and as a
+	// table entry, is listed once
+	items := ExtractFreeTierItems(page.Text, page.Tables...)
+	expected := []FreeTierItem{
+		{Resource: "GiB-seconds", Amount: 360000, Unit: "seconds"},
+		{Resource: "requests", Amount: 2000000, Unit: "count"},
+	}
+	if !reflect.DeepEqual(items, expected) {
+		t.Errorf("ExtractFreeTierItems() = %+v, want %+v", items, expected)
+	}
+}
@@ -0,0 +1,48 @@
+package freetier
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nozomi-koborinai/gcp-cost-mcp-server/internal/money"
+	"github.com/nozomi-koborinai/gcp-cost-mcp-server/internal/units"
+)
+
+// ItemUnit returns the unit of measure of a free tier item's amount. Items extracted from
+// documentation carry the unit of their number ("seconds", "GiB"), so the resource completes it:
+// an amount of "GiB-seconds" is measured in GiBy.s.
+func ItemUnit(item *FreeTierItem) (units.Unit, error) {
+	if strings.Contains(strings.ToLower(item.Resource), "gib-seconds") {
+		return units.Parse("GiBy.s")
+	}
+	return units.Parse(item.Unit)
+}
+
+// ConversionFactor returns how many SKU units one unit of a free tier item's allowance covers.
+// Items without a unit are assumed to be measured in the SKU's unit. Storage allowances are
+// documented as an amount of data kept for a month (e.g. "5 GiB per month"), so an allowance
+// measured in data converts to byte-time units such as GiBy.mo as data-months.
+func ConversionFactor(item *FreeTierItem, skuUnit string) (money.Decimal, error) {
+	if item.Unit == "" {
+		return money.FromInt(1), nil
+	}
+	from, err := ItemUnit(item)
+	if err != nil {
+		return money.Zero, fmt.Errorf("failed to parse free tier unit: %w", err)
+	}
+	to, err := units.Parse(skuUnit)
+	if err != nil {
+		return money.Zero, fmt.Errorf("failed to parse SKU unit: %w", err)
+	}
+
+	if from.Dimension == units.Data && to.Dimension == units.DataDuration {
+		from = from.Mul(units.MustParse("mo"))
+	}
+	return units.Convert(money.FromInt(1), from, to)
+}
+
+// convertible reports whether a free tier item's allowance can be deducted from usage in skuUnit
+func convertible(item *FreeTierItem, skuUnit string) bool {
+	_, err := ConversionFactor(item, skuUnit)
+	return err == nil
+}
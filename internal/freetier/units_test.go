@@ -0,0 +1,70 @@
+package freetier
+
+import (
+	"testing"
+)
+
+func TestConversionFactor(t *testing.T) {
+	tests := []struct {
+		name    string
+		item    FreeTierItem
+		skuUnit string
+		want    string
+		wantErr bool
+	}{
+		{"vCPU-seconds to s", FreeTierItem{Resource: "vCPU-seconds", Unit: "seconds"}, "s", "1", false},
+		{"vCPU-seconds to h", FreeTierItem{Resource: "vCPU-seconds", Unit: "seconds"}, "h", "0.000277777777777778", false},
+		{"GiB-seconds to GiBy.s", FreeTierItem{Resource: "GiB-seconds", Unit: "seconds"}, "GiBy.s", "1", false},
+		{"GiB-seconds to GiBy.h", FreeTierItem{Resource: "GiB-seconds", Unit: "seconds"}, "GiBy.h", "0.000277777777777778", false},
+		{"GiB-seconds to s", FreeTierItem{Resource: "GiB-seconds", Unit: "seconds"}, "s", "", true},
+		{"storage to GiBy", FreeTierItem{Resource: "storage", Unit: "GiB"}, "GiBy", "1", false},
+		{"storage to GiBy.mo", FreeTierItem{Resource: "storage", Unit: "GiB"}, "GiBy.mo", "1", false},
+		{"storage to GiBy.d", FreeTierItem{Resource: "storage", Unit: "GiB"}, "GiBy.d", "30.416666666666666667", false},
+		{"query processing to GiBy", FreeTierItem{Resource: "query-processing", Unit: "TiB"}, "GiBy", "1024", false},
+		{"requests to count", FreeTierItem{Resource: "requests", Unit: "count"}, "1", "1", false},
+		{"no unit", FreeTierItem{Resource: "requests"}, "count", "1", false},
+		{"credit", FreeTierItem{Resource: "cluster-credit", Unit: "USD"}, "h", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ConversionFactor(&tt.item, tt.skuUnit)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ConversionFactor() = %s, want error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ConversionFactor() error: %v", err)
+			}
+			if got.String() != tt.want {
+				t.Errorf("ConversionFactor() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindMatchingFreeTierItem_PrefersConvertible(t *testing.T) {
+	freeTier := &FreeTierInfo{
+		Items: []FreeTierItem{
+			{Resource: "GiB-seconds", Amount: 360000, Unit: "seconds"},
+			{Resource: "storage", Amount: 5, Unit: "GiB"},
+		},
+	}
+
+	// GiBy usage is storage, not memory time, although both resources are candidates
+	if got := FindMatchingFreeTierItem(freeTier, "GiBy"); got == nil || got.Resource != "storage" {
+		t.Errorf("FindMatchingFreeTierItem(GiBy) = %+v, want storage", got)
+	}
+	if got := FindMatchingFreeTierItem(freeTier, "GiBy.mo"); got == nil || got.Resource != "storage" {
+		t.Errorf("FindMatchingFreeTierItem(GiBy.mo) = %+v, want storage", got)
+	}
+	// Units outside the resource mapping match items whose amount converts to them
+	if got := FindMatchingFreeTierItem(freeTier, "MiBy"); got == nil || got.Resource != "storage" {
+		t.Errorf("FindMatchingFreeTierItem(MiBy) = %+v, want storage", got)
+	}
+	if got := FindMatchingFreeTierItem(freeTier, "GiBy.h"); got == nil || got.Resource != "GiB-seconds" {
+		t.Errorf("FindMatchingFreeTierItem(GiBy.h) = %+v, want GiB-seconds", got)
+	}
+}
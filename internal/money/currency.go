@@ -0,0 +1,33 @@
+package money
+
+import "strings"
+
+// minorUnits lists ISO-4217 currencies whose minor unit is not 2 decimal places
+var minorUnits = map[string]int{
+	"BHD": 3, "CLP": 0, "IDR": 0, "IQD": 3, "ISK": 0, "JOD": 3,
+	"JPY": 0, "KRW": 0, "KWD": 3, "LYD": 3, "OMR": 3, "PYG": 0,
+	"TND": 3, "UGX": 0, "VND": 0, "XAF": 0, "XOF": 0,
+}
+
+// MinorUnits returns the number of fraction digits of the currency's minor unit
+// (e.g. 2 for USD cents, 0 for JPY). Unknown currencies default to 2.
+func MinorUnits(currencyCode string) int {
+	if digits, ok := minorUnits[strings.ToUpper(currencyCode)]; ok {
+		return digits
+	}
+	return 2
+}
+
+// RoundToCurrency rounds d to the currency's minor unit
+func RoundToCurrency(d Decimal, currencyCode string, mode RoundingMode) Decimal {
+	return d.Round(MinorUnits(currencyCode), mode)
+}
+
+// Format formats d as an amount in the currency's minor unit, e.g. "63.07 USD" or "1234 JPY"
+func Format(d Decimal, currencyCode string, mode RoundingMode) string {
+	amount := d.StringFixed(MinorUnits(currencyCode), mode)
+	if currencyCode == "" {
+		return amount
+	}
+	return amount + " " + strings.ToUpper(currencyCode)
+}
@@ -0,0 +1,215 @@
+// Package money provides exact decimal arithmetic for prices and costs.
+package money
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// nanosPerUnit is the number of nanos in one currency unit
+const nanosPerUnit = 1_000_000_000
+
+// maxFractionDigits bounds the digits printed for non-terminating decimals
+const maxFractionDigits = 18
+
+// Decimal is an exact decimal number. The zero value is 0.
+// Decimals are immutable; all operations return a new value.
+type Decimal struct {
+	r *big.Rat
+}
+
+// Zero is the decimal 0
+var Zero = Decimal{}
+
+// New creates a decimal from whole units and nanos (10^-9 units), following the
+// google.type.Money convention that nanos has the same sign as units
+func New(units int64, nanos int64) Decimal {
+	r := new(big.Rat).SetFrac64(nanos, nanosPerUnit)
+	return Decimal{r: r.Add(r, new(big.Rat).SetInt64(units))}
+}
+
+// FromInt creates a decimal from an integer
+func FromInt(v int64) Decimal {
+	return Decimal{r: new(big.Rat).SetInt64(v)}
+}
+
+// FromFloat creates a decimal from the shortest decimal representation of f,
+// so that FromFloat(0.1) is exactly 0.1
+func FromFloat(f float64) Decimal {
+	d, err := Parse(strconv.FormatFloat(f, 'g', -1, 64))
+	if err != nil {
+		// Only NaN and infinities fail to parse
+		return Zero
+	}
+	return d
+}
+
+// Parse parses a decimal string such as "12", "-0.000024" or "1e6"
+func Parse(s string) (Decimal, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return Zero, nil
+	}
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return Zero, fmt.Errorf("invalid decimal %q", s)
+	}
+	return Decimal{r: r}, nil
+}
+
+// MustParse is like Parse but panics on invalid input. It is intended for constants and tests.
+func MustParse(s string) Decimal {
+	d, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+// rat returns the underlying value, treating the zero Decimal as 0
+func (d Decimal) rat() *big.Rat {
+	if d.r == nil {
+		return new(big.Rat)
+	}
+	return d.r
+}
+
+// Add returns d + other
+func (d Decimal) Add(other Decimal) Decimal {
+	return Decimal{r: new(big.Rat).Add(d.rat(), other.rat())}
+}
+
+// Sub returns d - other
+func (d Decimal) Sub(other Decimal) Decimal {
+	return Decimal{r: new(big.Rat).Sub(d.rat(), other.rat())}
+}
+
+// Mul returns d * other
+func (d Decimal) Mul(other Decimal) Decimal {
+	return Decimal{r: new(big.Rat).Mul(d.rat(), other.rat())}
+}
+
+// Quo returns d / other. It panics if other is zero.
+func (d Decimal) Quo(other Decimal) Decimal {
+	if other.IsZero() {
+		panic("money: division by zero")
+	}
+	return Decimal{r: new(big.Rat).Quo(d.rat(), other.rat())}
+}
+
+// Neg returns -d
+func (d Decimal) Neg() Decimal {
+	return Decimal{r: new(big.Rat).Neg(d.rat())}
+}
+
+// Cmp compares d and other and returns -1, 0 or +1
+func (d Decimal) Cmp(other Decimal) int {
+	return d.rat().Cmp(other.rat())
+}
+
+// Sign returns -1, 0 or +1 depending on the sign of d
+func (d Decimal) Sign() int {
+	return d.rat().Sign()
+}
+
+// IsZero reports whether d is 0
+func (d Decimal) IsZero() bool {
+	return d.Sign() == 0
+}
+
+// Min returns the smaller of a and b
+func Min(a, b Decimal) Decimal {
+	if a.Cmp(b) <= 0 {
+		return a
+	}
+	return b
+}
+
+// Max returns the larger of a and b
+func Max(a, b Decimal) Decimal {
+	if a.Cmp(b) >= 0 {
+		return a
+	}
+	return b
+}
+
+// Float64 returns the nearest float64 value of d
+func (d Decimal) Float64() float64 {
+	f, _ := d.rat().Float64()
+	return f
+}
+
+// Nanos splits d, rounded to nanos with the given mode, into units and nanos
+func (d Decimal) Nanos(mode RoundingMode) (units int64, nanos int64) {
+	total := d.Mul(FromInt(nanosPerUnit)).Round(0, mode).rat().Num()
+	q, r := new(big.Int).QuoRem(total, big.NewInt(nanosPerUnit), new(big.Int))
+	return q.Int64(), r.Int64()
+}
+
+// String returns the exact decimal representation of d. Values that do not have a
+// finite decimal expansion (e.g. 1/3) are rounded half-even to 18 fraction digits.
+func (d Decimal) String() string {
+	r := d.rat()
+	digits, exact := fractionDigits(r.Denom())
+	if !exact {
+		digits = maxFractionDigits
+	}
+	s := d.Round(digits, HalfEven).rat().FloatString(digits)
+	if strings.Contains(s, ".") {
+		s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	}
+	return s
+}
+
+// StringFixed returns d rounded with mode and formatted with exactly places fraction digits
+func (d Decimal) StringFixed(places int, mode RoundingMode) string {
+	return d.Round(places, mode).rat().FloatString(places)
+}
+
+// MarshalJSON encodes d as a JSON string to preserve precision
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(d.String())), nil
+}
+
+// JSONSchemaAlias describes d as a string in JSON schemas (e.g. of tool outputs), as MarshalJSON encodes it
+func (Decimal) JSONSchemaAlias() any {
+	return ""
+}
+
+// UnmarshalJSON decodes d from a JSON string or number
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if unquoted, err := strconv.Unquote(s); err == nil {
+		s = unquoted
+	}
+	parsed, err := Parse(s)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// fractionDigits returns the number of fraction digits needed to print a fraction
+// with the given denominator exactly, and whether that is possible at all
+func fractionDigits(denom *big.Int) (int, bool) {
+	d := new(big.Int).Set(denom)
+	two, five := big.NewInt(2), big.NewInt(5)
+	var twos, fives int
+	mod := new(big.Int)
+	for d.Cmp(big.NewInt(1)) != 0 {
+		switch {
+		case mod.Mod(d, two).Sign() == 0:
+			d.Quo(d, two)
+			twos++
+		case mod.Mod(d, five).Sign() == 0:
+			d.Quo(d, five)
+			fives++
+		default:
+			return 0, false
+		}
+	}
+	return max(twos, fives), true
+}
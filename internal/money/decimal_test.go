@@ -0,0 +1,149 @@
+package money
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestDecimal_ExactArithmetic(t *testing.T) {
+	// 0.1 + 0.2 is exactly 0.3, unlike float64
+	sum := FromFloat(0.1).Add(FromFloat(0.2))
+	if sum.Cmp(MustParse("0.3")) != 0 {
+		t.Errorf("0.1 + 0.2 = %s, want 0.3", sum)
+	}
+
+	// Summing a tiny per-second price a million times must not drift
+	price := New(0, 24000)
+	total := Zero
+	for range 1_000_000 {
+		total = total.Add(price)
+	}
+	if got := total.String(); got != "24" {
+		t.Errorf("total = %s, want 24", got)
+	}
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		units, nanos int64
+		want         string
+	}{
+		{0, 24000, "0.000024"},
+		{1, 500000000, "1.5"},
+		{-1, -750000000, "-1.75"},
+		{12, 0, "12"},
+		{0, 0, "0"},
+	}
+	for _, tt := range tests {
+		if got := New(tt.units, tt.nanos).String(); got != tt.want {
+			t.Errorf("New(%d, %d) = %s, want %s", tt.units, tt.nanos, got, tt.want)
+		}
+	}
+}
+
+func TestDecimal_String(t *testing.T) {
+	tests := []struct {
+		value Decimal
+		want  string
+	}{
+		{MustParse("1e6"), "1000000"},
+		{MustParse("0.4").Quo(MustParse("1000000")), "0.0000004"},
+		{MustParse("0.12").Quo(MustParse("1073741824")), "0.000000000111758708953857421875"},
+		{FromInt(1).Quo(FromInt(3)), "0.333333333333333333"},
+		{Zero, "0"},
+	}
+	for _, tt := range tests {
+		if got := tt.value.String(); got != tt.want {
+			t.Errorf("String() = %s, want %s", got, tt.want)
+		}
+	}
+}
+
+func TestDecimal_Round(t *testing.T) {
+	tests := []struct {
+		value  string
+		places int
+		mode   RoundingMode
+		want   string
+	}{
+		{"2.345", 2, HalfEven, "2.34"},
+		{"2.355", 2, HalfEven, "2.36"},
+		{"2.345", 2, HalfUp, "2.35"},
+		{"-2.345", 2, HalfUp, "-2.35"},
+		{"-2.345", 2, HalfEven, "-2.34"},
+		{"2.349", 2, Down, "2.34"},
+		{"-2.349", 2, Down, "-2.34"},
+		{"2.341", 2, Up, "2.35"},
+		{"-2.341", 2, Up, "-2.35"},
+		{"2.5", 0, HalfEven, "2"},
+		{"3.5", 0, HalfEven, "4"},
+		{"1.20", 2, Up, "1.2"},
+	}
+	for _, tt := range tests {
+		got := MustParse(tt.value).Round(tt.places, tt.mode).String()
+		if got != tt.want {
+			t.Errorf("Round(%s, %d, %s) = %s, want %s", tt.value, tt.places, tt.mode, got, tt.want)
+		}
+	}
+}
+
+func TestDecimal_Nanos(t *testing.T) {
+	units, nanos := MustParse("-1.0000000015").Nanos(HalfUp)
+	if units != -1 || nanos != -2 {
+		t.Errorf("Nanos() = %d, %d; want -1, -2", units, nanos)
+	}
+}
+
+func TestParseRoundingMode(t *testing.T) {
+	for _, mode := range []RoundingMode{HalfEven, HalfUp, Down, Up} {
+		parsed, err := ParseRoundingMode(mode.String())
+		if err != nil || parsed != mode {
+			t.Errorf("ParseRoundingMode(%q) = %v, %v", mode.String(), parsed, err)
+		}
+	}
+	if mode, err := ParseRoundingMode(""); err != nil || mode != HalfEven {
+		t.Errorf("default rounding mode = %v, %v; want half_even", mode, err)
+	}
+	if _, err := ParseRoundingMode("sideways"); err == nil {
+		t.Error("expected error for unknown rounding mode")
+	}
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		value    string
+		currency string
+		mode     RoundingMode
+		want     string
+	}{
+		{"63.072", "USD", HalfEven, "63.07 USD"},
+		{"0.005", "USD", HalfUp, "0.01 USD"},
+		{"0.005", "USD", HalfEven, "0.00 USD"},
+		{"1234.5", "JPY", HalfEven, "1234 JPY"},
+		{"1.2345", "KWD", HalfUp, "1.235 KWD"},
+		{"10", "eur", HalfEven, "10.00 EUR"},
+	}
+	for _, tt := range tests {
+		if got := Format(MustParse(tt.value), tt.currency, tt.mode); got != tt.want {
+			t.Errorf("Format(%s, %s) = %s, want %s", tt.value, tt.currency, got, tt.want)
+		}
+	}
+}
+
+func TestDecimal_JSON(t *testing.T) {
+	data, err := json.Marshal(struct{ V Decimal }{MustParse("0.000024")})
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if string(data) != `{"V":"0.000024"}` {
+		t.Errorf("Marshal = %s", data)
+	}
+
+	var out struct{ A, B Decimal }
+	if err := json.Unmarshal([]byte(`{"A":"1.5","B":2.25}`), &out); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if out.A.String() != "1.5" || out.B.String() != "2.25" {
+		t.Errorf("Unmarshal = %s, %s", out.A, out.B)
+	}
+}
@@ -0,0 +1,92 @@
+package money
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// RoundingMode selects how values are rounded to a number of decimal places
+type RoundingMode int
+
+const (
+	// HalfEven rounds to the nearest value, ties to even (banker's rounding)
+	HalfEven RoundingMode = iota
+	// HalfUp rounds to the nearest value, ties away from zero
+	HalfUp
+	// Down truncates towards zero
+	Down
+	// Up rounds away from zero
+	Up
+)
+
+// String returns the name used for the rounding mode in tool inputs
+func (m RoundingMode) String() string {
+	switch m {
+	case HalfEven:
+		return "half_even"
+	case HalfUp:
+		return "half_up"
+	case Down:
+		return "down"
+	case Up:
+		return "up"
+	default:
+		return fmt.Sprintf("RoundingMode(%d)", int(m))
+	}
+}
+
+// ParseRoundingMode parses a rounding mode name. An empty name selects HalfEven.
+func ParseRoundingMode(name string) (RoundingMode, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "half_even", "bankers":
+		return HalfEven, nil
+	case "half_up":
+		return HalfUp, nil
+	case "down", "truncate":
+		return Down, nil
+	case "up":
+		return Up, nil
+	default:
+		return HalfEven, fmt.Errorf("unknown rounding mode %q (expected half_even, half_up, down or up)", name)
+	}
+}
+
+// Round rounds d to the given number of fraction digits using mode
+func (d Decimal) Round(places int, mode RoundingMode) Decimal {
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(places)), nil)
+
+	// scaled = d * 10^places, split into integer quotient and remainder
+	scaled := new(big.Rat).Mul(d.rat(), new(big.Rat).SetInt(scale))
+	num, den := scaled.Num(), scaled.Denom()
+	q, r := new(big.Int).QuoRem(num, den, new(big.Int))
+	if r.Sign() == 0 {
+		return d
+	}
+
+	// Compare 2*|r| with den to locate the remainder relative to one half
+	half := new(big.Int).Abs(r)
+	half.Lsh(half, 1)
+	cmpHalf := half.Cmp(den)
+
+	awayFromZero := false
+	switch mode {
+	case HalfEven:
+		awayFromZero = cmpHalf > 0 || (cmpHalf == 0 && q.Bit(0) == 1)
+	case HalfUp:
+		awayFromZero = cmpHalf >= 0
+	case Up:
+		awayFromZero = true
+	case Down:
+		awayFromZero = false
+	}
+	if awayFromZero {
+		if num.Sign() < 0 {
+			q.Sub(q, big.NewInt(1))
+		} else {
+			q.Add(q, big.NewInt(1))
+		}
+	}
+
+	return Decimal{r: new(big.Rat).SetFrac(q, scale)}
+}
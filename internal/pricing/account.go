@@ -0,0 +1,136 @@
+package pricing
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// AccountPricingClient is implemented by clients that can read billing-account scoped
+// (contract) pricing from the v1beta Cloud Billing API
+type AccountPricingClient interface {
+	ListBillingAccountSKUs(ctx context.Context, billingAccount string, serviceID string, pageSize int, pageToken string) (*ListSKUsResponse, error)
+	GetBillingAccountSKUPrice(ctx context.Context, billingAccount string, skuID string, currencyCode string) (*BillingAccountPrice, error)
+}
+
+// Ensure Client implements AccountPricingClient interface
+var _ AccountPricingClient = (*Client)(nil)
+
+// BillingAccountPrice is the effective price of a SKU for a billing account
+type BillingAccountPrice struct {
+	Name         string       `json:"name"`
+	CurrencyCode string       `json:"currencyCode"`
+	ValueType    string       `json:"valueType,omitempty"`
+	Rate         *Rate        `json:"rate,omitempty"`
+	PriceReason  *PriceReason `json:"priceReason,omitempty"`
+}
+
+// PriceReason explains how a billing account price was derived from the list price
+type PriceReason struct {
+	// Type is e.g. "default-price", "fixed-discount", "floating-discount", "fixed-price" or "migrated-price"
+	Type             string         `json:"type,omitempty"`
+	FixedDiscount    *PriceDiscount `json:"fixedDiscount,omitempty"`
+	FloatingDiscount *PriceDiscount `json:"floatingDiscount,omitempty"`
+}
+
+// PriceDiscount describes a contractual discount
+type PriceDiscount struct {
+	DiscountPercent Amount `json:"discountPercent,omitempty"`
+	SKUGroup        string `json:"skuGroup,omitempty"`
+}
+
+// billingAccountSKUsResponse is the wire format of a billing account SKU list
+type billingAccountSKUsResponse struct {
+	BillingAccountSKUs []SKU  `json:"billingAccountSkus"`
+	NextPageToken      string `json:"nextPageToken,omitempty"`
+}
+
+// NormalizeBillingAccount returns the billing account ID without the "billingAccounts/" prefix
+func NormalizeBillingAccount(billingAccount string) string {
+	return strings.TrimPrefix(strings.TrimSpace(billingAccount), "billingAccounts/")
+}
+
+// ListBillingAccountSKUs lists the SKUs of a service that are visible to a billing account
+func (c *Client) ListBillingAccountSKUs(ctx context.Context, billingAccount string, serviceID string, pageSize int, pageToken string) (*ListSKUsResponse, error) {
+	billingAccount = NormalizeBillingAccount(billingAccount)
+	if billingAccount == "" {
+		return nil, fmt.Errorf("billingAccount is required")
+	}
+	if serviceID == "" {
+		return nil, fmt.Errorf("serviceID is required")
+	}
+
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+
+	params := url.Values{}
+	params.Set("pageSize", strconv.Itoa(pageSize))
+	params.Set("filter", fmt.Sprintf(`service="services/%s"`, serviceID))
+	if pageToken != "" {
+		params.Set("pageToken", pageToken)
+	}
+
+	reqURL := fmt.Sprintf("%s/v1beta/billingAccounts/%s/skus?%s", c.baseURL, url.PathEscape(billingAccount), params.Encode())
+
+	var result billingAccountSKUsResponse
+	if err := c.doGet(ctx, reqURL, &result); err != nil {
+		return nil, err
+	}
+
+	return &ListSKUsResponse{SKUs: result.BillingAccountSKUs, NextPageToken: result.NextPageToken}, nil
+}
+
+// GetBillingAccountSKUPrice gets the effective (contract) price of a SKU for a billing account
+func (c *Client) GetBillingAccountSKUPrice(ctx context.Context, billingAccount string, skuID string, currencyCode string) (*BillingAccountPrice, error) {
+	billingAccount = NormalizeBillingAccount(billingAccount)
+	if billingAccount == "" {
+		return nil, fmt.Errorf("billingAccount is required")
+	}
+	if skuID == "" {
+		return nil, fmt.Errorf("skuID is required")
+	}
+
+	params := url.Values{}
+	if currencyCode != "" {
+		params.Set("currencyCode", currencyCode)
+	}
+
+	reqURL := fmt.Sprintf("%s/v1beta/billingAccounts/%s/skus/%s/price", c.baseURL, url.PathEscape(billingAccount), skuID)
+	if len(params) > 0 {
+		reqURL = fmt.Sprintf("%s?%s", reqURL, params.Encode())
+	}
+
+	var result BillingAccountPrice
+	if err := c.doGet(ctx, reqURL, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// ContractPricer reads contract prices for one configured billing account
+type ContractPricer struct {
+	client         AccountPricingClient
+	billingAccount string
+}
+
+// NewContractPricer creates a ContractPricer for the given billing account
+func NewContractPricer(client AccountPricingClient, billingAccount string) *ContractPricer {
+	return &ContractPricer{
+		client:         client,
+		billingAccount: NormalizeBillingAccount(billingAccount),
+	}
+}
+
+// BillingAccount returns the billing account ID prices are read for
+func (p *ContractPricer) BillingAccount() string {
+	return p.billingAccount
+}
+
+// GetSKUPrice gets the contract price of a SKU
+func (p *ContractPricer) GetSKUPrice(ctx context.Context, skuID string, currencyCode string) (*BillingAccountPrice, error) {
+	return p.client.GetBillingAccountSKUPrice(ctx, p.billingAccount, skuID, currencyCode)
+}
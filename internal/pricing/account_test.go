@@ -0,0 +1,94 @@
+package pricing
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestClient_GetBillingAccountSKUPrice(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1beta/billingAccounts/0123AB-456789-CDEF01/skus/CPU-SKU/price" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if got := r.URL.Query().Get("currencyCode"); got != "USD" {
+			t.Errorf("currencyCode = %q, want USD", got)
+		}
+		w.Write([]byte(`{
+			"name": "billingAccounts/0123AB-456789-CDEF01/skus/CPU-SKU/price",
+			"currencyCode": "USD",
+			"valueType": "rate",
+			"rate": {
+				"unitInfo": {"unit": "h", "unitQuantity": {"value": "1"}},
+				"tiers": [{"startAmount": {"value": "0"}, "listPrice": {"currencyCode": "USD", "nanos": 24000000}}]
+			},
+			"priceReason": {"type": "fixed-discount", "fixedDiscount": {"discountPercent": {"value": "20"}}}
+		}`))
+	})
+
+	price, err := client.GetBillingAccountSKUPrice(context.Background(), "billingAccounts/0123AB-456789-CDEF01", "CPU-SKU", "USD")
+	if err != nil {
+		t.Fatalf("GetBillingAccountSKUPrice failed: %v", err)
+	}
+	if price.PriceReason == nil || price.PriceReason.Type != "fixed-discount" || price.PriceReason.FixedDiscount.DiscountPercent.Value != "20" {
+		t.Errorf("PriceReason = %+v", price.PriceReason)
+	}
+	if got := price.Rate.PricePerUnit(price.Rate.Tiers[0]).String(); got != "0.024" {
+		t.Errorf("price per unit = %s, want 0.024", got)
+	}
+}
+
+func TestClient_ListBillingAccountSKUs(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1beta/billingAccounts/0123AB-456789-CDEF01/skus" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if got := r.URL.Query().Get("filter"); got != `service="services/152E-C115-5142"` {
+			t.Errorf("filter = %q", got)
+		}
+		w.Write([]byte(`{"billingAccountSkus": [{"skuId": "CPU-SKU", "displayName": "CPU"}], "nextPageToken": "next"}`))
+	})
+
+	resp, err := client.ListBillingAccountSKUs(context.Background(), "0123AB-456789-CDEF01", "152E-C115-5142", 0, "")
+	if err != nil {
+		t.Fatalf("ListBillingAccountSKUs failed: %v", err)
+	}
+	if len(resp.SKUs) != 1 || resp.SKUs[0].SKUID != "CPU-SKU" || resp.NextPageToken != "next" {
+		t.Errorf("response = %+v", resp)
+	}
+
+	if _, err := client.ListBillingAccountSKUs(context.Background(), "", "152E-C115-5142", 0, ""); err == nil {
+		t.Error("expected error without billing account")
+	}
+}
+
+func TestCachedClient_BillingAccountPricing(t *testing.T) {
+	var calls int
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Write([]byte(`{"currencyCode": "USD", "rate": {"tiers": [{"listPrice": {"nanos": 1}}]}}`))
+	})
+
+	opts := DefaultCacheOptions()
+	opts.Dir = ""
+	cached, err := NewCachedClient(client, opts)
+	if err != nil {
+		t.Fatalf("NewCachedClient failed: %v", err)
+	}
+
+	pricer := NewContractPricer(cached, "0123AB-456789-CDEF01")
+	for range 2 {
+		if _, err := pricer.GetSKUPrice(context.Background(), "CPU-SKU", "USD"); err != nil {
+			t.Fatalf("GetSKUPrice failed: %v", err)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("API calls = %d, want 1", calls)
+	}
+
+	// Wrapped clients without billing account support fail clearly
+	snapshotCache, _ := NewCachedClient(NewSnapshotClient(testSnapshot()), opts)
+	if _, err := snapshotCache.GetBillingAccountSKUPrice(context.Background(), "X", "CPU-SKU", "USD"); err == nil {
+		t.Error("expected error for client without billing account support")
+	}
+}
@@ -0,0 +1,115 @@
+package pricing
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nozomi-koborinai/gcp-cost-mcp-server/internal/money"
+)
+
+// Aggregation levels and intervals used by AggregationInfo
+const (
+	AggregationLevelAccount    = "ACCOUNT"
+	AggregationLevelProject    = "PROJECT"
+	AggregationIntervalDaily   = "DAILY"
+	AggregationIntervalMonthly = "MONTHLY"
+)
+
+// HoursPerMonth is the average month length Google Cloud uses for monthly estimates
+const HoursPerMonth = 730
+
+// UsagePeriod is the length of time a usage amount accrues over
+type UsagePeriod struct {
+	// Days is the period length in (possibly fractional) days
+	Days money.Decimal
+}
+
+// UsageDays returns a usage period of n days
+func UsageDays(n money.Decimal) UsagePeriod {
+	return UsagePeriod{Days: n}
+}
+
+// UsageMonths returns a usage period of n average (730 hour) months
+func UsageMonths(n int64) UsagePeriod {
+	return UsagePeriod{Days: money.FromInt(n * HoursPerMonth).Quo(money.FromInt(24))}
+}
+
+// IsZero reports whether the period is unset
+func (p UsagePeriod) IsZero() bool {
+	return p.Days.Sign() <= 0
+}
+
+// intervalDays returns the length of an aggregation interval in days, or zero if the interval is unknown
+func intervalDays(interval string) money.Decimal {
+	switch strings.ToUpper(interval) {
+	case AggregationIntervalDaily:
+		return money.FromInt(1)
+	case AggregationIntervalMonthly:
+		return UsageMonths(1).Days
+	}
+	return money.Zero
+}
+
+// IntervalsIn returns the number of the rate's aggregation intervals in period, which is
+// fractional for a period that does not span a whole number of intervals. It is 1 when the
+// period is unset or the rate has no known aggregation interval.
+func (r *Rate) IntervalsIn(period UsagePeriod) money.Decimal {
+	if length := intervalDays(r.AggregationInfo.Interval); length.Sign() > 0 && !period.IsZero() {
+		return period.Days.Quo(length)
+	}
+	return money.FromInt(1)
+}
+
+// CostCalculation is the result of an aggregation-aware cost calculation
+type CostCalculation struct {
+	Cost money.Decimal
+	// AggregationLevel and AggregationInterval are taken from the rate
+	AggregationLevel    string
+	AggregationInterval string
+	// Intervals is the number of aggregation intervals the usage was spread over.
+	// Tiers restart in every interval, so each interval is priced separately. A fraction below 1
+	// means the period covers only part of an interval, whose tier bounds are pro-rated to that part.
+	Intervals money.Decimal
+	// UsagePerInterval and CostPerInterval are the usage and cost of one full interval. For a
+	// period shorter than an interval they are the usage and cost at the same rate over a full interval.
+	UsagePerInterval money.Decimal
+	CostPerInterval  money.Decimal
+	// Tiers holds the usage and cost of each tier summed over all intervals; tier bounds apply per interval
+	Tiers []TierCost
+}
+
+// CalculateCostForPeriod calculates the exact cost of usageAmount accrued over period.
+// Tiers are applied per aggregation interval of the rate: usage is split evenly across the
+// intervals in the period (e.g. per day for DAILY tiers) and each interval is priced on its own.
+// A period shorter than one interval pro-rates the tier bounds to the part of the interval it
+// covers (e.g. a week gets 7/30.42 of a monthly free tier). An unset period or an unknown
+// interval prices the usage as a whole.
+func CalculateCostForPeriod(rate *Rate, usageAmount money.Decimal, period UsagePeriod) (*CostCalculation, error) {
+	if rate == nil {
+		return nil, fmt.Errorf("invalid price data: rate is nil")
+	}
+
+	result := &CostCalculation{
+		AggregationLevel:    strings.ToUpper(rate.AggregationInfo.Level),
+		AggregationInterval: strings.ToUpper(rate.AggregationInfo.Interval),
+		// Pricing the usage per full interval and scaling by the (possibly fractional) number
+		// of intervals is the same as pro-rating the tier bounds, since tiers are linear in between
+		Intervals: rate.IntervalsIn(period),
+	}
+	result.UsagePerInterval = usageAmount.Quo(result.Intervals)
+
+	tiers, err := CalculateTierCosts(rate, result.UsagePerInterval)
+	if err != nil {
+		return nil, err
+	}
+
+	result.CostPerInterval = money.Zero
+	for i := range tiers {
+		result.CostPerInterval = result.CostPerInterval.Add(tiers[i].Cost)
+		tiers[i].Usage = tiers[i].Usage.Mul(result.Intervals)
+		tiers[i].Cost = tiers[i].Cost.Mul(result.Intervals)
+	}
+	result.Cost = result.CostPerInterval.Mul(result.Intervals)
+	result.Tiers = tiers
+	return result, nil
+}
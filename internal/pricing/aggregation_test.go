@@ -0,0 +1,96 @@
+package pricing
+
+import (
+	"testing"
+
+	"github.com/nozomi-koborinai/gcp-cost-mcp-server/internal/money"
+)
+
+// tieredRate returns a rate where the first 100 units of each interval are free and the rest cost 0.01
+func tieredRate(interval string) *Rate {
+	return &Rate{
+		UnitInfo:        UnitInfo{Unit: "GiBy", UnitQuantity: Amount{Value: "1"}},
+		AggregationInfo: AggregationInfo{Level: "ACCOUNT", Interval: interval},
+		Tiers: []Tier{
+			{StartAmount: Amount{Value: "0"}},
+			{StartAmount: Amount{Value: "100"}, ListPrice: Money{CurrencyCode: "USD", Nanos: 10000000}},
+		},
+	}
+}
+
+func TestCalculateCostForPeriod(t *testing.T) {
+	tests := []struct {
+		name          string
+		rate          *Rate
+		usage         string
+		period        UsagePeriod
+		wantIntervals string
+		wantCost      string
+	}{
+		// 30 days x (200 - 100 free) x 0.01
+		{"daily tiers split per day", tieredRate("DAILY"), "6000", UsageDays(money.FromInt(30)), "30", "30"},
+		// Usage below the daily free tier on every day is free even though the monthly total is not
+		{"daily tiers below free tier each day", tieredRate("DAILY"), "2400", UsageDays(money.FromInt(30)), "30", "0"},
+		// Half a day gets half of the daily free tier: (300 - 50 free) x 0.01
+		{"daily tiers within half a day", tieredRate("DAILY"), "300", UsageDays(money.MustParse("0.5")), "0.5", "2.5"},
+		{"monthly tiers within one month", tieredRate("MONTHLY"), "6000", UsageMonths(1), "1", "59"},
+		// Two months x (3000 - 100 free) x 0.01
+		{"monthly tiers over two months", tieredRate("MONTHLY"), "6000", UsageMonths(2), "2", "58"},
+		{"no period prices usage as a whole", tieredRate("DAILY"), "6000", UsagePeriod{}, "1", "59"},
+		{"no aggregation interval", tieredRate(""), "6000", UsageMonths(1), "1", "59"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CalculateCostForPeriod(tt.rate, money.MustParse(tt.usage), tt.period)
+			if err != nil {
+				t.Fatalf("CalculateCostForPeriod failed: %v", err)
+			}
+			if got.Intervals.Cmp(money.MustParse(tt.wantIntervals)) != 0 {
+				t.Errorf("Intervals = %s, want %s", got.Intervals, tt.wantIntervals)
+			}
+			if got.Cost.Cmp(money.MustParse(tt.wantCost)) != 0 {
+				t.Errorf("Cost = %s, want %s", got.Cost, tt.wantCost)
+			}
+		})
+	}
+
+	if _, err := CalculateCostForPeriod(nil, money.FromInt(1), UsageMonths(1)); err == nil {
+		t.Error("expected error for nil rate")
+	}
+}
+
+func TestCalculateCostForPeriod_ShorterThanInterval(t *testing.T) {
+	// A week is 7 / (730 / 24) = 84/365 of a month, so it gets that share of the monthly free tier
+	got, err := CalculateCostForPeriod(tieredRate("MONTHLY"), money.FromInt(6000), UsageDays(money.FromInt(7)))
+	if err != nil {
+		t.Fatalf("CalculateCostForPeriod failed: %v", err)
+	}
+
+	share := money.FromInt(84).Quo(money.FromInt(365))
+	if got.Intervals.Cmp(share) != 0 {
+		t.Errorf("Intervals = %s, want 84/365", got.Intervals)
+	}
+	freeUsage := money.FromInt(100).Mul(share)
+	if len(got.Tiers) != 2 || got.Tiers[0].Usage.Cmp(freeUsage) != 0 {
+		t.Fatalf("Tiers = %+v, want %s free units", got.Tiers, freeUsage)
+	}
+	// (6000 - 100 x 84/365) x 0.01
+	wantCost := money.FromInt(6000).Sub(freeUsage).Mul(money.MustParse("0.01"))
+	if got.Cost.Cmp(wantCost) != 0 {
+		t.Errorf("Cost = %s, want %s", got.Cost, wantCost)
+	}
+	if got.Tiers[0].Usage.Add(got.Tiers[1].Usage).Cmp(money.FromInt(6000)) != 0 {
+		t.Errorf("tier usage = %+v, want 6000 in total", got.Tiers)
+	}
+	if got.CostPerInterval.Mul(got.Intervals).Cmp(got.Cost) != 0 {
+		t.Errorf("CostPerInterval = %s, does not scale to Cost %s", got.CostPerInterval, got.Cost)
+	}
+}
+
+func TestUsageMonths(t *testing.T) {
+	// 730 hours / 24
+	if got := UsageMonths(1).Days.String(); got != "30.416666666666666667" {
+		t.Errorf("UsageMonths(1).Days = %s", got)
+	}
+}
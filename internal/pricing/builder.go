@@ -0,0 +1,222 @@
+package pricing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// DefaultBuildConcurrency is the default number of concurrent price requests while building a snapshot
+const DefaultBuildConcurrency = 8
+
+// BuildOptions configures BuildSnapshot
+type BuildOptions struct {
+	// Services restricts the snapshot to these services, matched by service ID or
+	// (case-insensitive) display name. Empty means all services.
+	Services []string
+	// CurrencyCodes are the currencies to fetch prices in (default: USD)
+	CurrencyCodes []string
+	// Concurrency bounds the number of in-flight price requests (default: DefaultBuildConcurrency)
+	Concurrency int
+	// Resume is a partial snapshot from an interrupted build. Services it already
+	// contains SKUs for are not fetched again; services no longer selected are dropped.
+	Resume *Snapshot
+	// Checkpoint, if set, is called after each completed service with the snapshot built so far
+	Checkpoint func(snapshot *Snapshot, done, total int) error
+}
+
+// BuildSnapshot crawls the catalog through client and returns it as a snapshot.
+// Services are processed one at a time; the prices of each service's SKUs are fetched concurrently.
+func BuildSnapshot(ctx context.Context, client PricingClient, opts BuildOptions) (*Snapshot, error) {
+	currencies := opts.CurrencyCodes
+	if len(currencies) == 0 {
+		currencies = []string{"USD"}
+	}
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultBuildConcurrency
+	}
+
+	if opts.Resume != nil && !slices.Equal(opts.Resume.Currencies, currencies) {
+		return nil, fmt.Errorf("cannot resume snapshot built for currencies %v with %v", opts.Resume.Currencies, currencies)
+	}
+
+	services, err := listAllServices(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+	services, err = selectServices(services, opts.Services)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := NewSnapshot()
+	snapshot.Currencies = currencies
+	snapshot.Services = services
+	if opts.Resume != nil {
+		resumeServices(snapshot, opts.Resume)
+	}
+
+	for i, service := range services {
+		if _, ok := snapshot.SKUs[service.ServiceID]; ok {
+			continue // completed by a previous run
+		}
+
+		skus, err := listAllSKUs(ctx, client, service.ServiceID)
+		if err != nil {
+			return nil, err
+		}
+		if err := fetchPrices(ctx, client, snapshot, skus, currencies, concurrency); err != nil {
+			return nil, fmt.Errorf("failed to fetch prices for %s: %w", service.DisplayName, err)
+		}
+		// Recording the SKUs last marks the service as complete for resumption
+		snapshot.SKUs[service.ServiceID] = skus
+
+		if opts.Checkpoint != nil {
+			if err := opts.Checkpoint(snapshot, i+1, len(services)); err != nil {
+				return nil, fmt.Errorf("failed to save checkpoint: %w", err)
+			}
+		}
+	}
+
+	return snapshot, nil
+}
+
+// resumeServices copies the services of a partial snapshot that are complete and still selected,
+// with the prices of their SKUs. Other services' SKUs and prices are not carried over.
+func resumeServices(snapshot, partial *Snapshot) {
+	for _, service := range snapshot.Services {
+		skus, ok := partial.SKUs[service.ServiceID]
+		if !ok {
+			continue
+		}
+		snapshot.SKUs[service.ServiceID] = skus
+		for _, sku := range skus {
+			for currency, prices := range partial.Prices {
+				if price, ok := prices[sku.SKUID]; ok {
+					snapshot.SetPrice(sku.SKUID, currency, price)
+				}
+			}
+		}
+	}
+}
+
+// listAllServices lists every service, following pagination
+func listAllServices(ctx context.Context, client PricingClient) ([]Service, error) {
+	var services []Service
+	pageToken := ""
+	for {
+		resp, err := client.ListServices(ctx, DefaultPageSize, pageToken)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list services: %w", err)
+		}
+		services = append(services, resp.Services...)
+		if resp.NextPageToken == "" {
+			return services, nil
+		}
+		pageToken = resp.NextPageToken
+	}
+}
+
+// listAllSKUs lists every SKU of a service, following pagination
+func listAllSKUs(ctx context.Context, client PricingClient, serviceID string) ([]SKU, error) {
+	skus := []SKU{}
+	pageToken := ""
+	for {
+		resp, err := client.ListSKUs(ctx, serviceID, DefaultPageSize, pageToken)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list SKUs for %s: %w", serviceID, err)
+		}
+		skus = append(skus, resp.SKUs...)
+		if resp.NextPageToken == "" {
+			return skus, nil
+		}
+		pageToken = resp.NextPageToken
+	}
+}
+
+// selectServices filters services by ID or display name. Every selector must match a service.
+func selectServices(services []Service, selectors []string) ([]Service, error) {
+	if len(selectors) == 0 {
+		return services, nil
+	}
+
+	var selected []Service
+	for _, selector := range selectors {
+		found := false
+		for _, service := range services {
+			if service.ServiceID == selector || strings.EqualFold(service.DisplayName, selector) {
+				if !slices.ContainsFunc(selected, func(s Service) bool { return s.ServiceID == service.ServiceID }) {
+					selected = append(selected, service)
+				}
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("service %q not found: %w", selector, ErrNotFound)
+		}
+	}
+	return selected, nil
+}
+
+// fetchPrices fetches the prices of skus in every currency with bounded concurrency.
+// SKUs without a price are skipped.
+func fetchPrices(ctx context.Context, client PricingClient, snapshot *Snapshot, skus []SKU, currencies []string, concurrency int) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type job struct {
+		skuID    string
+		currency string
+	}
+	jobs := make(chan job)
+
+	var (
+		mu       sync.Mutex
+		firstErr error
+		wg       sync.WaitGroup
+	)
+	for range concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				price, err := client.GetSKUPrice(ctx, j.skuID, j.currency)
+
+				mu.Lock()
+				switch {
+				case err == nil:
+					snapshot.SetPrice(j.skuID, j.currency, price)
+				case errors.Is(err, ErrNotFound):
+					log.Printf("Snapshot: no %s price for SKU %s, skipping", j.currency, j.skuID)
+				case firstErr == nil:
+					firstErr = err
+					cancel()
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+feed:
+	for _, sku := range skus {
+		for _, currency := range currencies {
+			select {
+			case jobs <- job{skuID: sku.SKUID, currency: currency}:
+			case <-ctx.Done():
+				break feed
+			}
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+	return ctx.Err()
+}
@@ -0,0 +1,141 @@
+package pricing
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+)
+
+// priceCountingClient counts GetSKUPrice calls and optionally fails them
+type priceCountingClient struct {
+	PricingClient
+	priceCalls atomic.Int64
+	priceErr   error
+}
+
+func (c *priceCountingClient) GetSKUPrice(ctx context.Context, skuID string, currencyCode string) (*GetPriceResponse, error) {
+	c.priceCalls.Add(1)
+	if c.priceErr != nil {
+		return nil, c.priceErr
+	}
+	return c.PricingClient.GetSKUPrice(ctx, skuID, currencyCode)
+}
+
+func TestBuildSnapshot(t *testing.T) {
+	source := testSnapshot()
+	source.SKUs["9662-B51E-5089"] = []SKU{{SKUID: "SQL-SKU", DisplayName: "Cloud SQL vCPU"}}
+	client := &priceCountingClient{PricingClient: NewSnapshotClient(source)}
+
+	var checkpoints int
+	snapshot, err := BuildSnapshot(context.Background(), client, BuildOptions{
+		Services:    []string{"cloud run", "9662-B51E-5089"},
+		Concurrency: 2,
+		Checkpoint: func(s *Snapshot, done, total int) error {
+			checkpoints++
+			if total != 2 {
+				t.Errorf("total = %d, want 2", total)
+			}
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("BuildSnapshot failed: %v", err)
+	}
+
+	if len(snapshot.Services) != 2 {
+		t.Errorf("got %d services, want 2", len(snapshot.Services))
+	}
+	if len(snapshot.SKUs["152E-C115-5142"]) != 3 || len(snapshot.SKUs["9662-B51E-5089"]) != 1 {
+		t.Errorf("unexpected SKUs: %+v", snapshot.SKUs)
+	}
+	// Only CPU-SKU has a price in the source; the rest are skipped
+	if _, ok := snapshot.Price("CPU-SKU", "USD"); !ok {
+		t.Error("CPU-SKU price missing")
+	}
+	if got := client.priceCalls.Load(); got != 4 {
+		t.Errorf("price calls = %d, want 4", got)
+	}
+	if checkpoints != 2 {
+		t.Errorf("checkpoints = %d, want 2", checkpoints)
+	}
+}
+
+func TestBuildSnapshot_UnknownService(t *testing.T) {
+	client := NewSnapshotClient(testSnapshot())
+
+	_, err := BuildSnapshot(context.Background(), client, BuildOptions{Services: []string{"BigQuery"}})
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestBuildSnapshot_Resume(t *testing.T) {
+	client := &priceCountingClient{PricingClient: NewSnapshotClient(testSnapshot())}
+
+	partial := NewSnapshot()
+	partial.Currencies = []string{"USD"}
+	partial.SKUs["152E-C115-5142"] = testSnapshot().SKUs["152E-C115-5142"]
+
+	snapshot, err := BuildSnapshot(context.Background(), client, BuildOptions{
+		Services: []string{"152E-C115-5142"},
+		Resume:   partial,
+	})
+	if err != nil {
+		t.Fatalf("BuildSnapshot failed: %v", err)
+	}
+	if got := client.priceCalls.Load(); got != 0 {
+		t.Errorf("price calls = %d, want 0 for completed service", got)
+	}
+	if len(snapshot.SKUs["152E-C115-5142"]) != 3 {
+		t.Errorf("resumed SKUs lost: %+v", snapshot.SKUs)
+	}
+
+	// Services no longer selected are pruned with their prices
+	partial.SKUs["9662-B51E-5089"] = []SKU{{SKUID: "SQL-SKU", DisplayName: "Cloud SQL vCPU"}}
+	partial.SetPrice("SQL-SKU", "USD", &GetPriceResponse{Name: "skus/SQL-SKU/price", CurrencyCode: "USD"})
+	partial.SetPrice("CPU-SKU", "USD", &GetPriceResponse{Name: "skus/CPU-SKU/price", CurrencyCode: "USD"})
+	snapshot, err = BuildSnapshot(context.Background(), client, BuildOptions{
+		Services: []string{"152E-C115-5142"},
+		Resume:   partial,
+	})
+	if err != nil {
+		t.Fatalf("BuildSnapshot failed: %v", err)
+	}
+	if _, ok := snapshot.SKUs["9662-B51E-5089"]; ok || len(snapshot.SKUs) != 1 {
+		t.Errorf("SKUs of unselected services kept: %+v", snapshot.SKUs)
+	}
+	if _, ok := snapshot.Price("SQL-SKU", "USD"); ok {
+		t.Error("price of an unselected service kept")
+	}
+	if _, ok := snapshot.Price("CPU-SKU", "USD"); !ok {
+		t.Error("price of a resumed service lost")
+	}
+
+	_, err = BuildSnapshot(context.Background(), client, BuildOptions{
+		CurrencyCodes: []string{"JPY"},
+		Resume:        partial,
+	})
+	if err == nil {
+		t.Error("expected error resuming with different currencies")
+	}
+}
+
+func TestBuildSnapshot_PriceError(t *testing.T) {
+	client := &priceCountingClient{
+		PricingClient: NewSnapshotClient(testSnapshot()),
+		priceErr:      ErrPermissionDenied,
+	}
+
+	var checkpoints int
+	_, err := BuildSnapshot(context.Background(), client, BuildOptions{
+		Services:   []string{"152E-C115-5142"},
+		Checkpoint: func(*Snapshot, int, int) error { checkpoints++; return nil },
+	})
+	if !errors.Is(err, ErrPermissionDenied) {
+		t.Errorf("expected ErrPermissionDenied, got %v", err)
+	}
+	if checkpoints != 0 {
+		t.Errorf("incomplete service was checkpointed")
+	}
+}
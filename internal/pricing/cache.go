@@ -0,0 +1,383 @@
+package pricing
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/nozomi-koborinai/gcp-cost-mcp-server/internal/money"
+)
+
+// CacheOptions configures the catalog cache used by CachedClient
+type CacheOptions struct {
+	// Dir is the directory used to persist cache entries. Empty disables the on-disk store.
+	Dir string
+	// ServicesTTL is how long ListServices responses are cached (0 disables caching)
+	ServicesTTL time.Duration
+	// SKUsTTL is how long ListSKUs responses are cached (0 disables caching)
+	SKUsTTL time.Duration
+	// PriceTTL is how long GetSKUPrice responses are cached (0 disables caching)
+	PriceTTL time.Duration
+}
+
+// DefaultCacheOptions returns the default cache options.
+// The catalog changes rarely, so services and SKUs are kept for a day,
+// while prices are refreshed more often.
+func DefaultCacheOptions() CacheOptions {
+	return CacheOptions{
+		Dir:         DefaultCacheDir(),
+		ServicesTTL: 24 * time.Hour,
+		SKUsTTL:     24 * time.Hour,
+		PriceTTL:    6 * time.Hour,
+	}
+}
+
+// DefaultCacheDir returns the default on-disk cache directory, or an empty string
+// if the user cache directory cannot be determined
+func DefaultCacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "gcp-cost-mcp-server", "catalog")
+}
+
+// cacheEntry is a single cached API response
+type cacheEntry struct {
+	Key       string          `json:"key"`
+	Data      json.RawMessage `json:"data"`
+	CachedAt  time.Time       `json:"cached_at"`
+	ExpiresAt time.Time       `json:"expires_at"`
+}
+
+// IsExpired checks if the cached entry has expired
+func (e *cacheEntry) IsExpired() bool {
+	return time.Now().After(e.ExpiresAt)
+}
+
+// CachedClient wraps a PricingClient with an in-memory cache backed by an optional on-disk store
+type CachedClient struct {
+	client  PricingClient
+	opts    CacheOptions
+	entries map[string]*cacheEntry
+	mutex   sync.RWMutex
+	// namespace prefixes every cache key, see cacheNamespace
+	namespace string
+
+	hits     atomic.Int64
+	diskHits atomic.Int64
+	misses   atomic.Int64
+}
+
+// Ensure CachedClient implements the pricing client interfaces
+var (
+	_ PricingClient        = (*CachedClient)(nil)
+	_ AccountPricingClient = (*CachedClient)(nil)
+	_ PriceListClient      = (*CachedClient)(nil)
+)
+
+// NewCachedClient creates a caching wrapper around the given PricingClient
+func NewCachedClient(client PricingClient, opts CacheOptions) (*CachedClient, error) {
+	if client == nil {
+		return nil, fmt.Errorf("client is required")
+	}
+
+	if opts.Dir != "" {
+		if err := os.MkdirAll(opts.Dir, 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create cache directory: %w", err)
+		}
+	}
+
+	return &CachedClient{
+		client:    client,
+		opts:      opts,
+		entries:   make(map[string]*cacheEntry),
+		namespace: cacheNamespace(client),
+	}, nil
+}
+
+// cacheNamespace returns the cache key prefix for the responses of client. Clients configured
+// with different endpoints or quota projects may see different catalogs, so they must not read
+// each other's entries when they share a cache directory.
+func cacheNamespace(client PricingClient) string {
+	c, ok := client.(*Client)
+	if !ok {
+		return ""
+	}
+	return c.baseURL + "|" + c.quotaProject
+}
+
+// key builds the cache key of a request from its parts
+func (c *CachedClient) key(parts ...string) string {
+	return c.namespace + "|" + strings.Join(parts, "|")
+}
+
+// ListServices lists services, serving from the cache when possible
+func (c *CachedClient) ListServices(ctx context.Context, pageSize int, pageToken string) (*ListServicesResponse, error) {
+	key := c.key("services", strconv.Itoa(pageSize), pageToken)
+	return cachedCall(c, key, c.opts.ServicesTTL, func() (*ListServicesResponse, error) {
+		return c.client.ListServices(ctx, pageSize, pageToken)
+	})
+}
+
+// ListSKUs lists SKUs for a service, serving from the cache when possible
+func (c *CachedClient) ListSKUs(ctx context.Context, serviceID string, pageSize int, pageToken string) (*ListSKUsResponse, error) {
+	key := c.key("skus", serviceID, strconv.Itoa(pageSize), pageToken)
+	return cachedCall(c, key, c.opts.SKUsTTL, func() (*ListSKUsResponse, error) {
+		return c.client.ListSKUs(ctx, serviceID, pageSize, pageToken)
+	})
+}
+
+// GetSKUPrice gets the price for a SKU, serving from the cache when possible
+func (c *CachedClient) GetSKUPrice(ctx context.Context, skuID string, currencyCode string) (*GetPriceResponse, error) {
+	key := c.key("price", skuID, currencyCode)
+	return cachedCall(c, key, c.opts.PriceTTL, func() (*GetPriceResponse, error) {
+		return c.client.GetSKUPrice(ctx, skuID, currencyCode)
+	})
+}
+
+// ListBillingAccountSKUs lists billing account SKUs, serving from the cache when possible.
+// It fails if the wrapped client does not support billing account pricing.
+func (c *CachedClient) ListBillingAccountSKUs(ctx context.Context, billingAccount string, serviceID string, pageSize int, pageToken string) (*ListSKUsResponse, error) {
+	accountClient, err := c.accountClient()
+	if err != nil {
+		return nil, err
+	}
+	key := c.key("account-skus", billingAccount, serviceID, strconv.Itoa(pageSize), pageToken)
+	return cachedCall(c, key, c.opts.SKUsTTL, func() (*ListSKUsResponse, error) {
+		return accountClient.ListBillingAccountSKUs(ctx, billingAccount, serviceID, pageSize, pageToken)
+	})
+}
+
+// GetBillingAccountSKUPrice gets a billing account price, serving from the cache when possible.
+// It fails if the wrapped client does not support billing account pricing.
+func (c *CachedClient) GetBillingAccountSKUPrice(ctx context.Context, billingAccount string, skuID string, currencyCode string) (*BillingAccountPrice, error) {
+	accountClient, err := c.accountClient()
+	if err != nil {
+		return nil, err
+	}
+	key := c.key("account-price", billingAccount, skuID, currencyCode)
+	return cachedCall(c, key, c.opts.PriceTTL, func() (*BillingAccountPrice, error) {
+		return accountClient.GetBillingAccountSKUPrice(ctx, billingAccount, skuID, currencyCode)
+	})
+}
+
+// ListPrices lists the published prices of a SKU, serving from the cache when possible.
+// It fails if the wrapped client cannot list prices.
+func (c *CachedClient) ListPrices(ctx context.Context, skuID string, currencyCode string, pageSize int, pageToken string) (*ListPricesResponse, error) {
+	priceClient, ok := c.client.(PriceListClient)
+	if !ok {
+		return nil, fmt.Errorf("listing prices is not supported by %T", c.client)
+	}
+	key := c.key("prices", skuID, currencyCode, strconv.Itoa(pageSize), pageToken)
+	return cachedCall(c, key, c.opts.PriceTTL, func() (*ListPricesResponse, error) {
+		return priceClient.ListPrices(ctx, skuID, currencyCode, pageSize, pageToken)
+	})
+}
+
+// accountClient returns the wrapped client as an AccountPricingClient
+func (c *CachedClient) accountClient() (AccountPricingClient, error) {
+	accountClient, ok := c.client.(AccountPricingClient)
+	if !ok {
+		return nil, fmt.Errorf("billing account pricing is not supported by %T", c.client)
+	}
+	return accountClient, nil
+}
+
+// CalculateCost delegates to the wrapped client
+func (c *CachedClient) CalculateCost(rate *Rate, usageAmount money.Decimal) (money.Decimal, error) {
+	return c.client.CalculateCost(rate, usageAmount)
+}
+
+// cachedCall returns the cached response for key, or calls fetch and caches its result.
+// Responses are stored as JSON so that callers never share mutable state with the cache.
+func cachedCall[T any](c *CachedClient, key string, ttl time.Duration, fetch func() (*T, error)) (*T, error) {
+	if ttl <= 0 {
+		return fetch()
+	}
+
+	if entry := c.lookup(key); entry != nil {
+		var result T
+		if err := json.Unmarshal(entry.Data, &result); err == nil {
+			return &result, nil
+		}
+		// Corrupt entry - fall through and refetch
+	}
+
+	c.misses.Add(1)
+	result, err := fetch()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		return result, nil
+	}
+	c.store(&cacheEntry{
+		Key:       key,
+		Data:      data,
+		CachedAt:  time.Now(),
+		ExpiresAt: time.Now().Add(ttl),
+	})
+
+	return result, nil
+}
+
+// lookup returns a valid cache entry from memory or disk, or nil
+func (c *CachedClient) lookup(key string) *cacheEntry {
+	c.mutex.RLock()
+	entry, exists := c.entries[key]
+	c.mutex.RUnlock()
+
+	if exists && !entry.IsExpired() {
+		c.hits.Add(1)
+		return entry
+	}
+
+	entry = c.readFromDisk(key)
+	if entry == nil || entry.IsExpired() {
+		return nil
+	}
+
+	c.mutex.Lock()
+	c.entries[key] = entry
+	c.mutex.Unlock()
+
+	c.hits.Add(1)
+	c.diskHits.Add(1)
+	return entry
+}
+
+// store saves an entry in memory and, when configured, on disk
+func (c *CachedClient) store(entry *cacheEntry) {
+	c.mutex.Lock()
+	c.entries[entry.Key] = entry
+	c.mutex.Unlock()
+
+	if err := c.writeToDisk(entry); err != nil {
+		log.Printf("PricingCache: failed to persist %s: %v", entry.Key, err)
+	}
+}
+
+// cachePath returns the on-disk file path for a cache key
+func (c *CachedClient) cachePath(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.opts.Dir, hex.EncodeToString(sum[:])+".json")
+}
+
+func (c *CachedClient) readFromDisk(key string) *cacheEntry {
+	if c.opts.Dir == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(c.cachePath(key))
+	if err != nil {
+		return nil
+	}
+
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil || entry.Key != key {
+		return nil
+	}
+	return &entry
+}
+
+func (c *CachedClient) writeToDisk(entry *cacheEntry) error {
+	if c.opts.Dir == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	// Write to a temporary file first so readers never observe partial entries
+	tmp, err := os.CreateTemp(c.opts.Dir, "entry-*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), c.cachePath(entry.Key))
+}
+
+// ClearCache removes all entries from memory and disk
+func (c *CachedClient) ClearCache() error {
+	c.mutex.Lock()
+	c.entries = make(map[string]*cacheEntry)
+	c.mutex.Unlock()
+
+	if c.opts.Dir == "" {
+		return nil
+	}
+
+	files, err := filepath.Glob(filepath.Join(c.opts.Dir, "*.json"))
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		if err := os.Remove(f); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// CacheStats reports the contents and effectiveness of a CachedClient
+type CacheStats struct {
+	TotalEntries   int `json:"total_entries"`
+	ValidEntries   int `json:"valid_entries"`
+	ExpiredEntries int `json:"expired_entries"`
+	// Hits counts responses served from the cache, of which DiskHits were read from disk
+	Hits             int64   `json:"hits"`
+	DiskHits         int64   `json:"disk_hits"`
+	Misses           int64   `json:"misses"`
+	Dir              string  `json:"cache_dir,omitempty"`
+	ServicesTTLHours float64 `json:"services_ttl_hours"`
+	SKUsTTLHours     float64 `json:"skus_ttl_hours"`
+	PriceTTLHours    float64 `json:"price_ttl_hours"`
+}
+
+// GetCacheStats returns cache statistics
+func (c *CachedClient) GetCacheStats() CacheStats {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	stats := CacheStats{
+		TotalEntries:     len(c.entries),
+		Hits:             c.hits.Load(),
+		DiskHits:         c.diskHits.Load(),
+		Misses:           c.misses.Load(),
+		Dir:              c.opts.Dir,
+		ServicesTTLHours: c.opts.ServicesTTL.Hours(),
+		SKUsTTLHours:     c.opts.SKUsTTL.Hours(),
+		PriceTTLHours:    c.opts.PriceTTL.Hours(),
+	}
+	for _, entry := range c.entries {
+		if entry.IsExpired() {
+			stats.ExpiredEntries++
+		} else {
+			stats.ValidEntries++
+		}
+	}
+	return stats
+}
@@ -0,0 +1,213 @@
+package pricing
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// countingClient is a PricingClient stub that counts upstream calls
+type countingClient struct {
+	Client
+	serviceCalls int
+	skuCalls     int
+	priceCalls   int
+}
+
+func (c *countingClient) ListServices(ctx context.Context, pageSize int, pageToken string) (*ListServicesResponse, error) {
+	c.serviceCalls++
+	return &ListServicesResponse{
+		Services: []Service{{ServiceID: "152E-C115-5142", DisplayName: "Cloud Run"}},
+	}, nil
+}
+
+func (c *countingClient) ListSKUs(ctx context.Context, serviceID string, pageSize int, pageToken string) (*ListSKUsResponse, error) {
+	c.skuCalls++
+	return &ListSKUsResponse{
+		SKUs: []SKU{{SKUID: "AAAA-BBBB-CCCC", DisplayName: "CPU Allocation Time", Service: "services/" + serviceID}},
+	}, nil
+}
+
+func (c *countingClient) GetSKUPrice(ctx context.Context, skuID string, currencyCode string) (*GetPriceResponse, error) {
+	c.priceCalls++
+	return &GetPriceResponse{Name: "skus/" + skuID + "/price", CurrencyCode: currencyCode}, nil
+}
+
+func TestCachedClient_MemoryHits(t *testing.T) {
+	upstream := &countingClient{}
+	cached, err := NewCachedClient(upstream, CacheOptions{
+		ServicesTTL: time.Hour,
+		SKUsTTL:     time.Hour,
+		PriceTTL:    time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("NewCachedClient failed: %v", err)
+	}
+
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		if _, err := cached.ListServices(ctx, 5000, ""); err != nil {
+			t.Fatalf("ListServices failed: %v", err)
+		}
+		if _, err := cached.ListSKUs(ctx, "152E-C115-5142", 5000, ""); err != nil {
+			t.Fatalf("ListSKUs failed: %v", err)
+		}
+		if _, err := cached.GetSKUPrice(ctx, "AAAA-BBBB-CCCC", "USD"); err != nil {
+			t.Fatalf("GetSKUPrice failed: %v", err)
+		}
+	}
+
+	if upstream.serviceCalls != 1 || upstream.skuCalls != 1 || upstream.priceCalls != 1 {
+		t.Errorf("upstream calls = (%d, %d, %d), want (1, 1, 1)",
+			upstream.serviceCalls, upstream.skuCalls, upstream.priceCalls)
+	}
+
+	// Different currency is a different cache key
+	if _, err := cached.GetSKUPrice(ctx, "AAAA-BBBB-CCCC", "JPY"); err != nil {
+		t.Fatalf("GetSKUPrice failed: %v", err)
+	}
+	if upstream.priceCalls != 2 {
+		t.Errorf("priceCalls = %d, want 2", upstream.priceCalls)
+	}
+
+	stats := cached.GetCacheStats()
+	if stats.Hits != 6 {
+		t.Errorf("hits = %d, want 6", stats.Hits)
+	}
+	if stats.Misses != 4 {
+		t.Errorf("misses = %d, want 4", stats.Misses)
+	}
+	if stats.TotalEntries != 4 || stats.ValidEntries != 4 {
+		t.Errorf("entries = %d (%d valid), want 4", stats.TotalEntries, stats.ValidEntries)
+	}
+}
+
+func TestCachedClient_PersistsToDisk(t *testing.T) {
+	dir := t.TempDir()
+	opts := CacheOptions{Dir: dir, ServicesTTL: time.Hour, SKUsTTL: time.Hour, PriceTTL: time.Hour}
+	ctx := context.Background()
+
+	first := &countingClient{}
+	cached, err := NewCachedClient(first, opts)
+	if err != nil {
+		t.Fatalf("NewCachedClient failed: %v", err)
+	}
+	if _, err := cached.GetSKUPrice(ctx, "AAAA-BBBB-CCCC", "USD"); err != nil {
+		t.Fatalf("GetSKUPrice failed: %v", err)
+	}
+
+	// A fresh client simulates a server restart
+	second := &countingClient{}
+	restarted, err := NewCachedClient(second, opts)
+	if err != nil {
+		t.Fatalf("NewCachedClient failed: %v", err)
+	}
+	resp, err := restarted.GetSKUPrice(ctx, "AAAA-BBBB-CCCC", "USD")
+	if err != nil {
+		t.Fatalf("GetSKUPrice failed: %v", err)
+	}
+	if resp.CurrencyCode != "USD" {
+		t.Errorf("CurrencyCode = %q, want USD", resp.CurrencyCode)
+	}
+	if second.priceCalls != 0 {
+		t.Errorf("expected disk hit, got %d upstream calls", second.priceCalls)
+	}
+	if got := restarted.GetCacheStats().DiskHits; got != 1 {
+		t.Errorf("disk_hits = %d, want 1", got)
+	}
+
+	// ClearCache removes persisted entries too
+	if err := restarted.ClearCache(); err != nil {
+		t.Fatalf("ClearCache failed: %v", err)
+	}
+	if _, err := restarted.GetSKUPrice(ctx, "AAAA-BBBB-CCCC", "USD"); err != nil {
+		t.Fatalf("GetSKUPrice failed: %v", err)
+	}
+	if second.priceCalls != 1 {
+		t.Errorf("priceCalls after ClearCache = %d, want 1", second.priceCalls)
+	}
+}
+
+func TestCachedClient_ExpiredAndDisabled(t *testing.T) {
+	upstream := &countingClient{}
+	cached, err := NewCachedClient(upstream, CacheOptions{
+		ServicesTTL: time.Nanosecond,
+		SKUsTTL:     0, // disabled
+		PriceTTL:    time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("NewCachedClient failed: %v", err)
+	}
+
+	ctx := context.Background()
+	cached.ListServices(ctx, 5000, "")
+	time.Sleep(time.Millisecond)
+	cached.ListServices(ctx, 5000, "")
+	if upstream.serviceCalls != 2 {
+		t.Errorf("serviceCalls = %d, want 2 (entry should expire)", upstream.serviceCalls)
+	}
+
+	cached.ListSKUs(ctx, "svc", 5000, "")
+	cached.ListSKUs(ctx, "svc", 5000, "")
+	if upstream.skuCalls != 2 {
+		t.Errorf("skuCalls = %d, want 2 (caching disabled)", upstream.skuCalls)
+	}
+}
+
+func TestCachedClient_ReturnsIndependentCopies(t *testing.T) {
+	opts := DefaultCacheOptions()
+	opts.Dir = "" // keep the test hermetic
+	cached, err := NewCachedClient(&countingClient{}, opts)
+	if err != nil {
+		t.Fatalf("NewCachedClient failed: %v", err)
+	}
+
+	ctx := context.Background()
+	first, _ := cached.ListServices(ctx, 5000, "")
+	first.Services[0].DisplayName = "mutated"
+
+	second, _ := cached.ListServices(ctx, 5000, "")
+	if second.Services[0].DisplayName != "Cloud Run" {
+		t.Errorf("cached response was mutated by caller: %q", second.Services[0].DisplayName)
+	}
+}
+
+func TestCachedClient_SeparatesConfigurations(t *testing.T) {
+	dir := t.TempDir()
+	opts := CacheOptions{Dir: dir, ServicesTTL: time.Hour, SKUsTTL: time.Hour, PriceTTL: time.Hour}
+	ctx := context.Background()
+
+	// Two endpoints serving different catalogs share one cache directory
+	newClient := func(displayName, quotaProject string) *CachedClient {
+		client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"services":[{"serviceId":"152E-C115-5142","displayName":"` + displayName + `"}]}`))
+		})
+		client.quotaProject = quotaProject
+		cached, err := NewCachedClient(client, opts)
+		if err != nil {
+			t.Fatalf("NewCachedClient failed: %v", err)
+		}
+		return cached
+	}
+
+	for _, tt := range []struct {
+		client *CachedClient
+		want   string
+	}{
+		{newClient("Cloud Run", ""), "Cloud Run"},
+		{newClient("Cloud Run (mirror)", ""), "Cloud Run (mirror)"},
+		{newClient("Cloud Run (quota)", "my-project"), "Cloud Run (quota)"},
+	} {
+		resp, err := tt.client.ListServices(ctx, 5000, "")
+		if err != nil {
+			t.Fatalf("ListServices failed: %v", err)
+		}
+		if got := resp.Services[0].DisplayName; got != tt.want {
+			t.Errorf("DisplayName = %q, want %q", got, tt.want)
+		}
+		if stats := tt.client.GetCacheStats(); stats.DiskHits != 0 || stats.Misses != 1 {
+			t.Errorf("stats = %+v, want a miss", stats)
+		}
+	}
+}
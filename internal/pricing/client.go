@@ -3,14 +3,12 @@ package pricing
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
 	"net/url"
 	"strconv"
 
-	"golang.org/x/oauth2/google"
+	"github.com/nozomi-koborinai/gcp-cost-mcp-server/internal/money"
 )
 
 const (
@@ -26,7 +24,7 @@ type PricingClient interface {
 	ListServices(ctx context.Context, pageSize int, pageToken string) (*ListServicesResponse, error)
 	ListSKUs(ctx context.Context, serviceID string, pageSize int, pageToken string) (*ListSKUsResponse, error)
 	GetSKUPrice(ctx context.Context, skuID string, currencyCode string) (*GetPriceResponse, error)
-	CalculateCost(rate *Rate, usageAmount float64) (float64, error)
+	CalculateCost(rate *Rate, usageAmount money.Decimal) (money.Decimal, error)
 }
 
 // Ensure Client implements PricingClient interface
@@ -34,21 +32,48 @@ var _ PricingClient = (*Client)(nil)
 
 // Client is a client for the Google Cloud Billing Pricing API
 type Client struct {
-	httpClient *http.Client
-	baseURL    string
+	httpClient   *http.Client
+	baseURL      string
+	quotaProject string
+	retryPolicy  RetryPolicy
+	limiter      *rateLimiter
+	// credentialMode and credentialSubject describe the credentials in CredentialErrors
+	credentialMode    CredentialMode
+	credentialSubject string
 }
 
-// NewClient creates a new Pricing API client using Application Default Credentials
-func NewClient(ctx context.Context) (*Client, error) {
-	// Use ADC to create an authenticated HTTP client
-	client, err := google.DefaultClient(ctx, "https://www.googleapis.com/auth/cloud-billing.readonly")
+// NewClient creates a new Pricing API client.
+// By default it uses Application Default Credentials against the public endpoint;
+// Options select a different endpoint or authentication mode.
+func NewClient(ctx context.Context, opts ...Option) (*Client, error) {
+	cfg := &clientConfig{
+		baseURL:           BaseURL,
+		retryPolicy:       DefaultRetryPolicy(),
+		requestsPerSecond: DefaultRequestsPerSecond,
+		burst:             DefaultBurst,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+
+	client, err := cfg.newHTTPClient(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create authenticated client: %w", err)
 	}
 
+	mode, subject := cfg.credentials()
 	return &Client{
-		httpClient: client,
-		baseURL:    BaseURL,
+		httpClient:        client,
+		baseURL:           cfg.baseURL,
+		quotaProject:      cfg.quotaProject,
+		retryPolicy:       cfg.retryPolicy,
+		limiter:           newRateLimiter(cfg.requestsPerSecond, cfg.burst),
+		credentialMode:    mode,
+		credentialSubject: subject,
 	}, nil
 }
 
@@ -151,6 +176,52 @@ type UnitInfo struct {
 	UnitQuantity    Amount `json:"unitQuantity,omitempty"`
 }
 
+// Decimal returns the amount as an exact decimal. Invalid or empty values are 0.
+func (a Amount) Decimal() money.Decimal {
+	d, err := money.Parse(a.Value)
+	if err != nil {
+		return money.Zero
+	}
+	return d
+}
+
+// Decimal returns the monetary amount (units + nanos) as an exact decimal
+func (m Money) Decimal() money.Decimal {
+	units, _ := strconv.ParseInt(m.Units, 10, 64)
+	return money.New(units, m.Nanos)
+}
+
+// Quantity returns the number of units a list price applies to (e.g. 1000000 for
+// a price per million requests). It is 1 when the quantity is unset or invalid.
+func (u UnitInfo) Quantity() money.Decimal {
+	q := u.UnitQuantity.Decimal()
+	if q.Sign() <= 0 {
+		return money.FromInt(1)
+	}
+	return q
+}
+
+// PricePerUnit returns the exact price of a single unit in the given tier, i.e. the
+// tier's list price divided by the rate's unit quantity
+func (r *Rate) PricePerUnit(tier Tier) money.Decimal {
+	return tier.ListPrice.Decimal().Quo(r.UnitInfo.Quantity())
+}
+
+// FreeTierAmount returns the usage covered by the rate's leading zero-priced tiers, i.e. a free
+// allowance encoded in the catalog, per aggregation interval. It reports false when the first tier
+// is paid or when no tier is paid (the SKU is free rather than having an allowance).
+func (r *Rate) FreeTierAmount() (money.Decimal, bool) {
+	for i, tier := range r.Tiers {
+		if !tier.ListPrice.Decimal().IsZero() {
+			if i == 0 {
+				return money.Zero, false
+			}
+			return tier.StartAmount.Decimal().Sub(r.Tiers[0].StartAmount.Decimal()), true
+		}
+	}
+	return money.Zero, false
+}
+
 // AggregationInfo contains aggregation information
 type AggregationInfo struct {
 	Level    string `json:"level,omitempty"`
@@ -192,25 +263,9 @@ func (c *Client) ListServices(ctx context.Context, pageSize int, pageToken strin
 
 	reqURL := fmt.Sprintf("%s/v2beta/services?%s", c.baseURL, params.Encode())
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to execute request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
-	}
-
 	var result ListServicesResponse
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	if err := c.doGet(ctx, reqURL, &result); err != nil {
+		return nil, err
 	}
 
 	return &result, nil
@@ -235,25 +290,9 @@ func (c *Client) ListSKUs(ctx context.Context, serviceID string, pageSize int, p
 
 	reqURL := fmt.Sprintf("%s/v2beta/skus?%s", c.baseURL, params.Encode())
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to execute request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
-	}
-
 	var result ListSKUsResponse
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	if err := c.doGet(ctx, reqURL, &result); err != nil {
+		return nil, err
 	}
 
 	return &result, nil
@@ -275,75 +314,81 @@ func (c *Client) GetSKUPrice(ctx context.Context, skuID string, currencyCode str
 		reqURL = fmt.Sprintf("%s?%s", reqURL, params.Encode())
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+	var result GetPriceResponse
+	if err := c.doGet(ctx, reqURL, &result); err != nil {
+		return nil, err
 	}
 
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to execute request: %w", err)
-	}
-	defer resp.Body.Close()
+	return &result, nil
+}
+
+// CalculateCost calculates the estimated cost based on usage amount and pricing tiers
+func (c *Client) CalculateCost(rate *Rate, usageAmount money.Decimal) (money.Decimal, error) {
+	return CalculateCost(rate, usageAmount)
+}
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+// CalculateCost calculates the exact cost of usageAmount based on the rate's pricing tiers.
+// usageAmount and tier start amounts are in the rate's unit; list prices are normalized by the unit quantity.
+func CalculateCost(rate *Rate, usageAmount money.Decimal) (money.Decimal, error) {
+	tiers, err := CalculateTierCosts(rate, usageAmount)
+	if err != nil {
+		return money.Zero, err
 	}
 
-	var result GetPriceResponse
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	totalCost := money.Zero
+	for _, tier := range tiers {
+		totalCost = totalCost.Add(tier.Cost)
 	}
+	return totalCost, nil
+}
 
-	return &result, nil
+// TierCost is the usage and cost attributed to one pricing tier
+type TierCost struct {
+	StartAmount money.Decimal
+	// EndAmount is the start of the next tier; it is unset for the open-ended last tier
+	EndAmount    money.Decimal
+	HasEnd       bool
+	Usage        money.Decimal
+	PricePerUnit money.Decimal
+	Cost         money.Decimal
 }
 
-// CalculateCost calculates the estimated cost based on usage amount and pricing tiers
-func (c *Client) CalculateCost(rate *Rate, usageAmount float64) (float64, error) {
+// CalculateTierCosts splits usageAmount across the rate's pricing tiers and prices each tier.
+// Every tier is returned, including tiers the usage does not reach.
+func CalculateTierCosts(rate *Rate, usageAmount money.Decimal) ([]TierCost, error) {
 	if rate == nil {
-		return 0, fmt.Errorf("invalid price data: rate is nil")
+		return nil, fmt.Errorf("invalid price data: rate is nil")
 	}
 
 	if len(rate.Tiers) == 0 {
-		return 0, fmt.Errorf("no pricing tiers available")
+		return nil, fmt.Errorf("no pricing tiers available")
 	}
 
-	var totalCost float64
-	remainingUsage := usageAmount
+	tiers := make([]TierCost, 0, len(rate.Tiers))
+	remainingUsage := money.Max(money.Zero, usageAmount)
 
 	for i, tier := range rate.Tiers {
-		startAmount, _ := strconv.ParseFloat(tier.StartAmount.Value, 64)
-
-		var endAmount float64
-		if i+1 < len(rate.Tiers) {
-			endAmount, _ = strconv.ParseFloat(rate.Tiers[i+1].StartAmount.Value, 64)
-		} else {
-			endAmount = remainingUsage + startAmount + 1 // Use all remaining usage
-		}
-
-		tierRange := endAmount - startAmount
-		if tierRange <= 0 {
-			continue
+		tierCost := TierCost{
+			StartAmount:  tier.StartAmount.Decimal(),
+			Usage:        money.Zero,
+			PricePerUnit: rate.PricePerUnit(tier),
+			Cost:         money.Zero,
 		}
 
+		// The last tier is open-ended and takes all remaining usage
 		usageInTier := remainingUsage
-		if usageInTier > tierRange {
-			usageInTier = tierRange
+		if i+1 < len(rate.Tiers) {
+			tierCost.EndAmount = rate.Tiers[i+1].StartAmount.Decimal()
+			tierCost.HasEnd = true
+			usageInTier = money.Min(remainingUsage, money.Max(money.Zero, tierCost.EndAmount.Sub(tierCost.StartAmount)))
 		}
 
-		// Calculate price per unit (units + nanos)
-		units, _ := strconv.ParseFloat(tier.ListPrice.Units, 64)
-		nanos := float64(tier.ListPrice.Nanos) / 1e9
-		pricePerUnit := units + nanos
-
-		totalCost += usageInTier * pricePerUnit
-		remainingUsage -= usageInTier
-
-		if remainingUsage <= 0 {
-			break
-		}
+		// List prices apply to UnitQuantity units; usage is in single units
+		tierCost.Usage = usageInTier
+		tierCost.Cost = usageInTier.Mul(tierCost.PricePerUnit)
+		remainingUsage = remainingUsage.Sub(usageInTier)
+		tiers = append(tiers, tierCost)
 	}
 
-	return totalCost, nil
+	return tiers, nil
 }
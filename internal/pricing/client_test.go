@@ -1,7 +1,15 @@
 package pricing
 
 import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
 	"testing"
+	"time"
+
+	"github.com/nozomi-koborinai/gcp-cost-mcp-server/internal/money"
 )
 
 func TestClient_CalculateCost(t *testing.T) {
@@ -122,23 +130,154 @@ func TestClient_CalculateCost(t *testing.T) {
 			wantCost:    5.76, // 240000 * $0 + 240000 * $0.000024
 			wantErr:     false,
 		},
+		{
+			name: "Unit quantity - price per 1M requests",
+			rate: &Rate{
+				UnitInfo: UnitInfo{Unit: "count", UnitQuantity: Amount{Value: "1000000"}},
+				Tiers: []Tier{
+					{
+						StartAmount: Amount{Value: "0"},
+						ListPrice:   Money{Units: "0", Nanos: 400000000}, // $0.40 per 1M requests
+					},
+				},
+			},
+			usageAmount: 5000000,
+			wantCost:    2.0, // 5M requests * $0.40/1M
+			wantErr:     false,
+		},
+		{
+			name: "Unit quantity - tiered price per 1000 characters",
+			rate: &Rate{
+				UnitInfo: UnitInfo{Unit: "char", UnitQuantity: Amount{Value: "1000"}},
+				Tiers: []Tier{
+					{
+						StartAmount: Amount{Value: "0"},
+						ListPrice:   Money{Units: "0", Nanos: 0}, // First 5000 characters free
+					},
+					{
+						StartAmount: Amount{Value: "5000"},
+						ListPrice:   Money{Units: "1", Nanos: 0}, // $1 per 1000 characters
+					},
+				},
+			},
+			usageAmount: 20000,
+			wantCost:    15.0, // 15000 billable characters * $1/1000
+			wantErr:     false,
+		},
+		{
+			name: "Unit quantity - invalid quantity treated as 1",
+			rate: &Rate{
+				UnitInfo: UnitInfo{Unit: "h", UnitQuantity: Amount{Value: "0"}},
+				Tiers: []Tier{
+					{
+						StartAmount: Amount{Value: "0"},
+						ListPrice:   Money{Units: "2"},
+					},
+				},
+			},
+			usageAmount: 3,
+			wantCost:    6.0,
+			wantErr:     false,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			gotCost, err := client.CalculateCost(tt.rate, tt.usageAmount)
+			gotCost, err := client.CalculateCost(tt.rate, money.FromFloat(tt.usageAmount))
 
 			if (err != nil) != tt.wantErr {
 				t.Errorf("CalculateCost() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
 
-			if !tt.wantErr {
-				// Use a tolerance for floating point comparison
-				tolerance := 0.0001
-				if diff := gotCost - tt.wantCost; diff < -tolerance || diff > tolerance {
-					t.Errorf("CalculateCost() = %v, want %v (diff: %v)", gotCost, tt.wantCost, diff)
-				}
+			// Decimal arithmetic is exact, so no floating point tolerance is needed
+			if !tt.wantErr && gotCost.Cmp(money.FromFloat(tt.wantCost)) != 0 {
+				t.Errorf("CalculateCost() = %s, want %v", gotCost, tt.wantCost)
+			}
+		})
+	}
+}
+
+func TestCalculateCost_ExactTotals(t *testing.T) {
+	// Tier boundaries and prices that are not representable in binary floating point
+	rate := &Rate{
+		UnitInfo: UnitInfo{Unit: "GiBy.mo", UnitQuantity: Amount{Value: "1"}},
+		Tiers: []Tier{
+			{StartAmount: Amount{Value: "0"}, ListPrice: Money{Nanos: 23000000}},     // $0.023
+			{StartAmount: Amount{Value: "51200"}, ListPrice: Money{Nanos: 22000000}}, // $0.022
+		},
+	}
+
+	got, err := CalculateCost(rate, money.MustParse("102400.1"))
+	if err != nil {
+		t.Fatalf("CalculateCost failed: %v", err)
+	}
+	// 51200 * 0.023 + 51200.1 * 0.022
+	if want := money.MustParse("2304.0022"); got.Cmp(want) != 0 {
+		t.Errorf("CalculateCost() = %s, want %s", got, want)
+	}
+}
+
+func TestCalculateTierCosts(t *testing.T) {
+	rate := &Rate{
+		UnitInfo: UnitInfo{Unit: "GiBy", UnitQuantity: Amount{Value: "1"}},
+		Tiers: []Tier{
+			{StartAmount: Amount{Value: "0"}},
+			{StartAmount: Amount{Value: "1024"}, ListPrice: Money{Units: "1", Nanos: 200000000}}, // $1.20
+			{StartAmount: Amount{Value: "10240"}, ListPrice: Money{Nanos: 850000000}},            // $0.85
+			{StartAmount: Amount{Value: "153600"}, ListPrice: Money{Nanos: 800000000}},           // $0.80
+		},
+	}
+
+	tiers, err := CalculateTierCosts(rate, money.FromInt(20000))
+	if err != nil {
+		t.Fatalf("CalculateTierCosts failed: %v", err)
+	}
+	if len(tiers) != 4 {
+		t.Fatalf("got %d tiers, want 4", len(tiers))
+	}
+
+	want := []struct {
+		start, end, usage, price, cost string
+		hasEnd                         bool
+	}{
+		{"0", "1024", "1024", "0", "0", true},
+		{"1024", "10240", "9216", "1.2", "11059.2", true},
+		{"10240", "153600", "9760", "0.85", "8296", true},
+		{"153600", "", "0", "0.8", "0", false},
+	}
+	for i, w := range want {
+		got := tiers[i]
+		if got.StartAmount.String() != w.start || got.HasEnd != w.hasEnd || (w.hasEnd && got.EndAmount.String() != w.end) {
+			t.Errorf("tier %d bounds = [%s, %s) hasEnd=%v, want [%s, %s)", i, got.StartAmount, got.EndAmount, got.HasEnd, w.start, w.end)
+		}
+		if got.Usage.String() != w.usage || got.PricePerUnit.String() != w.price || got.Cost.String() != w.cost {
+			t.Errorf("tier %d = usage %s x %s = %s, want %s x %s = %s", i, got.Usage, got.PricePerUnit, got.Cost, w.usage, w.price, w.cost)
+		}
+	}
+}
+
+func TestRate_FreeTierAmount(t *testing.T) {
+	tier := func(start string, nanos int64) Tier {
+		return Tier{StartAmount: Amount{Value: start}, ListPrice: Money{Nanos: nanos}}
+	}
+	tests := []struct {
+		name   string
+		tiers  []Tier
+		want   string
+		wantOK bool
+	}{
+		{"zero-priced first tier", []Tier{tier("0", 0), tier("2000000", 400)}, "2000000", true},
+		{"several zero-priced tiers", []Tier{tier("0", 0), tier("5", 0), tier("10", 400)}, "10", true},
+		{"paid first tier", []Tier{tier("0", 400), tier("100", 300)}, "0", false},
+		{"free SKU", []Tier{tier("0", 0)}, "0", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := (&Rate{Tiers: tt.tiers}).FreeTierAmount()
+			if ok != tt.wantOK || got.String() != tt.want {
+				t.Errorf("FreeTierAmount() = %s, %v, want %s, %v", got, ok, tt.want, tt.wantOK)
 			}
 		})
 	}
@@ -161,3 +300,212 @@ func TestBaseURL(t *testing.T) {
 func TestPricingClientInterface(t *testing.T) {
 	var _ PricingClient = (*Client)(nil)
 }
+
+// newTestClient creates a Client pointed at an httptest stand-in of the Billing API
+func newTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	return &Client{
+		httpClient: srv.Client(),
+		baseURL:    srv.URL,
+		retryPolicy: RetryPolicy{
+			MaxRetries:     3,
+			InitialBackoff: time.Millisecond,
+			MaxBackoff:     5 * time.Millisecond,
+			Multiplier:     2,
+		},
+	}
+}
+
+func TestClient_RetriesTransientStatus(t *testing.T) {
+	var calls atomic.Int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"services":[{"serviceId":"152E-C115-5142","displayName":"Cloud Run"}]}`))
+	})
+
+	resp, err := client.ListServices(context.Background(), 10, "")
+	if err != nil {
+		t.Fatalf("ListServices failed: %v", err)
+	}
+	if len(resp.Services) != 1 || resp.Services[0].DisplayName != "Cloud Run" {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+	if calls.Load() != 3 {
+		t.Errorf("calls = %d, want 3", calls.Load())
+	}
+}
+
+func TestClient_DoesNotRetryClientErrors(t *testing.T) {
+	var calls atomic.Int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	if _, err := client.GetSKUPrice(context.Background(), "MISSING-SKU", "USD"); err == nil {
+		t.Fatal("expected error for 404")
+	}
+	if calls.Load() != 1 {
+		t.Errorf("calls = %d, want 1", calls.Load())
+	}
+}
+
+func TestClient_GivesUpAfterMaxRetries(t *testing.T) {
+	var calls atomic.Int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	if _, err := client.ListSKUs(context.Background(), "152E-C115-5142", 10, ""); err == nil {
+		t.Fatal("expected error after exhausting retries")
+	}
+	if calls.Load() != 4 {
+		t.Errorf("calls = %d, want 4 (1 attempt + 3 retries)", calls.Load())
+	}
+}
+
+func TestClient_HonorsRetryAfter(t *testing.T) {
+	var calls atomic.Int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`{"name":"skus/AAAA/price","currencyCode":"USD"}`))
+	})
+	client.retryPolicy.MaxBackoff = 2 * time.Second
+
+	start := time.Now()
+	if _, err := client.GetSKUPrice(context.Background(), "AAAA", "USD"); err != nil {
+		t.Fatalf("GetSKUPrice failed: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 900*time.Millisecond {
+		t.Errorf("retried after %s, want at least the 1s Retry-After", elapsed)
+	}
+}
+
+func TestClient_FailsOnLongRetryAfter(t *testing.T) {
+	var calls atomic.Int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Retry-After", "3600")
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+
+	// The hour the server asks for is longer than MaxBackoff (5ms), so the call fails without retrying
+	start := time.Now()
+	_, err := client.GetSKUPrice(context.Background(), "AAAA", "USD")
+	var rateLimitErr *RateLimitError
+	if !errors.As(err, &rateLimitErr) || !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("err = %v, want a RateLimitError", err)
+	}
+	if rateLimitErr.RetryAfter != time.Hour {
+		t.Errorf("RetryAfter = %s, want 1h", rateLimitErr.RetryAfter)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("calls = %d, want 1", calls.Load())
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("failed after %s, want no wait", elapsed)
+	}
+}
+
+func TestClient_DoesNotRetryNotImplemented(t *testing.T) {
+	var calls atomic.Int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusNotImplemented)
+	})
+
+	if _, err := client.ListServices(context.Background(), 10, ""); err == nil {
+		t.Fatal("expected error for 501")
+	}
+	if calls.Load() != 1 {
+		t.Errorf("calls = %d, want 1", calls.Load())
+	}
+}
+
+func TestClient_StopsRetryingWhenContextCanceled(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	client.retryPolicy.InitialBackoff = time.Hour
+	client.retryPolicy.MaxBackoff = time.Hour
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	if _, err := client.ListServices(ctx, 10, ""); err == nil {
+		t.Fatal("expected error")
+	}
+	if time.Since(start) > time.Second {
+		t.Error("retry loop did not honor context cancellation")
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		value  string
+		want   time.Duration
+		wantOK bool
+	}{
+		{"Seconds", "5", 5 * time.Second, true},
+		{"HTTP date", now.Add(30 * time.Second).Format(http.TimeFormat), 30 * time.Second, true},
+		{"Date in the past", now.Add(-time.Minute).Format(http.TimeFormat), 0, true},
+		{"Empty", "", 0, false},
+		{"Garbage", "soon", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseRetryAfter(tt.value, now)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("parseRetryAfter(%q) = (%v, %v), want (%v, %v)", tt.value, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	policy := RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second, Multiplier: 2}
+
+	for attempt := 1; attempt <= 6; attempt++ {
+		base := min(time.Second, 100*time.Millisecond<<(attempt-1))
+		got := policy.backoff(attempt)
+		if got < base/2 || got > base {
+			t.Errorf("backoff(%d) = %v, want between %v and %v", attempt, got, base/2, base)
+		}
+	}
+}
+
+func TestRateLimiter(t *testing.T) {
+	limiter := newRateLimiter(100, 2)
+	ctx := context.Background()
+
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		if err := limiter.Wait(ctx); err != nil {
+			t.Fatalf("Wait failed: %v", err)
+		}
+	}
+	// 2 burst tokens, then 3 more at 100/s = ~30ms
+	if elapsed := time.Since(start); elapsed < 25*time.Millisecond {
+		t.Errorf("5 requests took %s, expected rate limiting to apply", elapsed)
+	}
+
+	if newRateLimiter(0, 1) != nil {
+		t.Error("expected nil limiter when rps is 0")
+	}
+}
@@ -0,0 +1,322 @@
+package pricing
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nozomi-koborinai/gcp-cost-mcp-server/internal/money"
+)
+
+// SnapshotDiff describes the catalog changes between two snapshots
+type SnapshotDiff struct {
+	CurrencyCode string        `json:"currency_code"`
+	From         time.Time     `json:"from"`
+	To           time.Time     `json:"to"`
+	AddedSKUs    int           `json:"added_skus"`
+	RemovedSKUs  int           `json:"removed_skus"`
+	ChangedSKUs  int           `json:"changed_skus"`
+	Services     []ServiceDiff `json:"services,omitempty"`
+}
+
+// ServiceDiff groups the changes of one service by region
+type ServiceDiff struct {
+	ServiceID   string       `json:"service_id"`
+	DisplayName string       `json:"display_name"`
+	Regions     []RegionDiff `json:"regions"`
+}
+
+// RegionDiff lists the SKU changes of a service in one region
+type RegionDiff struct {
+	Region  string      `json:"region"`
+	Added   []SKURef    `json:"added,omitempty"`
+	Removed []SKURef    `json:"removed,omitempty"`
+	Changed []SKUChange `json:"changed,omitempty"`
+}
+
+// SKURef identifies a SKU in a diff
+type SKURef struct {
+	SKUID       string `json:"sku_id"`
+	DisplayName string `json:"display_name"`
+}
+
+// SKUChange describes the price changes of a SKU present in both snapshots
+type SKUChange struct {
+	SKURef
+	Changes []PriceChange `json:"changes"`
+}
+
+// PriceChange describes a changed price tier of one consumption model. Prices are per single unit.
+// OldPrice is nil for tiers that were added and NewPrice is nil for tiers that were removed.
+type PriceChange struct {
+	ConsumptionModel string   `json:"consumption_model"`
+	Unit             string   `json:"unit"`
+	StartAmount      string   `json:"start_amount"`
+	OldPrice         *float64 `json:"old_price,omitempty"`
+	NewPrice         *float64 `json:"new_price,omitempty"`
+	// ChangePercent is the relative change; zero when either price is missing or the old price is zero
+	ChangePercent float64 `json:"change_percent,omitempty"`
+}
+
+// DiffSnapshots compares the SKUs and prices (in the given currency) of two snapshots.
+// Only services that have SKUs in at least one snapshot are compared.
+func DiffSnapshots(from, to *Snapshot, currencyCode string) *SnapshotDiff {
+	if currencyCode == "" {
+		currencyCode = "USD"
+	}
+
+	diff := &SnapshotDiff{CurrencyCode: currencyCode, From: from.CreatedAt, To: to.CreatedAt}
+
+	names := make(map[string]string)
+	for _, s := range append(append([]Service{}, from.Services...), to.Services...) {
+		names[s.ServiceID] = s.DisplayName
+	}
+
+	serviceIDs := make(map[string]bool)
+	for id := range from.SKUs {
+		serviceIDs[id] = true
+	}
+	for id := range to.SKUs {
+		serviceIDs[id] = true
+	}
+
+	for _, serviceID := range sortedKeys(serviceIDs) {
+		oldSKUs := indexSKUs(from.SKUs[serviceID])
+		newSKUs := indexSKUs(to.SKUs[serviceID])
+		regions := make(map[string]*RegionDiff)
+		region := func(sku SKU) *RegionDiff {
+			name := skuRegion(sku)
+			if regions[name] == nil {
+				regions[name] = &RegionDiff{Region: name}
+			}
+			return regions[name]
+		}
+
+		for id, sku := range newSKUs {
+			if _, ok := oldSKUs[id]; !ok {
+				r := region(sku)
+				r.Added = append(r.Added, SKURef{SKUID: id, DisplayName: sku.DisplayName})
+				diff.AddedSKUs++
+			}
+		}
+		for id, sku := range oldSKUs {
+			newSKU, ok := newSKUs[id]
+			if !ok {
+				r := region(sku)
+				r.Removed = append(r.Removed, SKURef{SKUID: id, DisplayName: sku.DisplayName})
+				diff.RemovedSKUs++
+				continue
+			}
+
+			oldPrice, _ := from.Price(id, currencyCode)
+			newPrice, _ := to.Price(id, currencyCode)
+			if changes := diffPrices(oldPrice, newPrice); len(changes) > 0 {
+				r := region(newSKU)
+				r.Changed = append(r.Changed, SKUChange{
+					SKURef:  SKURef{SKUID: id, DisplayName: newSKU.DisplayName},
+					Changes: changes,
+				})
+				diff.ChangedSKUs++
+			}
+		}
+
+		if len(regions) == 0 {
+			continue
+		}
+		serviceDiff := ServiceDiff{ServiceID: serviceID, DisplayName: names[serviceID]}
+		for _, r := range regions {
+			sort.Slice(r.Added, func(i, j int) bool { return r.Added[i].SKUID < r.Added[j].SKUID })
+			sort.Slice(r.Removed, func(i, j int) bool { return r.Removed[i].SKUID < r.Removed[j].SKUID })
+			sort.Slice(r.Changed, func(i, j int) bool { return r.Changed[i].SKUID < r.Changed[j].SKUID })
+			serviceDiff.Regions = append(serviceDiff.Regions, *r)
+		}
+		sort.Slice(serviceDiff.Regions, func(i, j int) bool {
+			return serviceDiff.Regions[i].Region < serviceDiff.Regions[j].Region
+		})
+		diff.Services = append(diff.Services, serviceDiff)
+	}
+
+	return diff
+}
+
+// DiffLive compares a snapshot with the current catalog served by client.
+// Only the services the snapshot has SKUs for are fetched; the SKUs of services that are no
+// longer in the catalog are reported as removed.
+func DiffLive(ctx context.Context, baseline *Snapshot, client PricingClient, currencyCode string, concurrency int) (*SnapshotDiff, error) {
+	if currencyCode == "" {
+		currencyCode = "USD"
+	}
+
+	if len(baseline.SKUs) == 0 {
+		return &SnapshotDiff{CurrencyCode: currencyCode, From: baseline.CreatedAt, To: time.Now().UTC()}, nil
+	}
+
+	services, err := listAllServices(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+	serviceIDs := make(map[string]bool)
+	for _, service := range services {
+		if _, ok := baseline.SKUs[service.ServiceID]; ok {
+			serviceIDs[service.ServiceID] = true
+		}
+	}
+
+	// An empty service list would build the whole catalog
+	current := NewSnapshot()
+	if len(serviceIDs) > 0 {
+		current, err = BuildSnapshot(ctx, client, BuildOptions{
+			Services:      sortedKeys(serviceIDs),
+			CurrencyCodes: []string{currencyCode},
+			Concurrency:   concurrency,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return DiffSnapshots(baseline, current, currencyCode), nil
+}
+
+// diffPrices compares the tiers of each consumption model of two prices.
+// A price missing on one side is treated as having no tiers.
+func diffPrices(from, to *GetPriceResponse) []PriceChange {
+	oldModels := indexConsumptionModels(from)
+	newModels := indexConsumptionModels(to)
+
+	models := make(map[string]bool)
+	for m := range oldModels {
+		models[m] = true
+	}
+	for m := range newModels {
+		models[m] = true
+	}
+
+	var changes []PriceChange
+	for _, model := range sortedKeys(models) {
+		oldRate, newRate := oldModels[model], newModels[model]
+		unit := rateUnit(newRate)
+		if unit == "" {
+			unit = rateUnit(oldRate)
+		}
+
+		oldTiers := indexTiers(oldRate)
+		newTiers := indexTiers(newRate)
+		starts := make(map[string]bool)
+		for s := range oldTiers {
+			starts[s] = true
+		}
+		for s := range newTiers {
+			starts[s] = true
+		}
+
+		sortedStarts := sortedKeys(starts)
+		sort.SliceStable(sortedStarts, func(i, j int) bool {
+			a, _ := strconv.ParseFloat(sortedStarts[i], 64)
+			b, _ := strconv.ParseFloat(sortedStarts[j], 64)
+			return a < b
+		})
+
+		for _, start := range sortedStarts {
+			oldTier, hadOld := oldTiers[start]
+			newTier, hasNew := newTiers[start]
+			var oldPrice, newPrice money.Decimal
+			if hadOld {
+				oldPrice = oldRate.PricePerUnit(oldTier)
+			}
+			if hasNew {
+				newPrice = newRate.PricePerUnit(newTier)
+			}
+			// Per-unit prices are compared exactly, so a change of unit quantity alone is not a price change
+			if hadOld && hasNew && oldPrice.Cmp(newPrice) == 0 {
+				continue
+			}
+
+			change := PriceChange{ConsumptionModel: model, Unit: unit, StartAmount: start}
+			if hadOld {
+				v := oldPrice.Float64()
+				change.OldPrice = &v
+			}
+			if hasNew {
+				v := newPrice.Float64()
+				change.NewPrice = &v
+			}
+			if hadOld && hasNew && !oldPrice.IsZero() {
+				change.ChangePercent = newPrice.Sub(oldPrice).Quo(oldPrice).Mul(money.FromInt(100)).Float64()
+			}
+			changes = append(changes, change)
+		}
+	}
+	return changes
+}
+
+// indexSKUs maps SKU IDs to SKUs
+func indexSKUs(skus []SKU) map[string]SKU {
+	index := make(map[string]SKU, len(skus))
+	for _, sku := range skus {
+		index[sku.SKUID] = sku
+	}
+	return index
+}
+
+// indexConsumptionModels maps consumption models to rates
+func indexConsumptionModels(price *GetPriceResponse) map[string]*Rate {
+	index := make(map[string]*Rate)
+	if price == nil {
+		return index
+	}
+	for _, p := range price.SKUPrices {
+		model := p.ConsumptionModel
+		if model == "" {
+			model = "DEFAULT"
+		}
+		index[model] = p.Rate
+	}
+	return index
+}
+
+// indexTiers maps tier start amounts to tiers
+func indexTiers(rate *Rate) map[string]Tier {
+	index := make(map[string]Tier)
+	if rate == nil {
+		return index
+	}
+	for _, tier := range rate.Tiers {
+		start := tier.StartAmount.Value
+		if start == "" {
+			start = "0"
+		}
+		index[start] = tier
+	}
+	return index
+}
+
+// rateUnit returns the unit of a rate, or "" for a nil rate
+func rateUnit(rate *Rate) string {
+	if rate == nil {
+		return ""
+	}
+	return rate.UnitInfo.Unit
+}
+
+// skuRegion returns the region a SKU is grouped under in diffs
+func skuRegion(sku SKU) string {
+	if region := sku.GeoTaxonomy.RegionalMetadata.Region.Region; region != "" {
+		return region
+	}
+	if sku.GeoTaxonomy.Type != "" {
+		return strings.ToLower(sku.GeoTaxonomy.Type)
+	}
+	return "global"
+}
+
+// sortedKeys returns the keys of a set in sorted order
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,149 @@
+package pricing
+
+import (
+	"context"
+	"math"
+	"testing"
+)
+
+func TestDiffSnapshots(t *testing.T) {
+	from := testSnapshot()
+	to := testSnapshot()
+
+	tokyo := GeoTaxonomy{Type: "REGIONAL", RegionalMetadata: RegionalMetadata{Region: Region{Region: "asia-northeast1"}}}
+	to.SKUs["152E-C115-5142"] = []SKU{
+		{SKUID: "CPU-SKU", DisplayName: "CPU Allocation Time", GeoTaxonomy: tokyo},
+		{SKUID: "MEM-SKU", DisplayName: "Memory Allocation Time"},
+		{SKUID: "GPU-SKU", DisplayName: "GPU Allocation Time", GeoTaxonomy: tokyo},
+	}
+	// CPU price goes up 10% and gains a volume tier
+	to.SetPrice("CPU-SKU", "USD", &GetPriceResponse{
+		CurrencyCode: "USD",
+		SKUPrices: []SKUPrice{{
+			ConsumptionModel: "DEFAULT",
+			Rate: &Rate{
+				UnitInfo: UnitInfo{Unit: "s"},
+				Tiers: []Tier{
+					{StartAmount: Amount{Value: "0"}, ListPrice: Money{Nanos: 26400}},
+					{StartAmount: Amount{Value: "1000000"}, ListPrice: Money{Nanos: 20000}},
+				},
+			},
+		}},
+	})
+
+	diff := DiffSnapshots(from, to, "USD")
+
+	if diff.AddedSKUs != 1 || diff.RemovedSKUs != 1 || diff.ChangedSKUs != 1 {
+		t.Fatalf("counts = added %d, removed %d, changed %d; want 1, 1, 1", diff.AddedSKUs, diff.RemovedSKUs, diff.ChangedSKUs)
+	}
+	if len(diff.Services) != 1 || diff.Services[0].DisplayName != "Cloud Run" {
+		t.Fatalf("services = %+v", diff.Services)
+	}
+
+	regions := diff.Services[0].Regions
+	if len(regions) != 2 || regions[0].Region != "asia-northeast1" || regions[1].Region != "global" {
+		t.Fatalf("regions = %+v", regions)
+	}
+	if len(regions[0].Added) != 1 || regions[0].Added[0].SKUID != "GPU-SKU" {
+		t.Errorf("added = %+v", regions[0].Added)
+	}
+	if len(regions[1].Removed) != 1 || regions[1].Removed[0].SKUID != "REQ-SKU" {
+		t.Errorf("removed = %+v", regions[1].Removed)
+	}
+
+	changes := regions[0].Changed[0].Changes
+	if len(changes) != 2 {
+		t.Fatalf("got %d tier changes, want 2: %+v", len(changes), changes)
+	}
+	if math.Abs(changes[0].ChangePercent-10) > 1e-9 {
+		t.Errorf("ChangePercent = %v, want 10", changes[0].ChangePercent)
+	}
+	if changes[1].StartAmount != "1000000" || changes[1].OldPrice != nil || changes[1].NewPrice == nil {
+		t.Errorf("added tier = %+v", changes[1])
+	}
+}
+
+func TestDiffSnapshots_UnitQuantity(t *testing.T) {
+	from := testSnapshot()
+	to := testSnapshot()
+	// Same price, now listed per 1000 seconds
+	to.SetPrice("CPU-SKU", "USD", &GetPriceResponse{
+		CurrencyCode: "USD",
+		SKUPrices: []SKUPrice{{
+			ConsumptionModel: "DEFAULT",
+			Rate: &Rate{
+				UnitInfo: UnitInfo{Unit: "s", UnitQuantity: Amount{Value: "1000"}},
+				Tiers:    []Tier{{StartAmount: Amount{Value: "0"}, ListPrice: Money{CurrencyCode: "USD", Nanos: 24000000}}},
+			},
+		}},
+	})
+
+	diff := DiffSnapshots(from, to, "USD")
+	if diff.ChangedSKUs != 0 {
+		t.Errorf("same per-unit price reported as changed: %+v", diff.Services)
+	}
+
+	// A real change in per-unit terms is still reported
+	to.Prices["USD"]["CPU-SKU"].SKUPrices[0].Rate.Tiers[0].ListPrice.Nanos = 36000000
+	diff = DiffSnapshots(from, to, "USD")
+	change := diff.Services[0].Regions[0].Changed[0].Changes[0]
+	if math.Abs(change.ChangePercent-50) > 1e-9 {
+		t.Errorf("ChangePercent = %v, want 50", change.ChangePercent)
+	}
+}
+
+func TestDiffSnapshots_NoChanges(t *testing.T) {
+	diff := DiffSnapshots(testSnapshot(), testSnapshot(), "")
+	if diff.AddedSKUs+diff.RemovedSKUs+diff.ChangedSKUs != 0 || len(diff.Services) != 0 {
+		t.Errorf("expected empty diff, got %+v", diff)
+	}
+}
+
+func TestDiffLive(t *testing.T) {
+	baseline := testSnapshot()
+	live := testSnapshot()
+	live.SetPrice("MEM-SKU", "USD", &GetPriceResponse{
+		CurrencyCode: "USD",
+		SKUPrices:    []SKUPrice{{Rate: &Rate{Tiers: []Tier{{ListPrice: Money{Nanos: 2500}}}}}},
+	})
+
+	diff, err := DiffLive(context.Background(), baseline, NewSnapshotClient(live), "USD", 2)
+	if err != nil {
+		t.Fatalf("DiffLive failed: %v", err)
+	}
+	if diff.ChangedSKUs != 1 || diff.Services[0].Regions[0].Changed[0].SKUID != "MEM-SKU" {
+		t.Errorf("diff = %+v", diff)
+	}
+}
+
+func TestDiffLive_RetiredService(t *testing.T) {
+	baseline := testSnapshot()
+	baseline.SKUs["9662-B51E-5089"] = []SKU{{SKUID: "SQL-SKU", DisplayName: "Cloud SQL vCPU"}}
+
+	// Cloud SQL is no longer in the catalog
+	live := testSnapshot()
+	live.Services = live.Services[1:]
+
+	diff, err := DiffLive(context.Background(), baseline, NewSnapshotClient(live), "USD", 2)
+	if err != nil {
+		t.Fatalf("DiffLive failed: %v", err)
+	}
+	if diff.RemovedSKUs != 1 || diff.AddedSKUs != 0 || diff.ChangedSKUs != 0 {
+		t.Fatalf("diff = %+v", diff)
+	}
+	service := diff.Services[0]
+	if service.DisplayName != "Cloud SQL" || service.Regions[0].Removed[0].SKUID != "SQL-SKU" {
+		t.Errorf("service diff = %+v", service)
+	}
+
+	// Every service of the baseline may be gone
+	live.Services = live.Services[1:]
+	delete(baseline.SKUs, "9662-B51E-5089")
+	diff, err = DiffLive(context.Background(), baseline, NewSnapshotClient(live), "USD", 2)
+	if err != nil {
+		t.Fatalf("DiffLive failed: %v", err)
+	}
+	if diff.RemovedSKUs != 3 {
+		t.Errorf("RemovedSKUs = %d, want 3", diff.RemovedSKUs)
+	}
+}
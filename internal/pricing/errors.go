@@ -0,0 +1,140 @@
+package pricing
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Sentinel errors for Cloud Billing API failures. Use errors.Is to check for them.
+var (
+	// ErrNotFound indicates that the requested service, SKU or price does not exist
+	ErrNotFound = errors.New("not found")
+	// ErrPermissionDenied indicates that the credentials are not allowed to access the resource
+	ErrPermissionDenied = errors.New("permission denied")
+	// ErrUnauthenticated indicates missing, invalid or expired credentials
+	ErrUnauthenticated = errors.New("unauthenticated")
+	// ErrRateLimited indicates that the API quota was exhausted
+	ErrRateLimited = errors.New("rate limited")
+	// ErrInvalidArgument indicates that the request parameters were rejected
+	ErrInvalidArgument = errors.New("invalid argument")
+)
+
+// APIError is an error response returned by the Cloud Billing API
+type APIError struct {
+	// StatusCode is the HTTP status code
+	StatusCode int
+	// Status is the canonical Google error status (e.g. "NOT_FOUND")
+	Status string
+	// Message is the human-readable error message from the API
+	Message string
+}
+
+// Error implements the error interface
+func (e *APIError) Error() string {
+	if e.Status != "" {
+		return fmt.Sprintf("API request failed with status %d (%s): %s", e.StatusCode, e.Status, e.Message)
+	}
+	return fmt.Sprintf("API request failed with status %d: %s", e.StatusCode, e.Message)
+}
+
+// Unwrap returns the sentinel error matching the API error, so that
+// errors.Is(err, ErrNotFound) and friends work on *APIError values
+func (e *APIError) Unwrap() error {
+	switch e.Status {
+	case "NOT_FOUND":
+		return ErrNotFound
+	case "PERMISSION_DENIED":
+		return ErrPermissionDenied
+	case "UNAUTHENTICATED":
+		return ErrUnauthenticated
+	case "RESOURCE_EXHAUSTED":
+		return ErrRateLimited
+	case "INVALID_ARGUMENT", "FAILED_PRECONDITION", "OUT_OF_RANGE":
+		return ErrInvalidArgument
+	}
+
+	switch e.StatusCode {
+	case http.StatusNotFound:
+		return ErrNotFound
+	case http.StatusForbidden:
+		return ErrPermissionDenied
+	case http.StatusUnauthorized:
+		return ErrUnauthenticated
+	case http.StatusTooManyRequests:
+		return ErrRateLimited
+	case http.StatusBadRequest:
+		return ErrInvalidArgument
+	}
+	return nil
+}
+
+// RateLimitError is returned when the API asks to retry a request later than the retry policy
+// allows to wait. It matches ErrRateLimited as well as the underlying error.
+type RateLimitError struct {
+	// RetryAfter is the delay the server asked for
+	RetryAfter time.Duration
+	Err        error
+}
+
+// Error implements the error interface
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("%v (retry after %s)", e.Err, e.RetryAfter)
+}
+
+// Unwrap returns ErrRateLimited and the underlying error
+func (e *RateLimitError) Unwrap() []error {
+	return []error{ErrRateLimited, e.Err}
+}
+
+// CredentialError is returned when a request is rejected as unauthenticated. It names the
+// credentials the client used, so that the failure can be explained, and matches ErrUnauthenticated.
+type CredentialError struct {
+	Mode CredentialMode
+	// Subject is the credentials file or the impersonated service account, if the mode has one
+	Subject string
+	Err     error
+}
+
+// Error implements the error interface
+func (e *CredentialError) Error() string {
+	if e.Subject != "" {
+		return fmt.Sprintf("%v (using %s %s)", e.Err, e.Mode, e.Subject)
+	}
+	return fmt.Sprintf("%v (using %s)", e.Err, e.Mode)
+}
+
+// Unwrap returns the underlying error
+func (e *CredentialError) Unwrap() error {
+	return e.Err
+}
+
+// googleErrorResponse is the standard Google API error body
+type googleErrorResponse struct {
+	Error struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+		Status  string `json:"status"`
+	} `json:"error"`
+}
+
+// parseAPIError builds an APIError from a non-200 response body
+func parseAPIError(statusCode int, body []byte) *APIError {
+	apiErr := &APIError{StatusCode: statusCode}
+
+	var parsed googleErrorResponse
+	if err := json.Unmarshal(body, &parsed); err == nil && (parsed.Error.Message != "" || parsed.Error.Status != "") {
+		apiErr.Status = parsed.Error.Status
+		apiErr.Message = parsed.Error.Message
+		return apiErr
+	}
+
+	apiErr.Message = strings.TrimSpace(string(body))
+	if apiErr.Message == "" {
+		apiErr.Message = http.StatusText(statusCode)
+	}
+	return apiErr
+}
@@ -0,0 +1,117 @@
+package pricing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestParseAPIError(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		body       string
+		wantErr    error
+		wantStatus string
+		wantMsg    string
+	}{
+		{
+			name:       "Google error JSON - not found",
+			statusCode: http.StatusNotFound,
+			body:       `{"error":{"code":404,"message":"SKU not found","status":"NOT_FOUND"}}`,
+			wantErr:    ErrNotFound,
+			wantStatus: "NOT_FOUND",
+			wantMsg:    "SKU not found",
+		},
+		{
+			name:       "Google error JSON - permission denied",
+			statusCode: http.StatusForbidden,
+			body:       `{"error":{"code":403,"message":"The caller does not have permission","status":"PERMISSION_DENIED"}}`,
+			wantErr:    ErrPermissionDenied,
+			wantStatus: "PERMISSION_DENIED",
+		},
+		{
+			name:       "Google error JSON - unauthenticated",
+			statusCode: http.StatusUnauthorized,
+			body:       `{"error":{"code":401,"message":"Request had invalid authentication credentials.","status":"UNAUTHENTICATED"}}`,
+			wantErr:    ErrUnauthenticated,
+			wantStatus: "UNAUTHENTICATED",
+		},
+		{
+			name:       "Google error JSON - quota",
+			statusCode: http.StatusTooManyRequests,
+			body:       `{"error":{"code":429,"message":"Quota exceeded","status":"RESOURCE_EXHAUSTED"}}`,
+			wantErr:    ErrRateLimited,
+			wantStatus: "RESOURCE_EXHAUSTED",
+		},
+		{
+			name:       "Google error JSON - invalid argument",
+			statusCode: http.StatusBadRequest,
+			body:       `{"error":{"code":400,"message":"Invalid currency code","status":"INVALID_ARGUMENT"}}`,
+			wantErr:    ErrInvalidArgument,
+			wantStatus: "INVALID_ARGUMENT",
+		},
+		{
+			name:       "Non-JSON body falls back to HTTP status",
+			statusCode: http.StatusNotFound,
+			body:       "<html>Not Found</html>",
+			wantErr:    ErrNotFound,
+			wantMsg:    "<html>Not Found</html>",
+		},
+		{
+			name:       "Empty body",
+			statusCode: http.StatusForbidden,
+			body:       "",
+			wantErr:    ErrPermissionDenied,
+			wantMsg:    "Forbidden",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			apiErr := parseAPIError(tt.statusCode, []byte(tt.body))
+			if !errors.Is(apiErr, tt.wantErr) {
+				t.Errorf("errors.Is(%v, %v) = false", apiErr, tt.wantErr)
+			}
+			if apiErr.Status != tt.wantStatus {
+				t.Errorf("Status = %q, want %q", apiErr.Status, tt.wantStatus)
+			}
+			if tt.wantMsg != "" && apiErr.Message != tt.wantMsg {
+				t.Errorf("Message = %q, want %q", apiErr.Message, tt.wantMsg)
+			}
+		})
+	}
+}
+
+func TestClient_ReturnsTypedErrors(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error":{"code":404,"message":"SKU 'MISSING' not found","status":"NOT_FOUND"}}`))
+	})
+
+	_, err := client.GetSKUPrice(context.Background(), "MISSING", "USD")
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected *APIError, got %T", err)
+	}
+	if apiErr.StatusCode != http.StatusNotFound {
+		t.Errorf("StatusCode = %d, want 404", apiErr.StatusCode)
+	}
+}
+
+func TestClient_RateLimitedAfterRetries(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte(`{"error":{"code":429,"message":"Quota exceeded","status":"RESOURCE_EXHAUSTED"}}`))
+	})
+
+	_, err := client.ListServices(context.Background(), 10, "")
+	if !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("expected ErrRateLimited, got %v", err)
+	}
+}
@@ -0,0 +1,92 @@
+package pricing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// iamCredentialsURL is the base URL of the IAM Service Account Credentials API
+const iamCredentialsURL = "https://iamcredentials.googleapis.com"
+
+// impersonatedTokenSource mints short-lived access tokens for a target service account
+// through the IAM Service Account Credentials API
+type impersonatedTokenSource struct {
+	ctx       context.Context
+	client    *http.Client // authenticated with the source credentials
+	endpoint  string
+	target    string
+	delegates []string
+	scopes    []string
+}
+
+// generateAccessTokenRequest is the body of a generateAccessToken call
+type generateAccessTokenRequest struct {
+	Delegates []string `json:"delegates,omitempty"`
+	Scope     []string `json:"scope"`
+	Lifetime  string   `json:"lifetime,omitempty"`
+}
+
+// generateAccessTokenResponse is the response of a generateAccessToken call
+type generateAccessTokenResponse struct {
+	AccessToken string    `json:"accessToken"`
+	ExpireTime  time.Time `json:"expireTime"`
+}
+
+// Token implements oauth2.TokenSource
+func (s *impersonatedTokenSource) Token() (*oauth2.Token, error) {
+	delegates := make([]string, len(s.delegates))
+	for i, d := range s.delegates {
+		delegates[i] = serviceAccountResource(d)
+	}
+
+	body, err := json.Marshal(generateAccessTokenRequest{
+		Delegates: delegates,
+		Scope:     s.scopes,
+		Lifetime:  "3600s",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	reqURL := fmt.Sprintf("%s/v1/%s:generateAccessToken", s.endpoint, serviceAccountResource(s.target))
+	req, err := http.NewRequestWithContext(s.ctx, http.MethodPost, reqURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create impersonation request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to impersonate %s: %w", s.target, err)
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to impersonate %s: %w", s.target, parseAPIError(resp.StatusCode, respBody))
+	}
+
+	var result generateAccessTokenResponse
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return nil, fmt.Errorf("failed to decode impersonation response: %w", err)
+	}
+
+	return &oauth2.Token{
+		AccessToken: result.AccessToken,
+		TokenType:   "Bearer",
+		Expiry:      result.ExpireTime,
+	}, nil
+}
+
+// serviceAccountResource returns the IAM resource name for a service account email
+func serviceAccountResource(email string) string {
+	return "projects/-/serviceAccounts/" + url.PathEscape(email)
+}
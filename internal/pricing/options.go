@@ -0,0 +1,233 @@
+package pricing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+)
+
+const (
+	// billingScope is the OAuth scope required to read the Cloud Billing catalog
+	billingScope = "https://www.googleapis.com/auth/cloud-billing.readonly"
+	// cloudPlatformScope is required by the source credentials when impersonating a service account
+	cloudPlatformScope = "https://www.googleapis.com/auth/cloud-platform"
+)
+
+// CredentialMode is the way a Client authenticates its requests
+type CredentialMode string
+
+// Credential modes, selected by the Options given to NewClient
+const (
+	CredentialsADC           CredentialMode = "application default credentials"
+	CredentialsAPIKey        CredentialMode = "API key"
+	CredentialsFile          CredentialMode = "credentials file"
+	CredentialsImpersonation CredentialMode = "service account impersonation"
+	CredentialsHTTPClient    CredentialMode = "custom HTTP client"
+)
+
+// Option configures a Client created by NewClient
+type Option func(*clientConfig)
+
+// clientConfig holds the settings collected from Options
+type clientConfig struct {
+	baseURL              string
+	apiKey               string
+	credentialsFile      string
+	impersonateTarget    string
+	impersonateDelegates []string
+	quotaProject         string
+	httpClient           *http.Client
+	retryPolicy          RetryPolicy
+	requestsPerSecond    float64
+	burst                int
+}
+
+// WithBaseURL overrides the Cloud Billing API endpoint (e.g. a local fake or a proxy mirror)
+func WithBaseURL(baseURL string) Option {
+	return func(c *clientConfig) {
+		c.baseURL = strings.TrimRight(baseURL, "/")
+	}
+}
+
+// WithAPIKey authenticates requests with an API key instead of OAuth credentials
+func WithAPIKey(apiKey string) Option {
+	return func(c *clientConfig) {
+		c.apiKey = apiKey
+	}
+}
+
+// WithCredentialsFile authenticates with an explicit service account (or other) JSON key file instead of ADC
+func WithCredentialsFile(path string) Option {
+	return func(c *clientConfig) {
+		c.credentialsFile = path
+	}
+}
+
+// WithImpersonation impersonates the target service account using the source credentials.
+// Optional delegates form the delegation chain, in order.
+func WithImpersonation(targetServiceAccount string, delegates ...string) Option {
+	return func(c *clientConfig) {
+		c.impersonateTarget = targetServiceAccount
+		c.impersonateDelegates = delegates
+	}
+}
+
+// WithQuotaProject sets the project used for quota and billing of API requests
+func WithQuotaProject(projectID string) Option {
+	return func(c *clientConfig) {
+		c.quotaProject = projectID
+	}
+}
+
+// WithHTTPClient uses the given HTTP client as-is. Credential options are ignored;
+// the client is expected to handle authentication itself.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *clientConfig) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithRetryPolicy overrides the retry policy for failed requests
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *clientConfig) {
+		c.retryPolicy = policy
+	}
+}
+
+// WithRateLimit overrides the client-side rate limit. A non-positive rps disables rate limiting.
+func WithRateLimit(rps float64, burst int) Option {
+	return func(c *clientConfig) {
+		c.requestsPerSecond = rps
+		c.burst = burst
+	}
+}
+
+// validate checks for conflicting options
+func (c *clientConfig) validate() error {
+	if c.apiKey != "" && c.credentialsFile != "" {
+		return fmt.Errorf("API key and credentials file cannot be used together")
+	}
+	if c.apiKey != "" && c.impersonateTarget != "" {
+		return fmt.Errorf("service account impersonation requires OAuth credentials, not an API key")
+	}
+	return nil
+}
+
+// credentials returns the authentication mode of the options and the credentials file or
+// impersonated service account it uses, if any
+func (c *clientConfig) credentials() (CredentialMode, string) {
+	switch {
+	case c.httpClient != nil:
+		return CredentialsHTTPClient, ""
+	case c.apiKey != "":
+		return CredentialsAPIKey, ""
+	case c.impersonateTarget != "":
+		return CredentialsImpersonation, c.impersonateTarget
+	case c.credentialsFile != "":
+		return CredentialsFile, c.credentialsFile
+	}
+	return CredentialsADC, ""
+}
+
+// newHTTPClient builds the HTTP client for the configured authentication mode
+func (c *clientConfig) newHTTPClient(ctx context.Context) (*http.Client, error) {
+	if c.httpClient != nil {
+		return c.httpClient, nil
+	}
+
+	if c.apiKey != "" {
+		return &http.Client{
+			Transport: &headerTransport{
+				base:    http.DefaultTransport,
+				headers: c.headers(),
+			},
+		}, nil
+	}
+
+	ts, err := c.tokenSource(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	client := oauth2.NewClient(ctx, ts)
+	if headers := c.headers(); len(headers) > 0 {
+		client.Transport = &headerTransport{base: client.Transport, headers: headers}
+	}
+	return client, nil
+}
+
+// tokenSource returns the OAuth token source for ADC, a credentials file, or impersonation
+func (c *clientConfig) tokenSource(ctx context.Context) (oauth2.TokenSource, error) {
+	scope := billingScope
+	if c.impersonateTarget != "" {
+		scope = cloudPlatformScope
+	}
+
+	var creds *google.Credentials
+	if c.credentialsFile != "" {
+		data, err := os.ReadFile(c.credentialsFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read credentials file: %w", err)
+		}
+		creds, err = google.CredentialsFromJSON(ctx, data, scope)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse credentials file: %w: %w", ErrUnauthenticated, err)
+		}
+	} else {
+		var err error
+		creds, err = google.FindDefaultCredentials(ctx, scope)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find default credentials: %w: %w", ErrUnauthenticated, err)
+		}
+	}
+
+	if c.impersonateTarget == "" {
+		return creds.TokenSource, nil
+	}
+
+	return oauth2.ReuseTokenSource(nil, &impersonatedTokenSource{
+		ctx:       ctx,
+		client:    oauth2.NewClient(ctx, creds.TokenSource),
+		endpoint:  iamCredentialsURL,
+		target:    c.impersonateTarget,
+		delegates: c.impersonateDelegates,
+		scopes:    []string{billingScope},
+	}), nil
+}
+
+// headers returns the extra request headers for the configured options
+func (c *clientConfig) headers() map[string]string {
+	headers := make(map[string]string)
+	if c.apiKey != "" {
+		headers["X-Goog-Api-Key"] = c.apiKey
+	}
+	if c.quotaProject != "" {
+		headers["X-Goog-User-Project"] = c.quotaProject
+	}
+	return headers
+}
+
+// headerTransport adds fixed headers to every request
+type headerTransport struct {
+	base    http.RoundTripper
+	headers map[string]string
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// RoundTrippers must not modify the caller's request
+	req = req.Clone(req.Context())
+	for k, v := range t.headers {
+		req.Header.Set(k, v)
+	}
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return base.RoundTrip(req)
+}
@@ -0,0 +1,200 @@
+package pricing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewClient_APIKeyAndCustomEndpoint(t *testing.T) {
+	var gotPath, gotKey, gotProject, gotAuth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotKey = r.Header.Get("X-Goog-Api-Key")
+		gotProject = r.Header.Get("X-Goog-User-Project")
+		gotAuth = r.Header.Get("Authorization")
+		w.Write([]byte(`{"services":[{"serviceId":"152E-C115-5142","displayName":"Cloud Run"}]}`))
+	}))
+	defer srv.Close()
+
+	client, err := NewClient(context.Background(),
+		WithBaseURL(srv.URL+"/"),
+		WithAPIKey("test-key"),
+		WithQuotaProject("my-project"),
+	)
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+
+	if _, err := client.ListServices(context.Background(), 10, ""); err != nil {
+		t.Fatalf("ListServices failed: %v", err)
+	}
+
+	if gotPath != "/v2beta/services" {
+		t.Errorf("path = %q, want /v2beta/services", gotPath)
+	}
+	if gotKey != "test-key" {
+		t.Errorf("X-Goog-Api-Key = %q, want test-key", gotKey)
+	}
+	if gotProject != "my-project" {
+		t.Errorf("X-Goog-User-Project = %q, want my-project", gotProject)
+	}
+	if gotAuth != "" {
+		t.Errorf("Authorization header should not be sent in API key mode, got %q", gotAuth)
+	}
+}
+
+func TestNewClient_CredentialErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"error":{"code":401,"message":"API key not valid","status":"UNAUTHENTICATED"}}`))
+	}))
+	defer srv.Close()
+
+	client, err := NewClient(context.Background(), WithBaseURL(srv.URL), WithAPIKey("revoked-key"))
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+
+	// Unauthenticated errors name the credentials in use
+	_, err = client.ListServices(context.Background(), 10, "")
+	var credentialErr *CredentialError
+	if !errors.As(err, &credentialErr) || !errors.Is(err, ErrUnauthenticated) {
+		t.Fatalf("err = %v, want a CredentialError", err)
+	}
+	if credentialErr.Mode != CredentialsAPIKey {
+		t.Errorf("Mode = %q, want %q", credentialErr.Mode, CredentialsAPIKey)
+	}
+}
+
+func TestClientConfig_Credentials(t *testing.T) {
+	tests := []struct {
+		cfg         clientConfig
+		wantMode    CredentialMode
+		wantSubject string
+	}{
+		{clientConfig{}, CredentialsADC, ""},
+		{clientConfig{apiKey: "key"}, CredentialsAPIKey, ""},
+		{clientConfig{credentialsFile: "sa.json"}, CredentialsFile, "sa.json"},
+		{clientConfig{credentialsFile: "sa.json", impersonateTarget: "sa@p.iam.gserviceaccount.com"}, CredentialsImpersonation, "sa@p.iam.gserviceaccount.com"},
+		{clientConfig{apiKey: "key", httpClient: http.DefaultClient}, CredentialsHTTPClient, ""},
+	}
+	for _, tt := range tests {
+		if mode, subject := tt.cfg.credentials(); mode != tt.wantMode || subject != tt.wantSubject {
+			t.Errorf("credentials() = %q, %q, want %q, %q", mode, subject, tt.wantMode, tt.wantSubject)
+		}
+	}
+}
+
+func TestNewClient_WithHTTPClient(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"name":"skus/AAAA/price","currencyCode":"JPY"}`))
+	}))
+	defer srv.Close()
+
+	client, err := NewClient(context.Background(), WithBaseURL(srv.URL), WithHTTPClient(srv.Client()), WithRateLimit(0, 0))
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	if client.limiter != nil {
+		t.Error("expected rate limiting to be disabled")
+	}
+
+	resp, err := client.GetSKUPrice(context.Background(), "AAAA", "JPY")
+	if err != nil {
+		t.Fatalf("GetSKUPrice failed: %v", err)
+	}
+	if resp.CurrencyCode != "JPY" {
+		t.Errorf("CurrencyCode = %q, want JPY", resp.CurrencyCode)
+	}
+}
+
+func TestNewClient_ConflictingOptions(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+	}{
+		{"API key with credentials file", []Option{WithAPIKey("k"), WithCredentialsFile("/tmp/key.json")}},
+		{"API key with impersonation", []Option{WithAPIKey("k"), WithImpersonation("sa@project.iam.gserviceaccount.com")}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewClient(context.Background(), tt.opts...); err == nil {
+				t.Error("expected error for conflicting options")
+			}
+		})
+	}
+}
+
+func TestNewClient_MissingCredentialsFile(t *testing.T) {
+	_, err := NewClient(context.Background(), WithCredentialsFile("/nonexistent/key.json"))
+	if err == nil || !strings.Contains(err.Error(), "credentials file") {
+		t.Errorf("expected credentials file error, got %v", err)
+	}
+}
+
+func TestImpersonatedTokenSource(t *testing.T) {
+	expiry := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+
+	var gotPath string
+	var gotBody generateAccessTokenRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		json.NewDecoder(r.Body).Decode(&gotBody)
+		json.NewEncoder(w).Encode(map[string]string{
+			"accessToken": "impersonated-token",
+			"expireTime":  expiry.Format(time.RFC3339),
+		})
+	}))
+	defer srv.Close()
+
+	ts := &impersonatedTokenSource{
+		ctx:       context.Background(),
+		client:    srv.Client(),
+		endpoint:  srv.URL,
+		target:    "pricing-reader@my-project.iam.gserviceaccount.com",
+		delegates: []string{"delegate@my-project.iam.gserviceaccount.com"},
+		scopes:    []string{billingScope},
+	}
+
+	token, err := ts.Token()
+	if err != nil {
+		t.Fatalf("Token failed: %v", err)
+	}
+
+	if token.AccessToken != "impersonated-token" {
+		t.Errorf("AccessToken = %q, want impersonated-token", token.AccessToken)
+	}
+	if !token.Expiry.Equal(expiry) {
+		t.Errorf("Expiry = %v, want %v", token.Expiry, expiry)
+	}
+	wantPath := "/v1/projects/-/serviceAccounts/pricing-reader@my-project.iam.gserviceaccount.com:generateAccessToken"
+	if gotPath != wantPath {
+		t.Errorf("path = %q, want %q", gotPath, wantPath)
+	}
+	if len(gotBody.Delegates) != 1 || gotBody.Delegates[0] != "projects/-/serviceAccounts/delegate@my-project.iam.gserviceaccount.com" {
+		t.Errorf("delegates = %v", gotBody.Delegates)
+	}
+	if len(gotBody.Scope) != 1 || gotBody.Scope[0] != billingScope {
+		t.Errorf("scope = %v, want [%s]", gotBody.Scope, billingScope)
+	}
+}
+
+func TestImpersonatedTokenSource_PermissionDenied(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"error":{"code":403,"message":"iam.serviceAccounts.getAccessToken denied","status":"PERMISSION_DENIED"}}`))
+	}))
+	defer srv.Close()
+
+	ts := &impersonatedTokenSource{ctx: context.Background(), client: srv.Client(), endpoint: srv.URL, target: "sa@p.iam.gserviceaccount.com"}
+	if _, err := ts.Token(); err == nil || !strings.Contains(err.Error(), "PERMISSION_DENIED") {
+		t.Errorf("expected permission denied error, got %v", err)
+	}
+}
@@ -0,0 +1,63 @@
+package pricing
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// PriceListClient is implemented by clients that can list the published prices of a SKU
+type PriceListClient interface {
+	ListPrices(ctx context.Context, skuID string, currencyCode string, pageSize int, pageToken string) (*ListPricesResponse, error)
+}
+
+// Ensure Client implements PriceListClient interface
+var _ PriceListClient = (*Client)(nil)
+
+// ListPrices lists the latest published prices of a SKU. The API does not report when a price
+// took effect, so past prices cannot be listed.
+func (c *Client) ListPrices(ctx context.Context, skuID string, currencyCode string, pageSize int, pageToken string) (*ListPricesResponse, error) {
+	if skuID == "" {
+		return nil, fmt.Errorf("skuID is required")
+	}
+
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+
+	params := url.Values{}
+	params.Set("pageSize", strconv.Itoa(pageSize))
+	if currencyCode != "" {
+		params.Set("currencyCode", currencyCode)
+	}
+	if pageToken != "" {
+		params.Set("pageToken", pageToken)
+	}
+
+	reqURL := fmt.Sprintf("%s/v2beta/skus/%s/prices?%s", c.baseURL, skuID, params.Encode())
+
+	var result ListPricesResponse
+	if err := c.doGet(ctx, reqURL, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// ListAllPrices lists every published price of a SKU, following pagination
+func ListAllPrices(ctx context.Context, client PriceListClient, skuID string, currencyCode string) ([]Price, error) {
+	var prices []Price
+	pageToken := ""
+	for {
+		resp, err := client.ListPrices(ctx, skuID, currencyCode, DefaultPageSize, pageToken)
+		if err != nil {
+			return nil, err
+		}
+		prices = append(prices, resp.Prices...)
+		if resp.NextPageToken == "" {
+			return prices, nil
+		}
+		pageToken = resp.NextPageToken
+	}
+}
@@ -0,0 +1,45 @@
+package pricing
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestClient_ListPrices(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2beta/skus/CPU-SKU/prices" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		query := r.URL.Query()
+		if query.Get("currencyCode") != "USD" {
+			t.Errorf("currencyCode = %q", query.Get("currencyCode"))
+		}
+		// Only documented parameters are sent
+		for param := range query {
+			if param != "currencyCode" && param != "pageSize" && param != "pageToken" {
+				t.Errorf("unexpected query parameter %q", param)
+			}
+		}
+		if query.Get("pageToken") == "" {
+			w.Write([]byte(`{"prices": [{"name": "skus/CPU-SKU/prices/USD", "currencyCode": "USD",
+				"rate": {"tiers": [{"listPrice": {"currencyCode": "USD", "nanos": 24000}}]}}],
+				"nextPageToken": "page-2"}`))
+			return
+		}
+		w.Write([]byte(`{"prices": [{"name": "skus/CPU-SKU/prices/USD-2", "currencyCode": "USD",
+			"rate": {"tiers": [{"listPrice": {"currencyCode": "USD", "nanos": 21000}}]}}]}`))
+	})
+
+	prices, err := ListAllPrices(context.Background(), client, "CPU-SKU", "USD")
+	if err != nil {
+		t.Fatalf("ListAllPrices failed: %v", err)
+	}
+	if len(prices) != 2 || prices[0].Name != "skus/CPU-SKU/prices/USD" || prices[1].Name != "skus/CPU-SKU/prices/USD-2" {
+		t.Errorf("prices = %+v", prices)
+	}
+
+	if _, err := client.ListPrices(context.Background(), "", "USD", 0, ""); err == nil {
+		t.Error("expected error without SKU ID")
+	}
+}
@@ -0,0 +1,71 @@
+package pricing
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultRequestsPerSecond is the default client-side request rate to the Cloud Billing API
+	DefaultRequestsPerSecond = 10
+	// DefaultBurst is the default number of requests allowed in a burst
+	DefaultBurst = 20
+)
+
+// rateLimiter is a token bucket rate limiter shared by all requests of a Client
+type rateLimiter struct {
+	mutex    sync.Mutex
+	rate     float64 // tokens added per second
+	burst    float64 // bucket capacity
+	tokens   float64
+	lastFill time.Time
+}
+
+// newRateLimiter creates a token bucket that refills at rps tokens per second.
+// It returns nil (no limiting) when rps is not positive.
+func newRateLimiter(rps float64, burst int) *rateLimiter {
+	if rps <= 0 {
+		return nil
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	return &rateLimiter{
+		rate:     rps,
+		burst:    float64(burst),
+		tokens:   float64(burst),
+		lastFill: time.Now(),
+	}
+}
+
+// Wait blocks until a token is available or the context is done
+func (l *rateLimiter) Wait(ctx context.Context) error {
+	for {
+		delay := l.reserve()
+		if delay == 0 {
+			return nil
+		}
+		if err := sleepContext(ctx, delay); err != nil {
+			return err
+		}
+	}
+}
+
+// reserve takes a token if one is available, otherwise it returns how long to wait for the next one
+func (l *rateLimiter) reserve() time.Duration {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	now := time.Now()
+	l.tokens = min(l.burst, l.tokens+now.Sub(l.lastFill).Seconds()*l.rate)
+	l.lastFill = now
+
+	if l.tokens >= 1 {
+		l.tokens--
+		return 0
+	}
+
+	missing := 1 - l.tokens
+	return max(time.Nanosecond, time.Duration(missing/l.rate*float64(time.Second)))
+}
@@ -0,0 +1,194 @@
+package pricing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// RetryPolicy controls how failed Cloud Billing API requests are retried
+type RetryPolicy struct {
+	// MaxRetries is the number of retries after the initial attempt (0 disables retries)
+	MaxRetries int
+	// InitialBackoff is the backoff before the first retry
+	InitialBackoff time.Duration
+	// MaxBackoff caps the exponential backoff between retries
+	MaxBackoff time.Duration
+	// Multiplier is the factor applied to the backoff after each retry
+	Multiplier float64
+}
+
+// DefaultRetryPolicy returns the default retry policy
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxRetries:     4,
+		InitialBackoff: 500 * time.Millisecond,
+		MaxBackoff:     30 * time.Second,
+		Multiplier:     2,
+	}
+}
+
+// backoff returns the jittered delay before the given retry attempt (starting at 1).
+// Half of the delay is fixed and half is random so that parallel callers spread out.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+
+	delay := float64(p.InitialBackoff) * math.Pow(multiplier, float64(attempt-1))
+	if p.MaxBackoff > 0 && delay > float64(p.MaxBackoff) {
+		delay = float64(p.MaxBackoff)
+	}
+
+	half := delay / 2
+	return time.Duration(half + rand.Float64()*half)
+}
+
+// isRetryableStatus reports whether an HTTP status code indicates a transient failure.
+// Other 5xx codes (e.g. 501 Not Implemented) fail the same way on every attempt.
+func isRetryableStatus(code int) bool {
+	switch code {
+	case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// isTransientError reports whether a transport error is worth retrying
+func isTransientError(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// parseRetryAfter parses a Retry-After header given in seconds or as an HTTP date
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		if d := t.Sub(now); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+// doGet performs a GET request against the Cloud Billing API and decodes the JSON response into out.
+// Requests are rate limited, and 429/500/502/503/504 responses and transient network errors are
+// retried with exponential backoff, honoring Retry-After when the server provides it. A Retry-After
+// longer than MaxBackoff is not waited for: the call fails with a *RateLimitError instead.
+func (c *Client) doGet(ctx context.Context, reqURL string, out interface{}) error {
+	for attempt := 0; ; attempt++ {
+		retryAfter, retryable, err := c.doGetOnce(ctx, reqURL, out)
+		if err == nil {
+			return nil
+		}
+		if !retryable || attempt >= c.retryPolicy.MaxRetries {
+			return c.credentialError(err)
+		}
+
+		delay := c.retryPolicy.backoff(attempt + 1)
+		if retryAfter > 0 {
+			// Retrying before the server's delay has passed would only fail again
+			if c.retryPolicy.MaxBackoff > 0 && retryAfter > c.retryPolicy.MaxBackoff {
+				return &RateLimitError{RetryAfter: retryAfter, Err: err}
+			}
+			delay = retryAfter
+		}
+
+		log.Printf("PricingClient: retrying request (attempt %d/%d) in %s: %v",
+			attempt+1, c.retryPolicy.MaxRetries, delay, err)
+
+		if sleepContext(ctx, delay) != nil {
+			return err
+		}
+	}
+}
+
+// credentialError wraps an unauthenticated error in a CredentialError naming the client's credentials
+func (c *Client) credentialError(err error) error {
+	if c.credentialMode == "" || !errors.Is(err, ErrUnauthenticated) {
+		return err
+	}
+	return &CredentialError{Mode: c.credentialMode, Subject: c.credentialSubject, Err: err}
+}
+
+// doGetOnce performs a single request attempt. It reports the server-requested
+// retry delay (if any) and whether a failure is retryable.
+func (c *Client) doGetOnce(ctx context.Context, reqURL string, out interface{}) (time.Duration, bool, error) {
+	if c.limiter != nil {
+		if err := c.limiter.Wait(ctx); err != nil {
+			return 0, false, fmt.Errorf("rate limiter: %w", err)
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		// Token refresh failures surface as transport errors; they are never transient
+		var retrieveErr *oauth2.RetrieveError
+		if errors.As(err, &retrieveErr) {
+			return 0, false, fmt.Errorf("failed to obtain access token: %w: %w", ErrUnauthenticated, err)
+		}
+		return 0, isTransientError(ctx, err), fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		apiErr := parseAPIError(resp.StatusCode, body)
+		if !isRetryableStatus(resp.StatusCode) {
+			return 0, false, apiErr
+		}
+		retryAfter, _ := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+		return retryAfter, true, apiErr
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return 0, isTransientError(ctx, err), fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return 0, false, nil
+}
+
+// sleepContext sleeps for d or until the context is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
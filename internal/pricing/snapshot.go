@@ -0,0 +1,245 @@
+package pricing
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nozomi-koborinai/gcp-cost-mcp-server/internal/money"
+)
+
+// SnapshotVersion is the current catalog snapshot format version
+const SnapshotVersion = 1
+
+// Snapshot is a serialized copy of (part of) the Cloud Billing Catalog
+type Snapshot struct {
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	// Currencies lists the currencies prices were fetched in
+	Currencies []string `json:"currencies,omitempty"`
+	// Services lists all services in the snapshot
+	Services []Service `json:"services"`
+	// SKUs maps service IDs to their SKUs
+	SKUs map[string][]SKU `json:"skus"`
+	// Prices maps currency codes to SKU IDs to their prices
+	Prices map[string]map[string]*GetPriceResponse `json:"prices"`
+}
+
+// NewSnapshot creates an empty snapshot
+func NewSnapshot() *Snapshot {
+	return &Snapshot{
+		Version:   SnapshotVersion,
+		CreatedAt: time.Now().UTC(),
+		SKUs:      make(map[string][]SKU),
+		Prices:    make(map[string]map[string]*GetPriceResponse),
+	}
+}
+
+// SetPrice stores the price of a SKU in the given currency
+func (s *Snapshot) SetPrice(skuID, currencyCode string, price *GetPriceResponse) {
+	if s.Prices == nil {
+		s.Prices = make(map[string]map[string]*GetPriceResponse)
+	}
+	if s.Prices[currencyCode] == nil {
+		s.Prices[currencyCode] = make(map[string]*GetPriceResponse)
+	}
+	s.Prices[currencyCode][skuID] = price
+}
+
+// Price returns the stored price of a SKU in the given currency
+func (s *Snapshot) Price(skuID, currencyCode string) (*GetPriceResponse, bool) {
+	price, ok := s.Prices[currencyCode][skuID]
+	return price, ok
+}
+
+// LoadSnapshot reads a snapshot from a JSON file. Gzip-compressed files are detected automatically.
+func LoadSnapshot(path string) (*Snapshot, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open snapshot: %w", err)
+	}
+	defer f.Close()
+
+	snapshot, err := ReadSnapshot(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot %s: %w", path, err)
+	}
+	return snapshot, nil
+}
+
+// ReadSnapshot decodes a snapshot from r. Gzip-compressed input is detected automatically.
+func ReadSnapshot(r io.Reader) (*Snapshot, error) {
+	br := bufio.NewReader(r)
+
+	var reader io.Reader = br
+	if magic, err := br.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open gzip stream: %w", err)
+		}
+		defer gz.Close()
+		reader = gz
+	}
+
+	var snapshot Snapshot
+	if err := json.NewDecoder(reader).Decode(&snapshot); err != nil {
+		return nil, fmt.Errorf("failed to decode snapshot: %w", err)
+	}
+	if snapshot.Version > SnapshotVersion {
+		return nil, fmt.Errorf("unsupported snapshot version %d (max %d)", snapshot.Version, SnapshotVersion)
+	}
+	if snapshot.SKUs == nil {
+		snapshot.SKUs = make(map[string][]SKU)
+	}
+	if snapshot.Prices == nil {
+		snapshot.Prices = make(map[string]map[string]*GetPriceResponse)
+	}
+
+	return &snapshot, nil
+}
+
+// SaveSnapshot writes a snapshot to a JSON file, gzip-compressed when the path ends in ".gz".
+// The file is written atomically.
+func SaveSnapshot(path string, snapshot *Snapshot) error {
+	tmpPath := path + ".tmp"
+	f, err := os.Create(tmpPath)
+	if err != nil {
+		return fmt.Errorf("failed to create snapshot file: %w", err)
+	}
+
+	if err := WriteSnapshot(f, snapshot, strings.HasSuffix(path, ".gz")); err != nil {
+		f.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to write snapshot file: %w", err)
+	}
+
+	return os.Rename(tmpPath, path)
+}
+
+// WriteSnapshot encodes a snapshot to w, optionally gzip-compressed
+func WriteSnapshot(w io.Writer, snapshot *Snapshot, compress bool) error {
+	if compress {
+		gz := gzip.NewWriter(w)
+		if err := json.NewEncoder(gz).Encode(snapshot); err != nil {
+			return fmt.Errorf("failed to encode snapshot: %w", err)
+		}
+		return gz.Close()
+	}
+
+	if err := json.NewEncoder(w).Encode(snapshot); err != nil {
+		return fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+	return nil
+}
+
+// SnapshotClient is a PricingClient backed by a catalog snapshot instead of the live API
+type SnapshotClient struct {
+	snapshot *Snapshot
+}
+
+// Ensure SnapshotClient implements PricingClient interface
+var _ PricingClient = (*SnapshotClient)(nil)
+
+// NewSnapshotClient creates a PricingClient that serves data from the given snapshot
+func NewSnapshotClient(snapshot *Snapshot) *SnapshotClient {
+	services := make([]Service, len(snapshot.Services))
+	copy(services, snapshot.Services)
+	sort.SliceStable(services, func(i, j int) bool {
+		return services[i].ServiceID < services[j].ServiceID
+	})
+
+	sorted := *snapshot
+	sorted.Services = services
+	return &SnapshotClient{snapshot: &sorted}
+}
+
+// Snapshot returns the snapshot backing this client
+func (c *SnapshotClient) Snapshot() *Snapshot {
+	return c.snapshot
+}
+
+// ListServices lists the services contained in the snapshot
+func (c *SnapshotClient) ListServices(ctx context.Context, pageSize int, pageToken string) (*ListServicesResponse, error) {
+	page, next, err := paginate(c.snapshot.Services, pageSize, pageToken)
+	if err != nil {
+		return nil, err
+	}
+	return &ListServicesResponse{Services: page, NextPageToken: next}, nil
+}
+
+// ListSKUs lists the SKUs of a service contained in the snapshot
+func (c *SnapshotClient) ListSKUs(ctx context.Context, serviceID string, pageSize int, pageToken string) (*ListSKUsResponse, error) {
+	if serviceID == "" {
+		return nil, fmt.Errorf("serviceID is required")
+	}
+
+	skus, ok := c.snapshot.SKUs[serviceID]
+	if !ok {
+		return nil, fmt.Errorf("service %s is not in the catalog snapshot: %w", serviceID, ErrNotFound)
+	}
+
+	page, next, err := paginate(skus, pageSize, pageToken)
+	if err != nil {
+		return nil, err
+	}
+	return &ListSKUsResponse{SKUs: page, NextPageToken: next}, nil
+}
+
+// GetSKUPrice gets the price of a SKU from the snapshot
+func (c *SnapshotClient) GetSKUPrice(ctx context.Context, skuID string, currencyCode string) (*GetPriceResponse, error) {
+	if skuID == "" {
+		return nil, fmt.Errorf("skuID is required")
+	}
+	if currencyCode == "" {
+		currencyCode = "USD"
+	}
+
+	price, ok := c.snapshot.Price(skuID, currencyCode)
+	if !ok {
+		return nil, fmt.Errorf("price for SKU %s in %s is not in the catalog snapshot: %w", skuID, currencyCode, ErrNotFound)
+	}
+	return price, nil
+}
+
+// CalculateCost calculates the estimated cost based on usage amount and pricing tiers
+func (c *SnapshotClient) CalculateCost(rate *Rate, usageAmount money.Decimal) (money.Decimal, error) {
+	return CalculateCost(rate, usageAmount)
+}
+
+// paginate returns one page of items. Page tokens are offsets into the list.
+func paginate[T any](items []T, pageSize int, pageToken string) ([]T, string, error) {
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+
+	offset := 0
+	if pageToken != "" {
+		var err error
+		offset, err = strconv.Atoi(pageToken)
+		if err != nil || offset < 0 || offset > len(items) {
+			return nil, "", fmt.Errorf("invalid page token %q: %w", pageToken, ErrInvalidArgument)
+		}
+	}
+
+	end := min(len(items), offset+pageSize)
+	page := make([]T, end-offset)
+	copy(page, items[offset:end])
+
+	next := ""
+	if end < len(items) {
+		next = strconv.Itoa(end)
+	}
+	return page, next, nil
+}
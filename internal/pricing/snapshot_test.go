@@ -0,0 +1,120 @@
+package pricing
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func testSnapshot() *Snapshot {
+	snapshot := NewSnapshot()
+	snapshot.Services = []Service{
+		{Name: "services/9662-B51E-5089", ServiceID: "9662-B51E-5089", DisplayName: "Cloud SQL"},
+		{Name: "services/152E-C115-5142", ServiceID: "152E-C115-5142", DisplayName: "Cloud Run"},
+		{Name: "services/6F81-5844-456A", ServiceID: "6F81-5844-456A", DisplayName: "Compute Engine"},
+	}
+	snapshot.SKUs["152E-C115-5142"] = []SKU{
+		{SKUID: "CPU-SKU", DisplayName: "CPU Allocation Time"},
+		{SKUID: "MEM-SKU", DisplayName: "Memory Allocation Time"},
+		{SKUID: "REQ-SKU", DisplayName: "Requests"},
+	}
+	snapshot.SetPrice("CPU-SKU", "USD", &GetPriceResponse{
+		Name:         "skus/CPU-SKU/price",
+		CurrencyCode: "USD",
+		SKUPrices: []SKUPrice{{
+			ConsumptionModel: "DEFAULT",
+			Rate: &Rate{
+				UnitInfo: UnitInfo{Unit: "s", UnitQuantity: Amount{Value: "1"}},
+				Tiers:    []Tier{{StartAmount: Amount{Value: "0"}, ListPrice: Money{CurrencyCode: "USD", Nanos: 24000}}},
+			},
+		}},
+	})
+	return snapshot
+}
+
+func TestSnapshot_SaveAndLoad(t *testing.T) {
+	for _, name := range []string{"catalog.json", "catalog.json.gz"} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), name)
+			if err := SaveSnapshot(path, testSnapshot()); err != nil {
+				t.Fatalf("SaveSnapshot failed: %v", err)
+			}
+
+			loaded, err := LoadSnapshot(path)
+			if err != nil {
+				t.Fatalf("LoadSnapshot failed: %v", err)
+			}
+			if loaded.Version != SnapshotVersion {
+				t.Errorf("Version = %d, want %d", loaded.Version, SnapshotVersion)
+			}
+			if len(loaded.Services) != 3 {
+				t.Errorf("got %d services, want 3", len(loaded.Services))
+			}
+			if len(loaded.SKUs["152E-C115-5142"]) != 3 {
+				t.Errorf("got %d Cloud Run SKUs, want 3", len(loaded.SKUs["152E-C115-5142"]))
+			}
+			price, ok := loaded.Price("CPU-SKU", "USD")
+			if !ok || price.SKUPrices[0].Rate.Tiers[0].ListPrice.Nanos != 24000 {
+				t.Errorf("price not preserved: %+v", price)
+			}
+		})
+	}
+}
+
+func TestSnapshotClient_Pagination(t *testing.T) {
+	client := NewSnapshotClient(testSnapshot())
+	ctx := context.Background()
+
+	first, err := client.ListServices(ctx, 2, "")
+	if err != nil {
+		t.Fatalf("ListServices failed: %v", err)
+	}
+	if len(first.Services) != 2 || first.NextPageToken == "" {
+		t.Fatalf("first page = %+v", first)
+	}
+	// Services are ordered deterministically by ID
+	if first.Services[0].ServiceID != "152E-C115-5142" {
+		t.Errorf("first service = %q, want 152E-C115-5142", first.Services[0].ServiceID)
+	}
+
+	second, err := client.ListServices(ctx, 2, first.NextPageToken)
+	if err != nil {
+		t.Fatalf("ListServices failed: %v", err)
+	}
+	if len(second.Services) != 1 || second.NextPageToken != "" {
+		t.Errorf("second page = %+v", second)
+	}
+
+	if _, err := client.ListServices(ctx, 2, "bogus"); !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("expected ErrInvalidArgument for bad token, got %v", err)
+	}
+
+	skus, err := client.ListSKUs(ctx, "152E-C115-5142", 0, "")
+	if err != nil {
+		t.Fatalf("ListSKUs failed: %v", err)
+	}
+	if len(skus.SKUs) != 3 {
+		t.Errorf("got %d SKUs, want 3", len(skus.SKUs))
+	}
+}
+
+func TestSnapshotClient_NotFound(t *testing.T) {
+	client := NewSnapshotClient(testSnapshot())
+	ctx := context.Background()
+
+	if _, err := client.ListSKUs(ctx, "UNKNOWN", 0, ""); !errors.Is(err, ErrNotFound) {
+		t.Errorf("ListSKUs: expected ErrNotFound, got %v", err)
+	}
+	if _, err := client.GetSKUPrice(ctx, "CPU-SKU", "JPY"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetSKUPrice: expected ErrNotFound for missing currency, got %v", err)
+	}
+
+	price, err := client.GetSKUPrice(ctx, "CPU-SKU", "")
+	if err != nil {
+		t.Fatalf("GetSKUPrice with default currency failed: %v", err)
+	}
+	if price.CurrencyCode != "USD" {
+		t.Errorf("CurrencyCode = %q, want USD", price.CurrencyCode)
+	}
+}
@@ -0,0 +1,116 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/nozomi-koborinai/gcp-cost-mcp-server/internal/money"
+	"github.com/nozomi-koborinai/gcp-cost-mcp-server/internal/pricing"
+)
+
+// ContractPriceInfo is the effective price of a SKU for the configured billing account
+type ContractPriceInfo struct {
+	BillingAccount  string        `json:"billing_account"`
+	PriceReason     string        `json:"price_reason,omitempty"`
+	DiscountPercent float64       `json:"discount_percent,omitempty"`
+	Unit            string        `json:"unit,omitempty"`
+	UnitQuantity    float64       `json:"unit_quantity,omitempty"`
+	Tiers           []PricingTier `json:"tiers,omitempty"`
+	// Note explains why the contract price is missing
+	Note string `json:"note,omitempty"`
+}
+
+// ContractCost is the cost of an estimate at the billing account's contract price
+type ContractCost struct {
+	BillingAccount         string  `json:"billing_account"`
+	PriceReason            string  `json:"price_reason,omitempty"`
+	EstimatedCost          float64 `json:"estimated_cost"`
+	EstimatedCostExact     string  `json:"estimated_cost_exact,omitempty"`
+	EstimatedCostFormatted string  `json:"estimated_cost_formatted,omitempty"`
+	// Savings relative to the list price cost
+	Savings        float64 `json:"savings"`
+	SavingsPercent float64 `json:"savings_percent"`
+	// Note explains why the contract cost is missing
+	Note string `json:"note,omitempty"`
+}
+
+// getContractPrice fetches the contract price of a SKU. Failures are logged and returned as a
+// note instead of an error so that list prices are still reported.
+func getContractPrice(ctx context.Context, contract *pricing.ContractPricer, skuID, currencyCode string) (*pricing.BillingAccountPrice, string) {
+	price, err := contract.GetSKUPrice(ctx, skuID, currencyCode)
+	if err != nil {
+		log.Printf("Error getting contract price for SKU %s: %v", skuID, err)
+		return nil, apiError("contract price unavailable", err).Error()
+	}
+	if price.Rate == nil || len(price.Rate.Tiers) == 0 {
+		return nil, "contract price has no rate tiers"
+	}
+	return price, ""
+}
+
+// buildContractPriceInfo converts a billing account price for display
+func buildContractPriceInfo(billingAccount string, price *pricing.BillingAccountPrice, note string) *ContractPriceInfo {
+	info := &ContractPriceInfo{BillingAccount: billingAccount, Note: note}
+	if price == nil {
+		return info
+	}
+
+	info.PriceReason = priceReasonType(price.PriceReason)
+	info.DiscountPercent = priceReasonDiscount(price.PriceReason)
+	info.Unit = price.Rate.UnitInfo.Unit
+	info.UnitQuantity = price.Rate.UnitInfo.Quantity().Float64()
+	info.Tiers = buildPricingTiers(price.Rate)
+	return info
+}
+
+// calculateContractCost prices usage at the contract price and compares it with the list price cost
+func calculateContractCost(billingAccount string, price *pricing.BillingAccountPrice, note string,
+	usage money.Decimal, period pricing.UsagePeriod, listCost money.Decimal, currencyCode string, mode money.RoundingMode) (*ContractCost, error) {
+	result := &ContractCost{BillingAccount: billingAccount, Note: note}
+	if price == nil {
+		return result, nil
+	}
+
+	calculation, err := pricing.CalculateCostForPeriod(price.Rate, usage, period)
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate contract cost: %w", err)
+	}
+	cost := calculation.Cost
+
+	savings := listCost.Sub(cost)
+	result.PriceReason = priceReasonType(price.PriceReason)
+	result.EstimatedCost = cost.Float64()
+	result.EstimatedCostExact = cost.String()
+	result.EstimatedCostFormatted = money.Format(cost, currencyCode, mode)
+	result.Savings = savings.Float64()
+	if !listCost.IsZero() {
+		result.SavingsPercent = savings.Quo(listCost).Mul(money.FromInt(100)).Float64()
+	}
+	return result, nil
+}
+
+// priceReasonType returns the price reason type, if any
+func priceReasonType(reason *pricing.PriceReason) string {
+	if reason == nil {
+		return ""
+	}
+	return reason.Type
+}
+
+// priceReasonDiscount returns the contractual discount percentage, if any
+func priceReasonDiscount(reason *pricing.PriceReason) float64 {
+	if reason == nil {
+		return 0
+	}
+	for _, discount := range []*pricing.PriceDiscount{reason.FixedDiscount, reason.FloatingDiscount} {
+		if discount == nil {
+			continue
+		}
+		if percent, err := strconv.ParseFloat(discount.DiscountPercent.Value, 64); err == nil {
+			return percent
+		}
+	}
+	return 0
+}
@@ -0,0 +1,65 @@
+package tools
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/firebase/genkit/go/ai"
+	"github.com/firebase/genkit/go/genkit"
+	"github.com/nozomi-koborinai/gcp-cost-mcp-server/internal/pricing"
+)
+
+// DiffCatalogInput is the input for the diff_catalog tool
+type DiffCatalogInput struct {
+	BaselineSnapshot string `json:"baseline_snapshot" jsonschema_description:"Path to the baseline catalog snapshot file (created with the 'snapshot' command)."`
+	CompareSnapshot  string `json:"compare_snapshot,omitempty" jsonschema_description:"Path to a second snapshot to compare against. If omitted, the baseline is compared with the current catalog for the services it contains."`
+	CurrencyCode     string `json:"currency_code,omitempty" jsonschema_description:"ISO-4217 currency code of the prices to compare. Defaults to USD."`
+}
+
+// DiffCatalogOutput is the output of the diff_catalog tool
+type DiffCatalogOutput struct {
+	Summary string                `json:"summary"`
+	Diff    *pricing.SnapshotDiff `json:"diff"`
+}
+
+// NewDiffCatalog creates a tool that reports catalog changes between snapshots, or between a snapshot and the current catalog
+func NewDiffCatalog(g *genkit.Genkit, client pricing.PricingClient) ai.Tool {
+	return genkit.DefineTool(
+		g,
+		"diff_catalog",
+		"Compares a pinned catalog snapshot with another snapshot or with the current catalog. Reports added and removed SKUs and per-tier price changes grouped by service and region. Use this to answer whether prices of the services in use have changed.",
+		func(ctx *ai.ToolContext, input DiffCatalogInput) (*DiffCatalogOutput, error) {
+			log.Printf("Tool 'diff_catalog' called with baseline=%q, compare=%q, currency=%s",
+				input.BaselineSnapshot, input.CompareSnapshot, input.CurrencyCode)
+
+			if input.BaselineSnapshot == "" {
+				return nil, fmt.Errorf("baseline_snapshot is required")
+			}
+
+			baseline, err := pricing.LoadSnapshot(input.BaselineSnapshot)
+			if err != nil {
+				return nil, err
+			}
+
+			var diff *pricing.SnapshotDiff
+			if input.CompareSnapshot != "" {
+				compare, err := pricing.LoadSnapshot(input.CompareSnapshot)
+				if err != nil {
+					return nil, err
+				}
+				diff = pricing.DiffSnapshots(baseline, compare, input.CurrencyCode)
+			} else {
+				diff, err = pricing.DiffLive(ctx.Context, baseline, client, input.CurrencyCode, pricing.DefaultBuildConcurrency)
+				if err != nil {
+					log.Printf("Error fetching current catalog: %v", err)
+					return nil, apiError("failed to fetch current catalog", err)
+				}
+			}
+
+			return &DiffCatalogOutput{
+				Summary: fmt.Sprintf("%d SKUs added, %d removed, %d with price changes across %d services",
+					diff.AddedSKUs, diff.RemovedSKUs, diff.ChangedSKUs, len(diff.Services)),
+				Diff: diff,
+			}, nil
+		})
+}
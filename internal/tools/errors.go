@@ -0,0 +1,54 @@
+package tools
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/nozomi-koborinai/gcp-cost-mcp-server/internal/pricing"
+)
+
+// apiError converts a pricing client error into an actionable MCP error message.
+// The original error is kept in the chain so callers can still use errors.Is.
+func apiError(action string, err error) error {
+	var hint string
+	switch {
+	case errors.Is(err, pricing.ErrUnauthenticated):
+		hint = unauthenticatedHint(err)
+	case errors.Is(err, pricing.ErrPermissionDenied):
+		hint = "The credentials are not allowed to read this pricing data. Check that the account can access the Cloud Billing API (billing-account pricing requires roles/billing.viewer)."
+	case errors.Is(err, pricing.ErrNotFound):
+		hint = "The requested resource does not exist. Verify the ID using list_services or list_skus."
+	case errors.Is(err, pricing.ErrRateLimited):
+		hint = "The Cloud Billing API quota was exhausted. Wait a minute and retry, or make fewer parallel calls."
+		var rateLimitErr *pricing.RateLimitError
+		if errors.As(err, &rateLimitErr) {
+			hint = fmt.Sprintf("The Cloud Billing API quota was exhausted. Wait %s before retrying, or make fewer parallel calls.", rateLimitErr.RetryAfter)
+		}
+	case errors.Is(err, pricing.ErrInvalidArgument):
+		hint = "The request was rejected as invalid. Check the parameters (e.g. SKU/service ID format, ISO-4217 currency code)."
+	}
+
+	if hint == "" {
+		return fmt.Errorf("%s: %w", action, err)
+	}
+	return fmt.Errorf("%s: %w. %s", action, err, hint)
+}
+
+// unauthenticatedHint suggests how to fix the credentials the server is configured with.
+// Errors that do not name their credentials are assumed to come from Application Default Credentials.
+func unauthenticatedHint(err error) string {
+	credentialErr := &pricing.CredentialError{Mode: pricing.CredentialsADC}
+	errors.As(err, &credentialErr)
+
+	switch credentialErr.Mode {
+	case pricing.CredentialsAPIKey:
+		return "The API key was rejected. Check that GCP_COST_API_KEY is a valid, unrestricted key of a project with the Cloud Billing API enabled, and restart the server."
+	case pricing.CredentialsFile:
+		return fmt.Sprintf("The credentials file %s was rejected. Check that GCP_COST_CREDENTIALS_FILE names a valid key that has not been deleted or disabled, and restart the server.", credentialErr.Subject)
+	case pricing.CredentialsImpersonation:
+		return fmt.Sprintf("Could not get credentials for %s. Check that the source credentials are valid and hold roles/iam.serviceAccountTokenCreator on that service account, and restart the server.", credentialErr.Subject)
+	case pricing.CredentialsHTTPClient:
+		return "The credentials of the configured HTTP client were rejected."
+	}
+	return "Credentials are missing or expired. Run 'gcloud auth application-default login' and restart the server."
+}
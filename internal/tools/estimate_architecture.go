@@ -0,0 +1,202 @@
+package tools
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+
+	"github.com/firebase/genkit/go/ai"
+	"github.com/firebase/genkit/go/genkit"
+	"github.com/nozomi-koborinai/gcp-cost-mcp-server/internal/freetier"
+	"github.com/nozomi-koborinai/gcp-cost-mcp-server/internal/money"
+	"github.com/nozomi-koborinai/gcp-cost-mcp-server/internal/pricing"
+)
+
+// architectureConcurrency is the number of line items priced in parallel
+const architectureConcurrency = 8
+
+// ArchitectureLineItem is one billable usage of an architecture estimate
+type ArchitectureLineItem struct {
+	SKUID            string            `json:"sku_id" jsonschema_description:"The SKU ID to price (e.g., '0008-F633-76AA'). Use list_skus to find SKU IDs."`
+	UsageAmount      float64           `json:"usage_amount" jsonschema_description:"The amount of usage in the SKU's unit (e.g., hours, GB, requests)."`
+	ServiceName      string            `json:"service_name" jsonschema_description:"The Google Cloud service name (e.g., 'Cloud Run'). Used for free tier lookup and per-service subtotals."`
+	Region           string            `json:"region,omitempty" jsonschema_description:"The region of this line item (e.g., 'asia-northeast1')."`
+	Description      string            `json:"description,omitempty" jsonschema_description:"What this line item covers (e.g., 'API service vCPU, 2 instances')."`
+	Labels           map[string]string `json:"labels,omitempty" jsonschema_description:"Free-form labels to identify the line item (e.g., {'component': 'api', 'env': 'prod'})."`
+	Project          string            `json:"project,omitempty" jsonschema_description:"Project the usage belongs to. Project-scoped free tiers are shared only by line items of the same project."`
+	ConsumptionModel string            `json:"consumption_model,omitempty" jsonschema_description:"Consumption model to price with. Defaults to on-demand (DEFAULT)."`
+	// Unit of usage_amount, converted to the SKU's unit
+	UsageUnit string `json:"usage_unit,omitempty" jsonschema_description:"Unit of usage_amount when it differs from the SKU's unit (e.g., 'GiBy', 'TiB', 'h'). Defaults to the SKU's unit."`
+}
+
+// EstimateArchitectureInput is the input for the estimate_architecture tool
+type EstimateArchitectureInput struct {
+	LineItems       []ArchitectureLineItem `json:"line_items" jsonschema_description:"The line items to price, one per SKU usage. REQUIRED."`
+	CurrencyCode    string                 `json:"currency_code,omitempty" jsonschema_description:"ISO-4217 currency code (e.g., 'USD', 'JPY', 'EUR'). Defaults to USD if not specified."`
+	UsagePeriod     string                 `json:"usage_period,omitempty" jsonschema_description:"Period the usage amounts accrue over: 'day', 'week', 'month' (730 hours) or 'year'. Free tier allowances are scaled to this period. Defaults to 'month'."`
+	UsagePeriodDays float64                `json:"usage_period_days,omitempty" jsonschema_description:"Number of days the usage amounts accrue over, as an alternative to usage_period."`
+	RoundingMode    string                 `json:"rounding_mode,omitempty" jsonschema_description:"Rounding mode for formatted amounts: 'half_even' (default), 'half_up', 'down' or 'up'."`
+}
+
+// LineItemEstimate is the estimate of one line item
+type LineItemEstimate struct {
+	Index    int               `json:"index"`
+	Labels   map[string]string `json:"labels,omitempty"`
+	Estimate CostBreakdown     `json:"estimate"`
+}
+
+// ServiceSubtotal is the cost of all line items of one service
+type ServiceSubtotal struct {
+	ServiceName       string  `json:"service_name"`
+	LineItems         int     `json:"line_items"`
+	Subtotal          float64 `json:"subtotal"`
+	SubtotalExact     string  `json:"subtotal_exact"`
+	SubtotalFormatted string  `json:"subtotal_formatted"`
+}
+
+// EstimateArchitectureOutput is the output of the estimate_architecture tool
+type EstimateArchitectureOutput struct {
+	CurrencyCode        string             `json:"currency_code"`
+	LineItems           []LineItemEstimate `json:"line_items"`
+	Services            []ServiceSubtotal  `json:"services"`
+	GrandTotal          float64            `json:"grand_total"`
+	GrandTotalExact     string             `json:"grand_total_exact"`
+	GrandTotalFormatted string             `json:"grand_total_formatted"`
+	// FreeTierAllocations reports how each shared free tier allowance was allocated to the line items
+	FreeTierAllocations []freetier.AllowanceReport `json:"free_tier_allocations,omitempty"`
+}
+
+// NewEstimateArchitecture creates a tool that estimates the cost of several line items at once.
+// Free tiers are shared across the line items through a ledger instead of being deducted for each.
+func NewEstimateArchitecture(g *genkit.Genkit, client pricing.PricingClient, freeTierService *freetier.Service, contract *pricing.ContractPricer) ai.Tool {
+	return genkit.DefineTool(
+		g,
+		"estimate_architecture",
+		`Estimates the total cost of an architecture made of several SKU usages (line items) in one call.
+Returns the estimate of every line item, subtotals per service and the grand total, all calculated exactly by the server.
+Prefer this over calling estimate_cost repeatedly and summing the results yourself.
+
+=== FREE TIER HANDLING ===
+Free tiers are applied once across all line items: e.g. two Cloud Run services share one Cloud Run
+free tier, which is deducted from the line items in the order they are given. Project-scoped free
+tiers are shared by line items with the same project. free_tier_allocations shows how each
+allowance was allocated.
+
+=== WORKFLOW ===
+1. Call get_estimation_guide for each service and gather the parameters from the user
+2. Use list_services and list_skus to find the SKU ID of each usage
+3. Call this tool with one line item per SKU usage, including service_name and description`,
+		func(ctx *ai.ToolContext, input EstimateArchitectureInput) (*EstimateArchitectureOutput, error) {
+			log.Printf("Tool 'estimate_architecture' called with %d line items", len(input.LineItems))
+
+			if len(input.LineItems) == 0 {
+				return nil, fmt.Errorf("line_items is required")
+			}
+
+			currencyCode := input.CurrencyCode
+			if currencyCode == "" {
+				currencyCode = "USD"
+			}
+			roundingMode, err := money.ParseRoundingMode(input.RoundingMode)
+			if err != nil {
+				return nil, err
+			}
+
+			// Resolve prices and free tiers of all line items concurrently
+			e := &estimator{client: client, freeTierService: freeTierService, contract: contract}
+			items := make([]*lineItem, len(input.LineItems))
+			errs := make([]error, len(input.LineItems))
+			sem := make(chan struct{}, architectureConcurrency)
+			var wg sync.WaitGroup
+			for i, li := range input.LineItems {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					sem <- struct{}{}
+					defer func() { <-sem }()
+
+					items[i], errs[i] = e.resolve(ctx.Context, EstimateCostInput{
+						SKUID:            li.SKUID,
+						UsageAmount:      li.UsageAmount,
+						CurrencyCode:     currencyCode,
+						ServiceName:      li.ServiceName,
+						Region:           li.Region,
+						Description:      li.Description,
+						ConsumptionModel: li.ConsumptionModel,
+						RoundingMode:     input.RoundingMode,
+						UsagePeriod:      input.UsagePeriod,
+						UsagePeriodDays:  input.UsagePeriodDays,
+						Project:          li.Project,
+						UsageUnit:        li.UsageUnit,
+					})
+				}()
+			}
+			wg.Wait()
+			for i, err := range errs {
+				if err != nil {
+					return nil, fmt.Errorf("line item %d (%s): %w", i, input.LineItems[i].SKUID, err)
+				}
+			}
+
+			// Calculate in input order so that shared free tiers are allocated deterministically
+			output := &EstimateArchitectureOutput{
+				CurrencyCode: currencyCode,
+				LineItems:    make([]LineItemEstimate, 0, len(items)),
+			}
+			ledger := freetier.NewLedger()
+			// Subtotals are keyed by the service name the free tier lookup resolved, like the free tier
+			// ledger, so aliases (e.g. "GKE" and "Kubernetes Engine") share one subtotal. Services it
+			// did not resolve are keyed by normalized name and reported under the first name given.
+			subtotals := make(map[string]money.Decimal)
+			counts := make(map[string]int)
+			names := make(map[string]string)
+			var serviceOrder []string
+			grandTotal := money.Zero
+
+			for i, item := range items {
+				item.id = fmt.Sprintf("#%d %s", i, item.name())
+				result, cost, err := e.calculate(item, ledger)
+				if err != nil {
+					return nil, fmt.Errorf("line item %d (%s): %w", i, item.input.SKUID, err)
+				}
+
+				serviceName := item.serviceName
+				if serviceName == "" {
+					serviceName = strings.TrimSpace(item.input.ServiceName)
+				}
+				service := strings.ToLower(strings.Join(strings.Fields(serviceName), " "))
+				if _, ok := subtotals[service]; !ok {
+					serviceOrder = append(serviceOrder, service)
+					subtotals[service] = money.Zero
+					names[service] = serviceName
+				}
+				subtotals[service] = subtotals[service].Add(cost)
+				counts[service]++
+				grandTotal = grandTotal.Add(cost)
+
+				output.LineItems = append(output.LineItems, LineItemEstimate{
+					Index:    i,
+					Labels:   input.LineItems[i].Labels,
+					Estimate: result.Estimate,
+				})
+			}
+
+			for _, service := range serviceOrder {
+				subtotal := subtotals[service]
+				output.Services = append(output.Services, ServiceSubtotal{
+					ServiceName:       names[service],
+					LineItems:         counts[service],
+					Subtotal:          subtotal.Float64(),
+					SubtotalExact:     subtotal.String(),
+					SubtotalFormatted: money.Format(subtotal, currencyCode, roundingMode),
+				})
+			}
+			output.GrandTotal = grandTotal.Float64()
+			output.GrandTotalExact = grandTotal.String()
+			output.GrandTotalFormatted = money.Format(grandTotal, currencyCode, roundingMode)
+			output.FreeTierAllocations = ledger.Report()
+
+			return output, nil
+		})
+}
@@ -3,10 +3,14 @@ package tools
 import (
 	"fmt"
 	"log"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/firebase/genkit/go/ai"
 	"github.com/firebase/genkit/go/genkit"
 	"github.com/nozomi-koborinai/gcp-cost-mcp-server/internal/freetier"
+	"github.com/nozomi-koborinai/gcp-cost-mcp-server/internal/money"
 	"github.com/nozomi-koborinai/gcp-cost-mcp-server/internal/pricing"
 )
 
@@ -19,6 +23,19 @@ type EstimateCostInput struct {
 	ServiceName string `json:"service_name,omitempty" jsonschema_description:"The Google Cloud service name (e.g., 'Cloud Run', 'Compute Engine'). Helps track what this estimate is for."`
 	Region      string `json:"region,omitempty" jsonschema_description:"The region for this estimate (e.g., 'asia-northeast1'). Important for accurate pricing."`
 	Description string `json:"description,omitempty" jsonschema_description:"Description of what this estimate covers (e.g., '2 vCPU Cloud Run instance, 730 hours/month')."`
+	// Consumption model selection
+	ConsumptionModel string `json:"consumption_model,omitempty" jsonschema_description:"Consumption model to price with (e.g., 'DEFAULT' for on-demand, or a commitment model as listed by get_sku_price). Defaults to on-demand (DEFAULT) when available, otherwise the first model."`
+	CompareModels    bool   `json:"compare_consumption_models,omitempty" jsonschema_description:"If true, also calculate the cost under every consumption model of the SKU and report them side by side, with savings relative to on-demand. Each model is priced after its own free tier deduction, since free tiers may not be available with committed use discounts."`
+	// Rounding of the formatted cost
+	RoundingMode string `json:"rounding_mode,omitempty" jsonschema_description:"Rounding mode for estimated_cost_formatted: 'half_even' (default, banker's rounding), 'half_up', 'down' or 'up'. Calculations are always exact; only the formatted amount is rounded."`
+	// Period the usage accrues over, used to apply aggregated tiers and scale free tier allowances
+	UsagePeriod     string  `json:"usage_period,omitempty" jsonschema_description:"Period usage_amount accrues over: 'day', 'week', 'month' (730 hours) or 'year'. Daily free tiers are granted for every day of the period and tiers that restart daily are applied per day; a period shorter than a tier interval gets a pro-rated share of its tiers. Defaults to 'month'. Use usage_period_days for other lengths."`
+	UsagePeriodDays float64 `json:"usage_period_days,omitempty" jsonschema_description:"Number of days usage_amount accrues over, as an alternative to usage_period."`
+	// Free tier sharing
+	Project   string `json:"project,omitempty" jsonschema_description:"Project the usage belongs to. Project-scoped free tiers are shared only by usage of the same project."`
+	SessionID string `json:"session_id,omitempty" jsonschema_description:"Estimate session ID. Calls with the same session_id share free tier allowances, so an account-wide free tier is only deducted once in total across them. Omit to apply the full free tier to this call."`
+	// Unit of usage_amount, converted to the SKU's unit
+	UsageUnit string `json:"usage_unit,omitempty" jsonschema_description:"Unit of usage_amount when it differs from the SKU's unit, as a Billing unit code or common name (e.g., 'GiBy', 'TiB', 'h', 'seconds', 'GiBy.mo'). It is converted to the SKU's unit; the units must measure the same thing. Defaults to the SKU's unit."`
 }
 
 // CostBreakdown represents the cost calculation breakdown
@@ -29,28 +46,116 @@ type CostBreakdown struct {
 	EstimatedCost float64 `json:"estimated_cost"`
 	CurrencyCode  string  `json:"currency_code"`
 	PricePerUnit  float64 `json:"price_per_unit"`
+	UnitQuantity  float64 `json:"unit_quantity"`
 	TieredPricing bool    `json:"tiered_pricing"`
 	NumberOfTiers int     `json:"number_of_tiers"`
 	CostBreakdown string  `json:"cost_breakdown,omitempty"`
+	// Consumption model the cost was calculated with
+	ConsumptionModel            string `json:"consumption_model"`
+	ConsumptionModelDescription string `json:"consumption_model_description,omitempty"`
+	// Exact cost, and the cost rounded to the currency's minor unit (e.g. "63.07 USD")
+	EstimatedCostExact     string `json:"estimated_cost_exact"`
+	EstimatedCostFormatted string `json:"estimated_cost_formatted"`
+	RoundingMode           string `json:"rounding_mode"`
+	// Contract is the cost at the configured billing account's contract price, if any
+	Contract *ContractCost `json:"contract,omitempty"`
+	// TierBreakdown is the usage and subtotal of each pricing tier, summing to estimated_cost
+	TierBreakdown []TierCostLine `json:"tier_breakdown"`
+	// UsagePeriod is the period the estimate covers
+	UsagePeriod     string  `json:"usage_period"`
+	UsagePeriodDays float64 `json:"usage_period_days"`
+	// UsageDistribution describes how usage was spread across the rate's aggregation intervals
+	UsageDistribution *UsageDistribution `json:"usage_distribution,omitempty"`
 	// Additional context in output
 	ServiceName string `json:"service_name,omitempty"`
 	Region      string `json:"region,omitempty"`
 	Description string `json:"description,omitempty"`
+	// UsageConversion describes how usage_amount was converted from usage_unit to the SKU's unit
+	UsageConversion string `json:"usage_conversion,omitempty"`
+	// CatalogFreeTier is a free allowance encoded in the catalog as zero-priced first tiers
+	CatalogFreeTier *CatalogFreeTier `json:"catalog_free_tier,omitempty"`
 	// Free tier information (Issue #8)
 	TotalUsage        float64 `json:"total_usage"`
 	FreeTierApplied   float64 `json:"free_tier_applied"`
 	BillableUsage     float64 `json:"billable_usage"`
 	FreeTierNote      string  `json:"free_tier_note,omitempty"`
 	FreeTierSourceURL string  `json:"free_tier_source_url,omitempty"`
+	// FreeTierSource is where the free tier came from: "dataset" (curated), "free_program" (the
+	// Free Program page) or "scrape" (documentation)
+	FreeTierSource string `json:"free_tier_source,omitempty"`
+	// FreeTierMatchedBy is how the free tier was bound to the SKU: "sku_id", "predicate" or "unit"
+	FreeTierMatchedBy string `json:"free_tier_matched_by,omitempty"`
+	// FreeTierWarnings explain why an apparently applicable free tier was not deducted
+	FreeTierWarnings []string `json:"free_tier_warnings,omitempty"`
+	// FreeTierConditions are the conditions and caveats of the free tier (e.g. region
+	// restrictions, "Not available with committed use discounts")
+	FreeTierConditions []string `json:"free_tier_conditions,omitempty"`
+}
+
+// CatalogFreeTier is a free allowance the catalog encodes as zero-priced first tiers.
+// It is applied by the tier calculation itself rather than deducted from usage.
+type CatalogFreeTier struct {
+	// AmountPerInterval is the allowance per aggregation interval; Amount is the allowance over the usage period
+	AmountPerInterval float64 `json:"amount_per_interval"`
+	Interval          string  `json:"interval,omitempty"`
+	Amount            float64 `json:"amount"`
+	// Applied is the usage covered by the allowance
+	Applied float64 `json:"applied"`
+	Note    string  `json:"note,omitempty"`
+}
+
+// TierCostLine is the usage and cost of one pricing tier.
+// With aggregated tiers, the bounds apply per interval and usage and subtotal are summed over the period.
+type TierCostLine struct {
+	StartAmount float64 `json:"start_amount"`
+	// EndAmount is omitted for the open-ended last tier
+	EndAmount         *float64 `json:"end_amount,omitempty"`
+	Usage             float64  `json:"usage"`
+	PricePerUnit      float64  `json:"price_per_unit"`
+	PricePerUnitExact string   `json:"price_per_unit_exact"`
+	Subtotal          float64  `json:"subtotal"`
+	SubtotalExact     string   `json:"subtotal_exact"`
+}
+
+// UsageDistribution describes how usage was split across aggregation intervals before applying tiers
+type UsageDistribution struct {
+	AggregationLevel    string  `json:"aggregation_level,omitempty"`
+	AggregationInterval string  `json:"aggregation_interval,omitempty"`
+	UsagePeriodDays     float64 `json:"usage_period_days"`
+	Intervals           float64 `json:"intervals"`
+	UsagePerInterval    float64 `json:"usage_per_interval"`
+	CostPerInterval     float64 `json:"cost_per_interval"`
+	Note                string  `json:"note,omitempty"`
+}
+
+// ModelCost is the cost of the same usage under one consumption model
+type ModelCost struct {
+	ConsumptionModel       string  `json:"consumption_model"`
+	Description            string  `json:"description,omitempty"`
+	EstimatedCost          float64 `json:"estimated_cost"`
+	EstimatedCostExact     string  `json:"estimated_cost_exact"`
+	EstimatedCostFormatted string  `json:"estimated_cost_formatted"`
+	// FreeTierApplied and BillableUsage are the usage covered by the free tier under this model and
+	// the usage left to price; a free tier may not be available with every model
+	FreeTierApplied float64 `json:"free_tier_applied"`
+	BillableUsage   float64 `json:"billable_usage"`
+	// Savings relative to the on-demand (DEFAULT) model; omitted when there is no on-demand model
+	Savings        float64 `json:"savings,omitempty"`
+	SavingsPercent float64 `json:"savings_percent,omitempty"`
 }
 
 // EstimateCostOutput is the output of the estimate_cost tool
 type EstimateCostOutput struct {
-	Estimate CostBreakdown `json:"estimate"`
+	Estimate        CostBreakdown `json:"estimate"`
+	ModelComparison []ModelCost   `json:"consumption_model_comparison,omitempty"`
+	// FreeTierAllocations reports how the session's free tier allowances were allocated so far
+	FreeTierAllocations []freetier.AllowanceReport `json:"free_tier_allocations,omitempty"`
 }
 
-// NewEstimateCost creates a tool that estimates the cost based on usage
-func NewEstimateCost(g *genkit.Genkit, client *pricing.Client, freeTierService *freetier.Service) ai.Tool {
+// NewEstimateCost creates a tool that estimates the cost based on usage.
+// When contract is non-nil, the cost at its billing account's contract price is reported alongside the list price cost.
+func NewEstimateCost(g *genkit.Genkit, client pricing.PricingClient, freeTierService *freetier.Service, contract *pricing.ContractPricer) ai.Tool {
+	sessions := newLedgerSessions()
 	return genkit.DefineTool(
 		g,
 		"estimate_cost",
@@ -62,6 +167,7 @@ This tool automatically:
 - Retrieves free tier information from GCP documentation
 - Deducts free tier allowance from usage before calculating cost
 - Reports free tier applied, billable usage, and source URL in the output
+- Recognizes free allowances the catalog encodes as zero-priced first tiers (catalog_free_tier) and does not deduct a documented free tier for the same usage again
 
 === SINGLE SERVICE WORKFLOW ===
 1. FIRST call get_estimation_guide to understand what information is needed
@@ -71,156 +177,279 @@ This tool automatically:
 5. Call this tool with the SKU ID and usage amount
 
 === MULTI-SERVICE / ARCHITECTURE DIAGRAM WORKFLOW ===
-When estimating costs for multiple services (e.g., from an architecture diagram):
-1. Call get_estimation_guide for EACH service identified
-2. Gather parameters from user (shared params like region first, then service-specific)
-3. For EACH service: list_services → list_skus → estimate_cost
-4. Call this tool multiple times (once per service/SKU)
-5. SUM all estimates and present a breakdown table like:
-
-   | Service       | Description              | Free Tier | Monthly Cost |
-   |---------------|--------------------------|-----------|--------------|
-   | Cloud Run     | 2x instances, 1vCPU, 2GB | Applied   | $152.42      |
-   | Cloud SQL     | PostgreSQL, db-custom-2  | N/A       | $89.50       |
-   | Cloud Storage | 100GB Standard           | Applied   | $0.00        |
-   | **Total**     |                          |           | **$241.92**  |
+When estimating costs for multiple services (e.g., from an architecture diagram), use
+estimate_architecture instead: it prices all line items in one call, shares account-scoped
+free tiers across them and returns per-service subtotals and an exact grand total.
 
 === IMPORTANT ===
 - DO NOT call this tool until you have gathered sufficient information from the user
 - ALWAYS include service_name, region, and description parameters to track what each estimate covers
-- For multi-service estimates, track each call and calculate the total at the end
-- Note: Free tiers are typically per billing account, not per project`,
+- Note: Free tiers are typically per billing account, not per project. When estimating several workloads with separate calls, pass the same session_id so the free tier is not deducted for each of them
+- estimated_cost is at list price; when a billing account is configured, contract shows the cost at the negotiated price
+- Prices use the on-demand consumption model unless consumption_model is set; set compare_consumption_models to show commitment savings in one call`,
 		func(ctx *ai.ToolContext, input EstimateCostInput) (*EstimateCostOutput, error) {
 			log.Printf("Tool 'estimate_cost' called for sku_id: %s, usage: %f", input.SKUID, input.UsageAmount)
 
-			if input.SKUID == "" {
-				return nil, fmt.Errorf("sku_id is required")
-			}
-
-			if input.UsageAmount < 0 {
-				return nil, fmt.Errorf("usage_amount must be non-negative")
+			e := &estimator{client: client, freeTierService: freeTierService, contract: contract}
+			item, err := e.resolve(ctx.Context, input)
+			if err != nil {
+				return nil, err
 			}
 
-			// Default to USD if not specified
-			currencyCode := input.CurrencyCode
-			if currencyCode == "" {
-				currencyCode = "USD"
+			ledger := freetier.NewLedger()
+			if input.SessionID != "" {
+				ledger = sessions.get(input.SessionID)
 			}
-
-			// Get the price for this SKU
-			priceResp, err := client.GetSKUPrice(ctx.Context, input.SKUID, currencyCode)
+			output, _, err := e.calculate(item, ledger)
 			if err != nil {
-				log.Printf("Error getting SKU price: %v", err)
-				return nil, fmt.Errorf("failed to get SKU price: %w", err)
-			}
-
-			// Extract rate from the first SKUPrice entry
-			if len(priceResp.SKUPrices) == 0 || priceResp.SKUPrices[0].Rate == nil {
-				return nil, fmt.Errorf("no pricing data available for SKU %s", input.SKUID)
+				return nil, err
 			}
-			rate := priceResp.SKUPrices[0].Rate
-
-			// Track original usage for reporting
-			totalUsage := input.UsageAmount
-			billableUsage := input.UsageAmount
-			var freeTierApplied float64
-			var freeTierNote string
-			var freeTierSourceURL string
-
-			// Try to get and apply free tier information (Issue #8)
-			if freeTierService != nil && input.ServiceName != "" {
-				freeTierInfo, err := freeTierService.GetFreeTier(ctx.Context, input.ServiceName)
-				if err == nil && freeTierInfo != nil {
-					// Find matching free tier item for this SKU's usage unit
-					matchingItem := freetier.FindMatchingFreeTierItem(freeTierInfo, rate.UnitInfo.Unit)
-					if matchingItem != nil {
-						// Calculate free tier deduction
-						freeTierApplied = min(totalUsage, matchingItem.Amount)
-						billableUsage = max(0, totalUsage-matchingItem.Amount)
-
-						freeTierNote = fmt.Sprintf(
-							"Free tier applied: %.0f %s (%s, %s)",
-							matchingItem.Amount,
-							matchingItem.Resource,
-							freeTierInfo.Scope,
-							freeTierInfo.Period,
-						)
-						freeTierSourceURL = freeTierInfo.SourceURL
-
-						log.Printf("Free tier applied for %s: %.0f %s deducted, billable: %.0f",
-							input.ServiceName, freeTierApplied, matchingItem.Resource, billableUsage)
-					}
-				}
+			if input.SessionID != "" {
+				output.FreeTierAllocations = ledger.Report()
 			}
+			return output, nil
+		})
+}
 
-			// Calculate the cost based on billable usage (after free tier deduction)
-			estimatedCost, err := client.CalculateCost(rate, billableUsage)
-			if err != nil {
-				log.Printf("Error calculating cost: %v", err)
-				return nil, fmt.Errorf("failed to calculate cost: %w", err)
-			}
+// buildTierBreakdown converts per-tier costs for display
+func buildTierBreakdown(tiers []pricing.TierCost) []TierCostLine {
+	lines := make([]TierCostLine, 0, len(tiers))
+	for _, tier := range tiers {
+		line := TierCostLine{
+			StartAmount:       tier.StartAmount.Float64(),
+			Usage:             tier.Usage.Float64(),
+			PricePerUnit:      tier.PricePerUnit.Float64(),
+			PricePerUnitExact: tier.PricePerUnit.String(),
+			Subtotal:          tier.Cost.Float64(),
+			SubtotalExact:     tier.Cost.String(),
+		}
+		if tier.HasEnd {
+			end := tier.EndAmount.Float64()
+			line.EndAmount = &end
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
 
-			// Prepare output
-			estimate := CostBreakdown{
-				SKUID:         input.SKUID,
-				UsageAmount:   totalUsage,
-				EstimatedCost: estimatedCost,
-				CurrencyCode:  currencyCode,
-				Unit:          rate.UnitInfo.Unit,
-				NumberOfTiers: len(rate.Tiers),
-				TieredPricing: len(rate.Tiers) > 1,
-				// Include context from input
-				ServiceName: input.ServiceName,
-				Region:      input.Region,
-				Description: input.Description,
-				// Free tier information
-				TotalUsage:        totalUsage,
-				FreeTierApplied:   freeTierApplied,
-				BillableUsage:     billableUsage,
-				FreeTierNote:      freeTierNote,
-				FreeTierSourceURL: freeTierSourceURL,
-			}
+// buildCatalogFreeTier reports the catalog-native free allowance and how much of it the usage consumed
+func buildCatalogFreeTier(calculation *pricing.CostCalculation, amountPerInterval money.Decimal, unit string) *CatalogFreeTier {
+	// The allowance is the leading zero-priced tiers, which the calculation prices at zero
+	applied := money.Zero
+	for _, tier := range calculation.Tiers {
+		if !tier.PricePerUnit.IsZero() {
+			break
+		}
+		applied = applied.Add(tier.Usage)
+	}
 
-			// Calculate average price per unit for display (based on billable usage)
-			if billableUsage > 0 {
-				estimate.PricePerUnit = estimatedCost / billableUsage
-			} else if len(rate.Tiers) > 0 {
-				// Use first tier price if no billable usage
-				tier := rate.Tiers[0]
-				estimate.PricePerUnit = float64(tier.ListPrice.Nanos) / 1e9
-			}
+	amount := amountPerInterval.Mul(calculation.Intervals)
+	note := fmt.Sprintf("Catalog free tier: first %s %s", amountPerInterval, unit)
+	if calculation.AggregationInterval != "" {
+		note += " per " + strings.ToLower(calculation.AggregationInterval) + " interval"
+	}
+	note += fmt.Sprintf(" priced at zero (%s %s used)", applied, unit)
 
-			// Create cost breakdown description
-			var breakdownDesc string
-			if freeTierApplied > 0 {
-				breakdownDesc = fmt.Sprintf(
-					"Total usage: %.2f %s. Free tier deducted: %.2f %s. Billable usage: %.2f %s. ",
-					totalUsage, estimate.Unit,
-					freeTierApplied, estimate.Unit,
-					billableUsage, estimate.Unit,
-				)
-			}
+	return &CatalogFreeTier{
+		AmountPerInterval: amountPerInterval.Float64(),
+		Interval:          calculation.AggregationInterval,
+		Amount:            amount.Float64(),
+		Applied:           applied.Float64(),
+		Note:              note,
+	}
+}
 
-			if estimate.TieredPricing {
-				breakdownDesc += fmt.Sprintf(
-					"Calculated using %d pricing tiers. Estimated cost: %.6f %s",
-					estimate.NumberOfTiers,
-					estimatedCost,
-					currencyCode,
-				)
-			} else {
-				breakdownDesc += fmt.Sprintf(
-					"Flat rate: %.6f %s per unit = %.6f %s",
-					estimate.PricePerUnit,
-					currencyCode,
-					estimatedCost,
-					currencyCode,
-				)
+// buildUsageDistribution reports how usage was split across aggregation intervals.
+// It returns nil for rates without aggregation info.
+func buildUsageDistribution(calculation *pricing.CostCalculation, period pricing.UsagePeriod, unit string) *UsageDistribution {
+	if calculation.AggregationLevel == "" && calculation.AggregationInterval == "" {
+		return nil
+	}
+
+	distribution := &UsageDistribution{
+		AggregationLevel:    calculation.AggregationLevel,
+		AggregationInterval: calculation.AggregationInterval,
+		UsagePeriodDays:     period.Days.Float64(),
+		Intervals:           calculation.Intervals.Float64(),
+		UsagePerInterval:    calculation.UsagePerInterval.Float64(),
+		CostPerInterval:     calculation.CostPerInterval.Float64(),
+	}
+
+	var notes []string
+	switch calculation.Intervals.Cmp(money.FromInt(1)) {
+	case 1:
+		notes = append(notes, fmt.Sprintf("Usage was split evenly into %s %s intervals of %s %s; tiers restart in each interval",
+			calculation.Intervals.StringFixed(2, money.HalfEven), strings.ToLower(calculation.AggregationInterval),
+			calculation.UsagePerInterval.StringFixed(6, money.HalfEven), unit))
+	case -1:
+		notes = append(notes, fmt.Sprintf("The usage period covers %s of a %s interval, so tier bounds (including free tiers) were pro-rated to that share",
+			calculation.Intervals.StringFixed(4, money.HalfEven), strings.ToLower(calculation.AggregationInterval)))
+	}
+	switch calculation.AggregationLevel {
+	case pricing.AggregationLevelProject:
+		notes = append(notes, "Tiers apply per project; usage is assumed to belong to a single project")
+	case pricing.AggregationLevelAccount:
+		notes = append(notes, "Tiers apply to the combined usage of the billing account")
+	}
+	distribution.Note = strings.Join(notes, ". ")
+	return distribution
+}
+
+// usagePeriods are the named usage periods accepted by estimate tools
+var usagePeriods = map[string]pricing.UsagePeriod{
+	"day":   pricing.UsageDays(money.FromInt(1)),
+	"week":  pricing.UsageDays(money.FromInt(7)),
+	"month": pricing.UsageMonths(1),
+	"year":  pricing.UsageMonths(12),
+}
+
+// parseUsagePeriod returns the usage period given either by name or by number of days, and
+// its display name. With neither given, the period is one month.
+func parseUsagePeriod(name string, days float64) (pricing.UsagePeriod, string, error) {
+	if days < 0 {
+		return pricing.UsagePeriod{}, "", fmt.Errorf("usage_period_days must be non-negative")
+	}
+	if name != "" && days > 0 {
+		return pricing.UsagePeriod{}, "", fmt.Errorf("set either usage_period or usage_period_days, not both")
+	}
+
+	if days > 0 {
+		return pricing.UsageDays(money.FromFloat(days)), fmt.Sprintf("%g days", days), nil
+	}
+	if name == "" {
+		name = "month"
+	}
+	period, ok := usagePeriods[strings.ToLower(name)]
+	if !ok {
+		return pricing.UsagePeriod{}, "", fmt.Errorf("invalid usage_period %q: must be 'day', 'week', 'month' or 'year'", name)
+	}
+	return period, strings.ToLower(name), nil
+}
+
+// sessionTTL is how long an unused estimate session keeps its free tier ledger
+const sessionTTL = 24 * time.Hour
+
+// ledgerSessions keeps the free tier ledgers of estimate sessions
+type ledgerSessions struct {
+	ledgers map[string]*sessionLedger
+	mutex   sync.Mutex
+}
+
+// sessionLedger is the ledger of one estimate session
+type sessionLedger struct {
+	ledger   *freetier.Ledger
+	lastUsed time.Time
+}
+
+func newLedgerSessions() *ledgerSessions {
+	return &ledgerSessions{ledgers: make(map[string]*sessionLedger)}
+}
+
+// get returns the ledger of a session, creating it if needed. Expired sessions are discarded.
+func (s *ledgerSessions) get(id string) *freetier.Ledger {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := time.Now()
+	for key, session := range s.ledgers {
+		if now.Sub(session.lastUsed) > sessionTTL {
+			delete(s.ledgers, key)
+		}
+	}
+
+	session, ok := s.ledgers[id]
+	if !ok {
+		session = &sessionLedger{ledger: freetier.NewLedger()}
+		s.ledgers[id] = session
+	}
+	session.lastUsed = now
+	return session.ledger
+}
+
+// defaultConsumptionModel is the on-demand consumption model
+const defaultConsumptionModel = "DEFAULT"
+
+// consumptionModelName returns the consumption model of a SKU price, treating an empty model as on-demand
+func consumptionModelName(p *pricing.SKUPrice) string {
+	if p.ConsumptionModel == "" {
+		return defaultConsumptionModel
+	}
+	return p.ConsumptionModel
+}
+
+// selectConsumptionModel picks the SKU price for the requested consumption model (case-insensitive).
+// With no model requested, on-demand is preferred and otherwise the first priced model is used.
+func selectConsumptionModel(prices []pricing.SKUPrice, model string) (*pricing.SKUPrice, error) {
+	var available []string
+	for i := range prices {
+		if prices[i].Rate == nil {
+			continue
+		}
+		name := consumptionModelName(&prices[i])
+		if strings.EqualFold(name, model) || (model == "" && name == defaultConsumptionModel) {
+			return &prices[i], nil
+		}
+		available = append(available, name)
+	}
+
+	if len(available) == 0 {
+		return nil, fmt.Errorf("no pricing data available")
+	}
+	if model == "" {
+		for i := range prices {
+			if prices[i].Rate != nil {
+				return &prices[i], nil
 			}
-			estimate.CostBreakdown = breakdownDesc
+		}
+	}
+	return nil, fmt.Errorf("consumption model %q not available (available: %s)", model, strings.Join(available, ", "))
+}
 
-			return &EstimateCostOutput{
-				Estimate: estimate,
-			}, nil
+// compareConsumptionModels calculates the cost of usage under every consumption model. freeUsage
+// returns the part of usage a model's free tier covers; only the rest is priced.
+func compareConsumptionModels(prices []pricing.SKUPrice, usage money.Decimal, freeUsage func(*pricing.SKUPrice) money.Decimal, period pricing.UsagePeriod, currencyCode string, mode money.RoundingMode) ([]ModelCost, error) {
+	var (
+		costs       []ModelCost
+		exact       []money.Decimal
+		onDemand    money.Decimal
+		hasOnDemand bool
+	)
+	for i := range prices {
+		if prices[i].Rate == nil {
+			continue
+		}
+		free := freeUsage(&prices[i])
+		billable := usage.Sub(free)
+		calculation, err := pricing.CalculateCostForPeriod(prices[i].Rate, billable, period)
+		if err != nil {
+			return nil, err
+		}
+		cost := calculation.Cost
+
+		name := consumptionModelName(&prices[i])
+		if name == defaultConsumptionModel {
+			onDemand, hasOnDemand = cost, true
+		}
+		costs = append(costs, ModelCost{
+			ConsumptionModel:       name,
+			Description:            prices[i].ConsumptionModelDescription,
+			EstimatedCost:          cost.Float64(),
+			EstimatedCostExact:     cost.String(),
+			EstimatedCostFormatted: money.Format(cost, currencyCode, mode),
+			FreeTierApplied:        free.Float64(),
+			BillableUsage:          billable.Float64(),
 		})
+		exact = append(exact, cost)
+	}
+
+	if hasOnDemand {
+		for i := range costs {
+			savings := onDemand.Sub(exact[i])
+			costs[i].Savings = savings.Float64()
+			if !onDemand.IsZero() {
+				costs[i].SavingsPercent = savings.Quo(onDemand).Mul(money.FromInt(100)).Float64()
+			}
+		}
+	}
+	return costs, nil
 }
@@ -0,0 +1,568 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/nozomi-koborinai/gcp-cost-mcp-server/internal/freetier"
+	"github.com/nozomi-koborinai/gcp-cost-mcp-server/internal/money"
+	"github.com/nozomi-koborinai/gcp-cost-mcp-server/internal/pricing"
+	"github.com/nozomi-koborinai/gcp-cost-mcp-server/internal/units"
+)
+
+// estimator prices the usage of a single SKU. It is shared by estimate_cost and estimate_architecture.
+// Estimates are made in two steps: resolve fetches prices and free tier information, and calculate
+// does the cost math, so that several line items can be resolved concurrently and then share
+// free tier allowances deterministically.
+type estimator struct {
+	client          pricing.PricingClient
+	freeTierService *freetier.Service
+	contract        *pricing.ContractPricer
+}
+
+// lineItem is an estimate request with its prices and free tier resolved
+type lineItem struct {
+	// id names the line item in free tier allocation reports; it defaults to the description or SKU ID
+	id           string
+	input        EstimateCostInput
+	period       pricing.UsagePeriod
+	periodName   string
+	currencyCode string
+	roundingMode money.RoundingMode
+
+	// usage is the usage amount in the SKU's unit, converted from input.UsageUnit if given
+	usage           money.Decimal
+	usageConversion string
+
+	priceResp *pricing.GetPriceResponse
+	skuPrice  *pricing.SKUPrice
+
+	// Free allowance encoded in the catalog as zero-priced first tiers
+	catalogFreeAmount  money.Decimal
+	hasCatalogFreeTier bool
+	// serviceName is the canonical name of the input's service when the free tier lookup
+	// resolved it (e.g. "Kubernetes Engine" for "GKE"), whether or not a free tier matched the SKU
+	serviceName string
+	// Documented free tier matching the SKU, if any, and how it was matched
+	freeTier          *freetier.FreeTierInfo
+	freeTierItem      *freetier.FreeTierItem
+	freeTierMatchedBy string
+	freeTierWarning   string
+	// region is where the usage happens, for free tiers restricted to some regions. It is the
+	// input's region, or else the SKU's region.
+	region string
+	// skuDisplayName is the SKU's catalog display name, when it was looked up for free tier matching
+	skuDisplayName string
+
+	contractPrice *pricing.BillingAccountPrice
+	contractNote  string
+}
+
+// name identifies the line item in free tier allocation reports
+func (item *lineItem) name() string {
+	if item.id != "" {
+		return item.id
+	}
+	if item.input.Description != "" {
+		return item.input.Description
+	}
+	return item.input.SKUID
+}
+
+// resolve validates an estimate request and fetches everything needed to calculate it
+func (e *estimator) resolve(ctx context.Context, input EstimateCostInput) (*lineItem, error) {
+	if input.SKUID == "" {
+		return nil, fmt.Errorf("sku_id is required")
+	}
+
+	if input.UsageAmount < 0 {
+		return nil, fmt.Errorf("usage_amount must be non-negative")
+	}
+
+	item := &lineItem{
+		input:        input,
+		currencyCode: input.CurrencyCode,
+		region:       input.Region,
+	}
+
+	var err error
+	if item.period, item.periodName, err = parseUsagePeriod(input.UsagePeriod, input.UsagePeriodDays); err != nil {
+		return nil, err
+	}
+
+	// Default to USD if not specified
+	if item.currencyCode == "" {
+		item.currencyCode = "USD"
+	}
+
+	if item.roundingMode, err = money.ParseRoundingMode(input.RoundingMode); err != nil {
+		return nil, err
+	}
+
+	// Get the price for this SKU
+	item.priceResp, err = e.client.GetSKUPrice(ctx, input.SKUID, item.currencyCode)
+	if err != nil {
+		log.Printf("Error getting SKU price: %v", err)
+		return nil, apiError("failed to get SKU price", err)
+	}
+
+	// Select the consumption model to price with
+	item.skuPrice, err = selectConsumptionModel(item.priceResp.SKUPrices, input.ConsumptionModel)
+	if err != nil {
+		return nil, fmt.Errorf("SKU %s: %w", input.SKUID, err)
+	}
+	rate := item.skuPrice.Rate
+
+	// Convert usage to the SKU's unit
+	item.usage = money.FromFloat(input.UsageAmount)
+	if input.UsageUnit != "" && input.UsageUnit != rate.UnitInfo.Unit {
+		converted, err := units.ConvertCode(item.usage, input.UsageUnit, rate.UnitInfo.Unit)
+		if err != nil {
+			return nil, fmt.Errorf("invalid usage_unit for SKU %s: %w", input.SKUID, err)
+		}
+		item.usageConversion = fmt.Sprintf("%s %s = %s %s", item.usage, input.UsageUnit, converted, rate.UnitInfo.Unit)
+		item.usage = converted
+	}
+
+	// A free allowance encoded in the catalog is applied by the tier calculation, so a scraped
+	// free tier for the same usage must not be deducted on top of it
+	item.catalogFreeAmount, item.hasCatalogFreeTier = rate.FreeTierAmount()
+
+	// Look up documented free tier information (Issue #8)
+	if e.freeTierService != nil && input.ServiceName != "" {
+		freeTierInfo, err := e.freeTierService.GetFreeTier(ctx, input.ServiceName)
+		if err == nil && freeTierInfo != nil {
+			item.serviceName = freeTierInfo.ServiceName
+			// Find the free tier item bound to this SKU, or matching its usage unit. Region
+			// restrictions are checked against the SKU's region if the input names none.
+			sku := freetier.SKU{ID: input.SKUID, Unit: rate.UnitInfo.Unit}
+			if freeTierInfo.HasSKUBindings() || (item.region == "" && freeTierInfo.HasRegionRestrictions()) || hasBillingModeConditions(freeTierInfo) {
+				if details, err := lookupSKU(ctx, e.client, input.ServiceName, input.SKUID); err != nil {
+					log.Printf("Could not look up SKU %s for free tier matching: %v", input.SKUID, err)
+				} else {
+					sku.DisplayName = details.DisplayName
+					item.skuDisplayName = details.DisplayName
+					for _, category := range details.ProductTaxonomy.TaxonomyCategories {
+						sku.Categories = append(sku.Categories, category.Category)
+					}
+					if item.region == "" {
+						item.region = details.GeoTaxonomy.RegionalMetadata.Region.Region
+					}
+				}
+			}
+			if match := freetier.MatchSKU(freeTierInfo, sku); match.Item != nil || match.Warning != "" {
+				item.freeTier = freeTierInfo
+				item.freeTierItem = match.Item
+				item.freeTierMatchedBy = match.By
+				item.freeTierWarning = match.Warning
+			}
+		}
+	}
+
+	// Contract prices are only available for on-demand prices
+	if e.contract != nil && consumptionModelName(item.skuPrice) == defaultConsumptionModel {
+		item.contractPrice, item.contractNote = getContractPrice(ctx, e.contract, input.SKUID, item.currencyCode)
+	}
+
+	return item, nil
+}
+
+// calculate estimates the cost of a resolved line item and also returns the exact cost, which the
+// output only reports rounded for display. Documented free tiers are deducted from the allowance
+// left in ledger, which is shared by all line items of an estimate session.
+func (e *estimator) calculate(item *lineItem, ledger *freetier.Ledger) (*EstimateCostOutput, money.Decimal, error) {
+	input := item.input
+	rate := item.skuPrice.Rate
+	currencyCode := item.currencyCode
+	roundingMode := item.roundingMode
+	period := item.period
+
+	// Track original usage for reporting; all amounts are exact decimals
+	totalUsage := item.usage
+	billableUsage := totalUsage
+	freeTierApplied := money.Zero
+	var freeTierNote string
+	var freeTierSourceURL string
+	var freeTierSource string
+
+	// Free tier allowances are deducted in the SKU's unit, converted by plan.factor
+	matchingItem := item.freeTierItem
+	plan := item.planFreeTier(item.skuPrice)
+	var itemAmount, allowance, available, used money.Decimal
+	var key freetier.LedgerKey
+	if matchingItem != nil {
+		itemAmount = money.FromFloat(matchingItem.Amount)
+		// Allowances are keyed by the free tier's service name, so that line items naming the
+		// service by different aliases (e.g. "GKE" and "Kubernetes Engine") share them
+		key = freetier.NewLedgerKey(item.freeTier.ServiceName, matchingItem, item.freeTier.ItemScope(matchingItem), input.Project)
+		allowance = plan.allowance
+		available = ledger.Remaining(key, allowance)
+		// used is what earlier line items of the estimate took from the allowance
+		used = money.Max(money.Zero, allowance.Sub(available))
+	}
+
+	if matchingItem != nil && plan.catalogCovered {
+		reason := fmt.Sprintf("the catalog already prices the first %s %s of this SKU at zero", item.catalogFreeAmount, rate.UnitInfo.Unit)
+		if plan.catalogMismatch != "" {
+			reason = plan.catalogMismatch
+		}
+		freeTierNote = fmt.Sprintf(
+			"Documented free tier of %s %s (%s, %s) not deducted: %s",
+			itemAmount,
+			matchingItem.Resource,
+			item.freeTier.ItemScope(matchingItem),
+			item.freeTier.ItemPeriod(matchingItem),
+			reason,
+		)
+		freeTierSourceURL = item.freeTier.SourceURL
+		freeTierSource = item.freeTier.Source
+	} else if matchingItem != nil && plan.notApplicable != nil {
+		freeTierNote = fmt.Sprintf(
+			"Documented free tier of %s %s (%s, %s) not deducted: %v",
+			itemAmount,
+			matchingItem.Resource,
+			item.freeTier.ItemScope(matchingItem),
+			item.freeTier.ItemPeriod(matchingItem),
+			plan.notApplicable,
+		)
+		freeTierSourceURL = item.freeTier.SourceURL
+		freeTierSource = item.freeTier.Source
+	} else if matchingItem != nil {
+		// Calculate free tier deduction from what is left of the allowance over the usage period.
+		// The ledger tracks allowances in the free tier's unit, so usage is converted to it and back.
+		granted := ledger.Allocate(key, allowance, item.name(), totalUsage.Quo(plan.factor))
+		freeTierApplied = money.Min(totalUsage, granted.Mul(plan.factor))
+		billableUsage = totalUsage.Sub(freeTierApplied)
+
+		freeTierNote = fmt.Sprintf(
+			"Free tier applied: %s %s (%s, %s)",
+			itemAmount,
+			matchingItem.Resource,
+			item.freeTier.ItemScope(matchingItem),
+			item.freeTier.ItemPeriod(matchingItem),
+		)
+		if plan.allowanceNote != "" {
+			freeTierNote += fmt.Sprintf("; %s for this %s", plan.allowanceNote, item.periodName)
+		}
+		if plan.factor.Cmp(money.FromInt(1)) != 0 {
+			freeTierNote += fmt.Sprintf("; converted at 1 %s = %s %s", matchingItem.Unit, plan.factor, rate.UnitInfo.Unit)
+		}
+		if available.Cmp(allowance) < 0 {
+			freeTierNote += fmt.Sprintf("; only %s remained after earlier line items of this estimate", available)
+		}
+		if plan.catalogMismatch != "" {
+			freeTierNote += "; " + plan.catalogMismatch
+		}
+		freeTierSourceURL = item.freeTier.SourceURL
+		freeTierSource = item.freeTier.Source
+
+		log.Printf("Free tier applied for %s: %s %s deducted, billable: %s",
+			input.ServiceName, freeTierApplied, matchingItem.Resource, billableUsage)
+	}
+
+	var freeTierWarnings, freeTierConditions []string
+	if item.freeTier != nil {
+		freeTierConditions = item.freeTier.Conditions
+	}
+	if item.freeTierWarning != "" {
+		freeTierWarnings = append(freeTierWarnings, item.freeTierWarning)
+		freeTierNote = "Free tier not applied: " + item.freeTierWarning
+		freeTierSourceURL = item.freeTier.SourceURL
+		freeTierSource = item.freeTier.Source
+	}
+
+	// Calculate the cost based on billable usage (after free tier deduction),
+	// applying tiers per aggregation interval of the usage period
+	calculation, err := pricing.CalculateCostForPeriod(rate, billableUsage, period)
+	if err != nil {
+		log.Printf("Error calculating cost: %v", err)
+		return nil, money.Zero, fmt.Errorf("failed to calculate cost: %w", err)
+	}
+	estimatedCost := calculation.Cost
+
+	// Prepare output
+	estimate := CostBreakdown{
+		SKUID:                       input.SKUID,
+		UsageAmount:                 totalUsage.Float64(),
+		EstimatedCost:               estimatedCost.Float64(),
+		EstimatedCostExact:          estimatedCost.String(),
+		EstimatedCostFormatted:      money.Format(estimatedCost, currencyCode, roundingMode),
+		RoundingMode:                roundingMode.String(),
+		CurrencyCode:                currencyCode,
+		ConsumptionModel:            consumptionModelName(item.skuPrice),
+		ConsumptionModelDescription: item.skuPrice.ConsumptionModelDescription,
+		Unit:                        rate.UnitInfo.Unit,
+		UnitQuantity:                rate.UnitInfo.Quantity().Float64(),
+		NumberOfTiers:               len(rate.Tiers),
+		TieredPricing:               len(rate.Tiers) > 1,
+		// Include context from input
+		ServiceName: input.ServiceName,
+		Region:      input.Region,
+		Description: input.Description,
+		// Free tier information
+		TotalUsage:         totalUsage.Float64(),
+		FreeTierApplied:    freeTierApplied.Float64(),
+		BillableUsage:      billableUsage.Float64(),
+		FreeTierNote:       freeTierNote,
+		FreeTierSourceURL:  freeTierSourceURL,
+		FreeTierSource:     freeTierSource,
+		FreeTierWarnings:   freeTierWarnings,
+		FreeTierConditions: freeTierConditions,
+		UsageConversion:    item.usageConversion,
+		TierBreakdown:      buildTierBreakdown(calculation.Tiers),
+		UsagePeriod:        item.periodName,
+		UsagePeriodDays:    period.Days.Float64(),
+		UsageDistribution:  buildUsageDistribution(calculation, period, rate.UnitInfo.Unit),
+	}
+	if item.freeTierItem != nil {
+		estimate.FreeTierMatchedBy = item.freeTierMatchedBy
+	}
+	if item.hasCatalogFreeTier {
+		estimate.CatalogFreeTier = buildCatalogFreeTier(calculation, item.catalogFreeAmount, rate.UnitInfo.Unit)
+	}
+
+	// Calculate average price per unit for display (based on billable usage)
+	pricePerUnit := money.Zero
+	if billableUsage.Sign() > 0 {
+		pricePerUnit = estimatedCost.Quo(billableUsage)
+	} else if len(rate.Tiers) > 0 {
+		// Use first tier price if no billable usage
+		pricePerUnit = rate.PricePerUnit(rate.Tiers[0])
+	}
+	estimate.PricePerUnit = pricePerUnit.Float64()
+
+	// Create cost breakdown description
+	var breakdownDesc string
+	if estimate.CatalogFreeTier != nil {
+		breakdownDesc = estimate.CatalogFreeTier.Note + ". "
+	}
+	if freeTierApplied.Sign() > 0 {
+		breakdownDesc += fmt.Sprintf(
+			"Total usage: %s %s. Free tier deducted: %s %s. Billable usage: %s %s. ",
+			totalUsage, estimate.Unit,
+			freeTierApplied, estimate.Unit,
+			billableUsage, estimate.Unit,
+		)
+	}
+
+	if estimate.TieredPricing && calculation.Intervals.Cmp(money.FromInt(1)) > 0 {
+		breakdownDesc += fmt.Sprintf(
+			"Tiers applied per %s interval: %s %s in each of %s intervals. Estimated cost: %s %s (%s)",
+			strings.ToLower(calculation.AggregationInterval),
+			calculation.UsagePerInterval.StringFixed(6, money.HalfEven), estimate.Unit,
+			calculation.Intervals.StringFixed(2, money.HalfEven),
+			estimatedCost,
+			currencyCode,
+			estimate.EstimatedCostFormatted,
+		)
+	} else if estimate.TieredPricing && calculation.Intervals.Cmp(money.FromInt(1)) < 0 {
+		breakdownDesc += fmt.Sprintf(
+			"Tier bounds pro-rated to %s of a %s interval. Estimated cost: %s %s (%s)",
+			calculation.Intervals.StringFixed(4, money.HalfEven),
+			strings.ToLower(calculation.AggregationInterval),
+			estimatedCost,
+			currencyCode,
+			estimate.EstimatedCostFormatted,
+		)
+	} else if estimate.TieredPricing {
+		breakdownDesc += fmt.Sprintf(
+			"Calculated using %d pricing tiers. Estimated cost: %s %s (%s)",
+			estimate.NumberOfTiers,
+			estimatedCost,
+			currencyCode,
+			estimate.EstimatedCostFormatted,
+		)
+	} else {
+		breakdownDesc += fmt.Sprintf(
+			"Flat rate: %s %s per unit = %s %s (%s)",
+			pricePerUnit,
+			currencyCode,
+			estimatedCost,
+			currencyCode,
+			estimate.EstimatedCostFormatted,
+		)
+	}
+	estimate.CostBreakdown = breakdownDesc
+
+	// Contract prices are only available for the on-demand model
+	if e.contract != nil {
+		if estimate.ConsumptionModel != defaultConsumptionModel {
+			estimate.Contract = &ContractCost{
+				BillingAccount: e.contract.BillingAccount(),
+				Note:           "contract prices are only available for the on-demand (DEFAULT) consumption model",
+			}
+		} else {
+			estimate.Contract, err = calculateContractCost(e.contract.BillingAccount(), item.contractPrice, item.contractNote,
+				billableUsage, period, estimatedCost, currencyCode, roundingMode)
+			if err != nil {
+				return nil, money.Zero, err
+			}
+		}
+	}
+
+	output := &EstimateCostOutput{Estimate: estimate}
+	if input.CompareModels {
+		// Every model is priced on the usage left after its own free tier deduction, since a free
+		// tier may not be available with some models (e.g. committed use discounts)
+		freeUsage := func(skuPrice *pricing.SKUPrice) money.Decimal {
+			if skuPrice == item.skuPrice {
+				return freeTierApplied
+			}
+			plan := item.planFreeTier(skuPrice)
+			if matchingItem == nil || plan.notApplicable != nil || plan.catalogCovered {
+				return money.Zero
+			}
+			return money.Min(totalUsage, money.Max(money.Zero, plan.allowance.Sub(used)).Mul(plan.factor))
+		}
+		output.ModelComparison, err = compareConsumptionModels(item.priceResp.SKUPrices, totalUsage, freeUsage, period, currencyCode, roundingMode)
+		if err != nil {
+			return nil, money.Zero, fmt.Errorf("failed to calculate cost: %w", err)
+		}
+	}
+
+	return output, estimatedCost, nil
+}
+
+// freeTierPlan is how the documented free tier of a line item applies when the SKU is priced
+// with one of its consumption models
+type freeTierPlan struct {
+	// factor is the number of SKU units one unit of the free tier item covers
+	factor money.Decimal
+	// allowance is the amount to deduct over the usage period, in the free tier item's unit, and
+	// allowanceNote describes how it was scaled from the documented amount
+	allowance     money.Decimal
+	allowanceNote string
+	// notApplicable is the first reason the allowance cannot be deducted, if any
+	notApplicable error
+	// catalogCovered is set when the catalog's zero-priced tiers already grant the allowance, and
+	// catalogMismatch describes a catalog allowance of another amount
+	catalogCovered  bool
+	catalogMismatch string
+}
+
+// planFreeTier decides how the line item's documented free tier applies to usage priced with skuPrice
+func (item *lineItem) planFreeTier(skuPrice *pricing.SKUPrice) freeTierPlan {
+	var plan freeTierPlan
+	matchingItem := item.freeTierItem
+	if matchingItem == nil {
+		return plan
+	}
+	rate := skuPrice.Rate
+	var factorErr error
+	plan.factor, factorErr = freetier.ConversionFactor(matchingItem, rate.UnitInfo.Unit)
+	plan.allowance, plan.allowanceNote = freetier.AllowanceForPeriod(item.freeTier, matchingItem, item.period)
+	if plan.allowanceNote != "" {
+		plan.allowanceNote += fmt.Sprintf(" = %s %s", plan.allowance, matchingItem.Resource)
+	}
+
+	// A free allowance encoded in the catalog is applied by the tier calculation. Both allowances
+	// are compared over the usage period in the SKU's unit, and the documented free tier is never
+	// stacked on top of the catalog's: only the part of it above the catalog allowance is deducted.
+	if catalogFreeAmount, ok := rate.FreeTierAmount(); ok && factorErr == nil {
+		catalog := catalogFreeAmount.Mul(rate.IntervalsIn(item.period))
+		documented := plan.allowance.Mul(plan.factor)
+		switch documented.Cmp(catalog) {
+		case 0:
+			plan.catalogCovered = true
+		case -1:
+			plan.catalogCovered = true
+			plan.catalogMismatch = fmt.Sprintf(
+				"the catalog prices the first %s %s of this SKU at zero for this period, more than this free tier (%s %s), so only the catalog allowance applies",
+				catalog, rate.UnitInfo.Unit, documented, rate.UnitInfo.Unit,
+			)
+		case 1:
+			plan.allowance = documented.Sub(catalog).Quo(plan.factor)
+			plan.catalogMismatch = fmt.Sprintf(
+				"the catalog already prices the first %s %s of this SKU at zero for this period, less than this free tier (%s %s), so only the %s %s above it are deducted",
+				catalog, rate.UnitInfo.Unit, documented, rate.UnitInfo.Unit, documented.Sub(catalog), rate.UnitInfo.Unit,
+			)
+		}
+	}
+
+	plan.notApplicable = matchingItem.CheckRegion(item.region)
+	if plan.notApplicable == nil {
+		plan.notApplicable = item.conditionError(skuPrice)
+	}
+	if plan.notApplicable == nil {
+		plan.notApplicable = factorErr
+	}
+	return plan
+}
+
+// conditionError returns an error if a condition of the line item's free tier excludes its usage
+// when priced with skuPrice
+func (item *lineItem) conditionError(skuPrice *pricing.SKUPrice) error {
+	conditions := item.freeTier.Conditions
+	model := consumptionModelName(skuPrice)
+	if strings.HasPrefix(model, "COMMITTED_USE") && slices.Contains(conditions, freetier.ConditionNoCommittedUse) {
+		return fmt.Errorf("it is not available with committed use discounts (consumption model %s)", model)
+	}
+	mode := billingModeOf(item.skuDisplayName)
+	if (mode == billingModeRequest && slices.Contains(conditions, freetier.ConditionNoRequestBased)) ||
+		(mode == billingModeInstance && slices.Contains(conditions, freetier.ConditionNoInstanceBased)) {
+		return fmt.Errorf("it does not apply to %s billing (SKU %q)", mode, item.skuDisplayName)
+	}
+	return nil
+}
+
+// billingMode is how a SKU's usage is billed, for free tiers that only apply to one mode (e.g.
+// Cloud Run's request-based and instance-based billing). It is empty when unknown.
+type billingMode string
+
+const (
+	billingModeRequest  billingMode = "request-based"
+	billingModeInstance billingMode = "instance-based"
+)
+
+var (
+	instanceBasedSKU = regexp.MustCompile(`(?i)instance[- ]based|always[- ](?:on|allocated)`)
+	requestBasedSKU  = regexp.MustCompile(`(?i)requests?[- ]based|^requests?\b`)
+)
+
+// billingModeOf tells the billing mode of a SKU from its display name. Per-request fees are only
+// charged with request-based billing.
+func billingModeOf(displayName string) billingMode {
+	switch {
+	case instanceBasedSKU.MatchString(displayName):
+		return billingModeInstance
+	case requestBasedSKU.MatchString(displayName):
+		return billingModeRequest
+	}
+	return ""
+}
+
+// hasBillingModeConditions reports whether a free tier only applies to some billing modes, so
+// that applying it needs the SKU's display name
+func hasBillingModeConditions(info *freetier.FreeTierInfo) bool {
+	return slices.Contains(info.Conditions, freetier.ConditionNoRequestBased) ||
+		slices.Contains(info.Conditions, freetier.ConditionNoInstanceBased)
+}
+
+// lookupSKU finds the catalog entry of a SKU through its service
+func lookupSKU(ctx context.Context, client pricing.PricingClient, serviceName, skuID string) (*pricing.SKU, error) {
+	serviceID, _, err := findServiceByName(ctx, client, serviceName)
+	if err != nil {
+		return nil, err
+	}
+
+	pageToken := ""
+	for {
+		resp, err := client.ListSKUs(ctx, serviceID, 5000, pageToken)
+		if err != nil {
+			return nil, apiError("failed to list SKUs", err)
+		}
+		for i := range resp.SKUs {
+			if resp.SKUs[i].SKUID == skuID {
+				return &resp.SKUs[i], nil
+			}
+		}
+		if resp.NextPageToken == "" {
+			return nil, fmt.Errorf("SKU %s not found in service %s", skuID, serviceName)
+		}
+		pageToken = resp.NextPageToken
+	}
+}
@@ -0,0 +1,37 @@
+package tools
+
+import (
+	"log"
+
+	"github.com/firebase/genkit/go/ai"
+	"github.com/firebase/genkit/go/genkit"
+	"github.com/nozomi-koborinai/gcp-cost-mcp-server/internal/pricing"
+)
+
+// GetCacheStatsInput is the input for the get_cache_stats tool
+type GetCacheStatsInput struct{}
+
+// GetCacheStatsOutput is the output of the get_cache_stats tool
+type GetCacheStatsOutput struct {
+	// Enabled is false when the catalog is read from a snapshot or caching is disabled
+	Enabled bool                `json:"enabled"`
+	Stats   *pricing.CacheStats `json:"stats,omitempty"`
+}
+
+// NewGetCacheStats creates a tool that reports the statistics of the catalog cache
+func NewGetCacheStats(g *genkit.Genkit, client pricing.PricingClient) ai.Tool {
+	return genkit.DefineTool(
+		g,
+		"get_cache_stats",
+		"Reports statistics of the Billing Catalog cache: cached entries, hits (from memory and from disk), misses, cache directory and TTLs. Use this to check whether repeated catalog lookups are served from the cache.",
+		func(ctx *ai.ToolContext, input GetCacheStatsInput) (*GetCacheStatsOutput, error) {
+			log.Printf("Tool 'get_cache_stats' called")
+
+			cachedClient, ok := client.(*pricing.CachedClient)
+			if !ok {
+				return &GetCacheStatsOutput{Enabled: false}, nil
+			}
+			stats := cachedClient.GetCacheStats()
+			return &GetCacheStatsOutput{Enabled: true, Stats: &stats}, nil
+		})
+}
@@ -2,6 +2,7 @@ package tools
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"strings"
@@ -33,6 +34,12 @@ type FreeTierSummary struct {
 	Scope     string                  `json:"scope,omitempty"`
 	Period    string                  `json:"period,omitempty"`
 	SourceURL string                  `json:"source_url,omitempty"`
+	// Source is "dataset" for the curated free tier dataset, "free_program" for the Free Program
+	// page or "scrape" for documentation scraping
+	Source         string `json:"source,omitempty"`
+	DatasetVersion string `json:"dataset_version,omitempty"`
+	// Conditions are the caveats of the free tier, e.g. region restrictions or scope
+	Conditions []string `json:"conditions,omitempty"`
 }
 
 // EstimationGuide represents the guide for estimating costs
@@ -55,7 +62,7 @@ type GetEstimationGuideOutput struct {
 }
 
 // NewGetEstimationGuide creates a tool that provides estimation requirements for GCP services
-func NewGetEstimationGuide(g *genkit.Genkit, pricingClient *pricing.Client, freeTierService *freetier.Service) ai.Tool {
+func NewGetEstimationGuide(g *genkit.Genkit, pricingClient pricing.PricingClient, freeTierService *freetier.Service) ai.Tool {
 	return genkit.DefineTool(
 		g,
 		"get_estimation_guide",
@@ -94,6 +101,10 @@ When the user provides an architecture diagram (image):
 			// Find the service ID
 			serviceID, displayName, err := findServiceByName(ctx.Context, pricingClient, input.ServiceName)
 			if err != nil {
+				// Credential problems affect every later call, so report them instead of degrading
+				if errors.Is(err, pricing.ErrUnauthenticated) || errors.Is(err, pricing.ErrPermissionDenied) {
+					return nil, apiError("failed to look up service", err)
+				}
 				log.Printf("Warning: Could not find service ID for %s: %v", input.ServiceName, err)
 				// Continue without service ID - we can still provide a generic guide
 			}
@@ -130,6 +141,10 @@ When the user provides an architecture diagram (image):
 						Scope:     freeTierInfo.Scope,
 						Period:    freeTierInfo.Period,
 						SourceURL: freeTierInfo.SourceURL,
+						// Where the free tier information came from
+						Source:         freeTierInfo.Source,
+						DatasetVersion: freeTierInfo.DatasetVersion,
+						Conditions:     freeTierInfo.Conditions,
 					}
 				} else {
 					guide.FreeTier = &FreeTierSummary{
@@ -149,7 +164,7 @@ When the user provides an architecture diagram (image):
 }
 
 // findServiceByName searches for a GCP service by name and returns its ID
-func findServiceByName(ctx context.Context, client *pricing.Client, serviceName string) (string, string, error) {
+func findServiceByName(ctx context.Context, client pricing.PricingClient, serviceName string) (string, string, error) {
 	normalizedName := strings.ToLower(strings.TrimSpace(serviceName))
 
 	// Fetch services from the API
@@ -204,7 +219,7 @@ func getServiceAliases() map[string]string {
 }
 
 // analyzeSkusToGenerateGuide analyzes SKUs for a service and generates an estimation guide
-func analyzeSkusToGenerateGuide(ctx context.Context, client *pricing.Client, serviceID, serviceName string) (*EstimationGuide, error) {
+func analyzeSkusToGenerateGuide(ctx context.Context, client pricing.PricingClient, serviceID, serviceName string) (*EstimationGuide, error) {
 	// Fetch SKUs for the service
 	resp, err := client.ListSKUs(ctx, serviceID, 500, "")
 	if err != nil {
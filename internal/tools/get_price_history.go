@@ -0,0 +1,97 @@
+package tools
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/firebase/genkit/go/ai"
+	"github.com/firebase/genkit/go/genkit"
+	"github.com/nozomi-koborinai/gcp-cost-mcp-server/internal/pricing"
+)
+
+// GetPriceHistoryInput is the input for the get_price_history tool
+type GetPriceHistoryInput struct {
+	SKUID        string `json:"sku_id" jsonschema_description:"The SKU ID to list prices for (e.g., '0008-F633-76AA'). Use list_skus to find SKU IDs."`
+	CurrencyCode string `json:"currency_code,omitempty" jsonschema_description:"ISO-4217 currency code (e.g., 'USD', 'JPY', 'EUR'). Defaults to USD if not specified."`
+}
+
+// ListedPrice is a price of a SKU as published by the Cloud Billing API
+type ListedPrice struct {
+	Name            string        `json:"name"`
+	ValueType       string        `json:"value_type,omitempty"`
+	Unit            string        `json:"unit"`
+	UnitDescription string        `json:"unit_description,omitempty"`
+	UnitQuantity    float64       `json:"unit_quantity"`
+	Tiers           []PricingTier `json:"tiers"`
+}
+
+// GetPriceHistoryOutput is the output of the get_price_history tool
+type GetPriceHistoryOutput struct {
+	SKUID        string        `json:"sku_id"`
+	CurrencyCode string        `json:"currency_code"`
+	Prices       []ListedPrice `json:"prices"`
+	// Note explains that past prices are not available
+	Note string `json:"note"`
+}
+
+// priceHistoryNote explains why get_price_history only lists current prices
+const priceHistoryNote = "The Cloud Billing API only publishes the latest prices and does not report when a price took effect, so past prices are not available."
+
+// NewGetPriceHistory creates a tool that lists the prices the Cloud Billing API publishes for a SKU.
+// Price history is not supported: the API's price resources have no effective dates.
+func NewGetPriceHistory(g *genkit.Genkit, client pricing.PricingClient) ai.Tool {
+	return genkit.DefineTool(
+		g,
+		"get_price_history",
+		"Lists the prices the Cloud Billing API currently publishes for a SKU. Price history is NOT supported: the API only lists the latest prices and does not report when a price took effect, so this tool cannot tell when or how a price changed, and estimates cannot be priced as of a past date. To track changes over time, save catalog snapshots and compare them with diff_catalog.",
+		func(ctx *ai.ToolContext, input GetPriceHistoryInput) (*GetPriceHistoryOutput, error) {
+			log.Printf("Tool 'get_price_history' called for sku_id: %s", input.SKUID)
+
+			if input.SKUID == "" {
+				return nil, fmt.Errorf("sku_id is required")
+			}
+
+			priceClient, ok := client.(pricing.PriceListClient)
+			if !ok {
+				return nil, fmt.Errorf("listing SKU prices requires the live Cloud Billing API and is not available in offline snapshot mode")
+			}
+
+			currencyCode := input.CurrencyCode
+			if currencyCode == "" {
+				currencyCode = "USD"
+			}
+
+			prices, err := pricing.ListAllPrices(ctx.Context, priceClient, input.SKUID, currencyCode)
+			if err != nil {
+				log.Printf("Error listing SKU prices: %v", err)
+				return nil, apiError("failed to list SKU prices", err)
+			}
+
+			output := &GetPriceHistoryOutput{
+				SKUID:        input.SKUID,
+				CurrencyCode: currencyCode,
+				Prices:       []ListedPrice{},
+				Note:         priceHistoryNote,
+			}
+			for i := range prices {
+				output.Prices = append(output.Prices, buildListedPrice(&prices[i]))
+			}
+			return output, nil
+		})
+}
+
+// buildListedPrice converts a published price for display
+func buildListedPrice(p *pricing.Price) ListedPrice {
+	result := ListedPrice{
+		Name:      p.Name,
+		ValueType: p.ValueType,
+		Tiers:     []PricingTier{},
+	}
+	if p.Rate != nil {
+		result.Unit = p.Rate.UnitInfo.Unit
+		result.UnitDescription = p.Rate.UnitInfo.UnitDescription
+		result.UnitQuantity = p.Rate.UnitInfo.Quantity().Float64()
+		result.Tiers = buildPricingTiers(p.Rate)
+	}
+	return result
+}
@@ -18,9 +18,13 @@ type GetSKUPriceInput struct {
 
 // PricingTier represents a pricing tier
 type PricingTier struct {
-	StartAmount  float64 `json:"start_amount"`
+	StartAmount float64 `json:"start_amount"`
+	// PricePerUnit is the price of a single unit; ListPrice is the catalog price per unit_quantity units
 	PricePerUnit float64 `json:"price_per_unit"`
-	Currency     string  `json:"currency"`
+	ListPrice    float64 `json:"list_price"`
+	// PricePerUnitExact is PricePerUnit as an exact decimal string
+	PricePerUnitExact string `json:"price_per_unit_exact"`
+	Currency          string `json:"currency"`
 }
 
 // ConsumptionPricing represents pricing for a specific consumption model (e.g., on-demand, CUD)
@@ -29,19 +33,41 @@ type ConsumptionPricing struct {
 	Description      string        `json:"description,omitempty"`
 	Unit             string        `json:"unit"`
 	UnitDescription  string        `json:"unit_description"`
+	UnitQuantity     float64       `json:"unit_quantity"`
 	Tiers            []PricingTier `json:"tiers"`
 	AggregationInfo  string        `json:"aggregation_info,omitempty"`
 }
 
 // PriceInfo represents pricing information
 type PriceInfo struct {
-	SKUID              string               `json:"sku_id"`
-	CurrencyCode       string               `json:"currency_code"`
-	Unit               string               `json:"unit"`
-	UnitDescription    string               `json:"unit_description"`
-	Tiers              []PricingTier         `json:"tiers"`
-	AggregationInfo    string               `json:"aggregation_info,omitempty"`
-	AllPricingModels   []ConsumptionPricing  `json:"all_pricing_models,omitempty"`
+	SKUID            string               `json:"sku_id"`
+	CurrencyCode     string               `json:"currency_code"`
+	Unit             string               `json:"unit"`
+	UnitDescription  string               `json:"unit_description"`
+	UnitQuantity     float64              `json:"unit_quantity"`
+	Tiers            []PricingTier        `json:"tiers"`
+	AggregationInfo  string               `json:"aggregation_info,omitempty"`
+	AllPricingModels []ConsumptionPricing `json:"all_pricing_models,omitempty"`
+	// ContractPrice is the effective price for the configured billing account, if any
+	ContractPrice *ContractPriceInfo `json:"contract_price,omitempty"`
+}
+
+// buildPricingTiers converts the tiers of a rate for display
+func buildPricingTiers(rate *pricing.Rate) []PricingTier {
+	tiers := []PricingTier{}
+	for _, tier := range rate.Tiers {
+		startAmount, _ := strconv.ParseFloat(tier.StartAmount.Value, 64)
+
+		pricePerUnit := rate.PricePerUnit(tier)
+		tiers = append(tiers, PricingTier{
+			StartAmount:       startAmount,
+			PricePerUnit:      pricePerUnit.Float64(),
+			ListPrice:         tier.ListPrice.Decimal().Float64(),
+			PricePerUnitExact: pricePerUnit.String(),
+			Currency:          tier.ListPrice.CurrencyCode,
+		})
+	}
+	return tiers
 }
 
 // GetSKUPriceOutput is the output of the get_sku_price tool
@@ -50,11 +76,12 @@ type GetSKUPriceOutput struct {
 }
 
 // NewGetSKUPrice creates a tool that gets the price for a specific SKU
-func NewGetSKUPrice(g *genkit.Genkit, client *pricing.Client) ai.Tool {
+// When contract is non-nil, the effective price for its billing account is reported alongside the list price.
+func NewGetSKUPrice(g *genkit.Genkit, client pricing.PricingClient, contract *pricing.ContractPricer) ai.Tool {
 	return genkit.DefineTool(
 		g,
 		"get_sku_price",
-		"Gets detailed pricing information for a specific SKU. Returns the price per unit and any tiered pricing information. IMPORTANT: You must first use list_skus to obtain the SKU ID before calling this tool.",
+		"Gets detailed pricing information for a specific SKU. Returns the price per unit and any tiered pricing information. When a billing account is configured, the negotiated contract price is returned in contract_price next to the list price. IMPORTANT: You must first use list_skus to obtain the SKU ID before calling this tool.",
 		func(ctx *ai.ToolContext, input GetSKUPriceInput) (*GetSKUPriceOutput, error) {
 			log.Printf("Tool 'get_sku_price' called for sku_id: %s, currency: %s", input.SKUID, input.CurrencyCode)
 
@@ -70,7 +97,7 @@ func NewGetSKUPrice(g *genkit.Genkit, client *pricing.Client) ai.Tool {
 			resp, err := client.GetSKUPrice(ctx.Context, input.SKUID, currencyCode)
 			if err != nil {
 				log.Printf("Error getting SKU price: %v", err)
-				return nil, fmt.Errorf("failed to get SKU price: %w", err)
+				return nil, apiError("failed to get SKU price", err)
 			}
 
 			priceInfo := PriceInfo{
@@ -92,7 +119,8 @@ func NewGetSKUPrice(g *genkit.Genkit, client *pricing.Client) ai.Tool {
 					Description:      skuPrice.ConsumptionModelDescription,
 					Unit:             rate.UnitInfo.Unit,
 					UnitDescription:  rate.UnitInfo.UnitDescription,
-					Tiers:            []PricingTier{},
+					UnitQuantity:     rate.UnitInfo.Quantity().Float64(),
+					Tiers:            buildPricingTiers(rate),
 				}
 
 				if rate.AggregationInfo.Level != "" || rate.AggregationInfo.Interval != "" {
@@ -101,24 +129,12 @@ func NewGetSKUPrice(g *genkit.Genkit, client *pricing.Client) ai.Tool {
 						rate.AggregationInfo.Interval)
 				}
 
-				for _, tier := range rate.Tiers {
-					startAmount, _ := strconv.ParseFloat(tier.StartAmount.Value, 64)
-					units, _ := strconv.ParseFloat(tier.ListPrice.Units, 64)
-					nanos := float64(tier.ListPrice.Nanos) / 1e9
-					pricePerUnit := units + nanos
-
-					model.Tiers = append(model.Tiers, PricingTier{
-						StartAmount:  startAmount,
-						PricePerUnit: pricePerUnit,
-						Currency:     tier.ListPrice.CurrencyCode,
-					})
-				}
-
 				allModels = append(allModels, model)
 
 				if idx == 0 {
 					priceInfo.Unit = rate.UnitInfo.Unit
 					priceInfo.UnitDescription = rate.UnitInfo.UnitDescription
+					priceInfo.UnitQuantity = model.UnitQuantity
 					priceInfo.AggregationInfo = model.AggregationInfo
 					priceInfo.Tiers = model.Tiers
 				}
@@ -128,6 +144,11 @@ func NewGetSKUPrice(g *genkit.Genkit, client *pricing.Client) ai.Tool {
 				priceInfo.AllPricingModels = allModels
 			}
 
+			if contract != nil {
+				contractPrice, note := getContractPrice(ctx.Context, contract, input.SKUID, currencyCode)
+				priceInfo.ContractPrice = buildContractPriceInfo(contract.BillingAccount(), contractPrice, note)
+			}
+
 			return &GetSKUPriceOutput{
 				Price: priceInfo,
 			}, nil
@@ -2,7 +2,10 @@ package tools
 
 import (
 	"encoding/json"
+	"errors"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/nozomi-koborinai/gcp-cost-mcp-server/internal/freetier"
 	"github.com/nozomi-koborinai/gcp-cost-mcp-server/internal/pricing"
@@ -724,3 +727,83 @@ func TestBuildSuggestedQuestion(t *testing.T) {
 	}
 }
 
+func TestAPIError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		sentinel error
+		contains string
+	}{
+		{
+			name:     "Unauthenticated suggests ADC login",
+			err:      &pricing.APIError{StatusCode: 401, Status: "UNAUTHENTICATED", Message: "invalid credentials"},
+			sentinel: pricing.ErrUnauthenticated,
+			contains: "gcloud auth application-default login",
+		},
+		{
+			name:     "Unauthenticated API key does not suggest ADC login",
+			err:      &pricing.CredentialError{Mode: pricing.CredentialsAPIKey, Err: &pricing.APIError{StatusCode: 401, Message: "invalid key"}},
+			sentinel: pricing.ErrUnauthenticated,
+			contains: "GCP_COST_API_KEY",
+		},
+		{
+			name:     "Unauthenticated credentials file names the file",
+			err:      &pricing.CredentialError{Mode: pricing.CredentialsFile, Subject: "/keys/sa.json", Err: pricing.ErrUnauthenticated},
+			sentinel: pricing.ErrUnauthenticated,
+			contains: "credentials file /keys/sa.json was rejected",
+		},
+		{
+			name:     "Unauthenticated impersonation names the service account",
+			err:      &pricing.CredentialError{Mode: pricing.CredentialsImpersonation, Subject: "sa@p.iam.gserviceaccount.com", Err: pricing.ErrUnauthenticated},
+			sentinel: pricing.ErrUnauthenticated,
+			contains: "sa@p.iam.gserviceaccount.com",
+		},
+		{
+			name:     "Permission denied mentions billing viewer",
+			err:      &pricing.APIError{StatusCode: 403, Status: "PERMISSION_DENIED", Message: "denied"},
+			sentinel: pricing.ErrPermissionDenied,
+			contains: "roles/billing.viewer",
+		},
+		{
+			name:     "Not found points to list tools",
+			err:      &pricing.APIError{StatusCode: 404, Status: "NOT_FOUND", Message: "SKU not found"},
+			sentinel: pricing.ErrNotFound,
+			contains: "list_skus",
+		},
+		{
+			name:     "Rate limited suggests retry",
+			err:      &pricing.APIError{StatusCode: 429, Status: "RESOURCE_EXHAUSTED", Message: "quota"},
+			sentinel: pricing.ErrRateLimited,
+			contains: "retry",
+		},
+		{
+			name:     "Rate limited with a long Retry-After names the delay",
+			err:      &pricing.RateLimitError{RetryAfter: time.Hour, Err: &pricing.APIError{StatusCode: 429, Message: "quota"}},
+			sentinel: pricing.ErrRateLimited,
+			contains: "Wait 1h0m0s before retrying",
+		},
+		{
+			name:     "Invalid argument",
+			err:      &pricing.APIError{StatusCode: 400, Status: "INVALID_ARGUMENT", Message: "bad currency"},
+			sentinel: pricing.ErrInvalidArgument,
+			contains: "currency code",
+		},
+		{
+			name:     "Unknown error is wrapped unchanged",
+			err:      errors.New("connection reset"),
+			contains: "failed to get SKU price: connection reset",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := apiError("failed to get SKU price", tt.err)
+			if !strings.Contains(got.Error(), tt.contains) {
+				t.Errorf("apiError() = %q, want it to contain %q", got.Error(), tt.contains)
+			}
+			if tt.sentinel != nil && !errors.Is(got, tt.sentinel) {
+				t.Errorf("errors.Is(apiError(), %v) = false", tt.sentinel)
+			}
+		})
+	}
+}
@@ -2,7 +2,6 @@
 package tools
 
 import (
-	"fmt"
 	"log"
 	"strings"
 
@@ -33,7 +32,7 @@ type ListServicesOutput struct {
 }
 
 // NewListServices creates a tool that lists all Google Cloud services
-func NewListServices(g *genkit.Genkit, client *pricing.Client) ai.Tool {
+func NewListServices(g *genkit.Genkit, client pricing.PricingClient) ai.Tool {
 	return genkit.DefineTool(
 		g,
 		"list_services",
@@ -51,7 +50,7 @@ func NewListServices(g *genkit.Genkit, client *pricing.Client) ai.Tool {
 					resp, err := client.ListServices(ctx.Context, 5000, pageToken)
 					if err != nil {
 						log.Printf("Error listing services: %v", err)
-						return nil, fmt.Errorf("failed to list services: %w", err)
+						return nil, apiError("failed to list services", err)
 					}
 					allServices = append(allServices, resp.Services...)
 					if resp.NextPageToken == "" {
@@ -70,7 +69,7 @@ func NewListServices(g *genkit.Genkit, client *pricing.Client) ai.Tool {
 			resp, err := client.ListServices(ctx.Context, input.PageSize, input.PageToken)
 			if err != nil {
 				log.Printf("Error listing services: %v", err)
-				return nil, fmt.Errorf("failed to list services: %w", err)
+				return nil, apiError("failed to list services", err)
 			}
 
 			services := make([]ServiceInfo, len(resp.Services))
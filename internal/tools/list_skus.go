@@ -37,7 +37,7 @@ type ListSKUsOutput struct {
 }
 
 // NewListSKUs creates a tool that lists SKUs for a specific Google Cloud service
-func NewListSKUs(g *genkit.Genkit, client *pricing.Client) ai.Tool {
+func NewListSKUs(g *genkit.Genkit, client pricing.PricingClient) ai.Tool {
 	return genkit.DefineTool(
 		g,
 		"list_skus",
@@ -61,7 +61,7 @@ func NewListSKUs(g *genkit.Genkit, client *pricing.Client) ai.Tool {
 					resp, err := client.ListSKUs(ctx.Context, input.ServiceID, 5000, pageToken)
 					if err != nil {
 						log.Printf("Error listing SKUs: %v", err)
-						return nil, fmt.Errorf("failed to list SKUs: %w", err)
+						return nil, apiError("failed to list SKUs", err)
 					}
 					allSKUs = append(allSKUs, resp.SKUs...)
 					if resp.NextPageToken == "" {
@@ -73,7 +73,7 @@ func NewListSKUs(g *genkit.Genkit, client *pricing.Client) ai.Tool {
 				resp, err := client.ListSKUs(ctx.Context, input.ServiceID, input.PageSize, input.PageToken)
 				if err != nil {
 					log.Printf("Error listing SKUs: %v", err)
-					return nil, fmt.Errorf("failed to list SKUs: %w", err)
+					return nil, apiError("failed to list SKUs", err)
 				}
 				allSKUs = resp.SKUs
 
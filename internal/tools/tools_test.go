@@ -0,0 +1,1252 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/firebase/genkit/go/ai"
+	"github.com/firebase/genkit/go/genkit"
+	"github.com/nozomi-koborinai/gcp-cost-mcp-server/internal/freetier"
+	"github.com/nozomi-koborinai/gcp-cost-mcp-server/internal/money"
+	"github.com/nozomi-koborinai/gcp-cost-mcp-server/internal/pricing"
+)
+
+// testCatalog returns a small catalog snapshot used to run the tools deterministically
+func testCatalog() *pricing.Snapshot {
+	snapshot := pricing.NewSnapshot()
+	snapshot.Services = []pricing.Service{
+		{Name: "services/152E-C115-5142", ServiceID: "152E-C115-5142", DisplayName: "Cloud Run"},
+		{Name: "services/6F81-5844-456A", ServiceID: "6F81-5844-456A", DisplayName: "Compute Engine"},
+		{Name: "services/AAAA-0000-CENT", ServiceID: "AAAA-0000-CENT", DisplayName: "OpenLogic CentOS 7.9"},
+	}
+
+	tokyo := pricing.GeoTaxonomy{
+		Type:             "REGIONAL",
+		RegionalMetadata: pricing.RegionalMetadata{Region: pricing.Region{Region: "asia-northeast1"}},
+	}
+	compute := pricing.ProductTaxonomy{TaxonomyCategories: []pricing.TaxonomyCategory{{Category: "Compute"}}}
+	snapshot.SKUs["152E-C115-5142"] = []pricing.SKU{
+		{SKUID: "CPU-TOKYO", DisplayName: "CPU Allocation Time (tier 1)", GeoTaxonomy: tokyo, ProductTaxonomy: compute},
+		{SKUID: "MEM-TOKYO", DisplayName: "Memory Allocation Time (tier 1)", GeoTaxonomy: tokyo, ProductTaxonomy: compute},
+		{SKUID: "REQUESTS", DisplayName: "Requests", GeoTaxonomy: pricing.GeoTaxonomy{Type: "GLOBAL"}},
+		{SKUID: "REQUESTS-1M", DisplayName: "Requests (per million)", GeoTaxonomy: pricing.GeoTaxonomy{Type: "GLOBAL"}},
+		{SKUID: "VCPU-CUD", DisplayName: "vCPU (with commitments)", GeoTaxonomy: tokyo, ProductTaxonomy: compute},
+	}
+
+	snapshot.SetPrice("CPU-TOKYO", "USD", &pricing.GetPriceResponse{
+		Name:         "skus/CPU-TOKYO/price",
+		CurrencyCode: "USD",
+		SKUPrices: []pricing.SKUPrice{{
+			ConsumptionModel: "DEFAULT",
+			Rate: &pricing.Rate{
+				UnitInfo:        pricing.UnitInfo{Unit: "s", UnitDescription: "second", UnitQuantity: pricing.Amount{Value: "1"}},
+				AggregationInfo: pricing.AggregationInfo{Level: "ACCOUNT", Interval: "MONTHLY"},
+				Tiers: []pricing.Tier{
+					{StartAmount: pricing.Amount{Value: "0"}, ListPrice: pricing.Money{CurrencyCode: "USD", Nanos: 24000}},
+				},
+			},
+		}},
+	})
+	snapshot.SetPrice("REQUESTS", "USD", &pricing.GetPriceResponse{
+		Name:         "skus/REQUESTS/price",
+		CurrencyCode: "USD",
+		SKUPrices: []pricing.SKUPrice{{
+			ConsumptionModel: "DEFAULT",
+			Rate: &pricing.Rate{
+				UnitInfo: pricing.UnitInfo{Unit: "count", UnitDescription: "count", UnitQuantity: pricing.Amount{Value: "1"}},
+				Tiers: []pricing.Tier{
+					{StartAmount: pricing.Amount{Value: "0"}, ListPrice: pricing.Money{CurrencyCode: "USD"}},
+					{StartAmount: pricing.Amount{Value: "2000000"}, ListPrice: pricing.Money{CurrencyCode: "USD", Nanos: 400}},
+				},
+			},
+		}},
+	})
+	// Same request price, listed per 1,000,000 requests as the catalog does for many SKUs
+	snapshot.SetPrice("REQUESTS-1M", "USD", &pricing.GetPriceResponse{
+		Name:         "skus/REQUESTS-1M/price",
+		CurrencyCode: "USD",
+		SKUPrices: []pricing.SKUPrice{{
+			ConsumptionModel: "DEFAULT",
+			Rate: &pricing.Rate{
+				UnitInfo: pricing.UnitInfo{Unit: "count", UnitDescription: "count", UnitQuantity: pricing.Amount{Value: "1000000"}},
+				Tiers: []pricing.Tier{
+					{StartAmount: pricing.Amount{Value: "0"}, ListPrice: pricing.Money{CurrencyCode: "USD"}},
+					{StartAmount: pricing.Amount{Value: "2000000"}, ListPrice: pricing.Money{CurrencyCode: "USD", Nanos: 400000000}},
+				},
+			},
+		}},
+	})
+	// On-demand and commitment prices for the same SKU
+	hourly := func(nanos int64) *pricing.Rate {
+		return &pricing.Rate{
+			UnitInfo: pricing.UnitInfo{Unit: "h", UnitDescription: "hour", UnitQuantity: pricing.Amount{Value: "1"}},
+			Tiers:    []pricing.Tier{{StartAmount: pricing.Amount{Value: "0"}, ListPrice: pricing.Money{CurrencyCode: "USD", Nanos: nanos}}},
+		}
+	}
+	snapshot.SetPrice("VCPU-CUD", "USD", &pricing.GetPriceResponse{
+		Name:         "skus/VCPU-CUD/price",
+		CurrencyCode: "USD",
+		SKUPrices: []pricing.SKUPrice{
+			{ConsumptionModel: "COMMITTED_USE_1Y", ConsumptionModelDescription: "1 year commitment", Rate: hourly(21000000)},
+			{ConsumptionModel: "DEFAULT", ConsumptionModelDescription: "On-demand", Rate: hourly(30000000)},
+			{ConsumptionModel: "COMMITTED_USE_3Y", ConsumptionModelDescription: "3 year commitment", Rate: hourly(15000000)},
+		},
+	})
+
+	return snapshot
+}
+
+// fakeAccountClient serves fixed contract prices
+type fakeAccountClient struct {
+	prices map[string]*pricing.BillingAccountPrice
+}
+
+func (c *fakeAccountClient) ListBillingAccountSKUs(ctx context.Context, billingAccount string, serviceID string, pageSize int, pageToken string) (*pricing.ListSKUsResponse, error) {
+	return &pricing.ListSKUsResponse{}, nil
+}
+
+func (c *fakeAccountClient) GetBillingAccountSKUPrice(ctx context.Context, billingAccount string, skuID string, currencyCode string) (*pricing.BillingAccountPrice, error) {
+	price, ok := c.prices[skuID]
+	if !ok {
+		return nil, pricing.ErrPermissionDenied
+	}
+	return price, nil
+}
+
+// testContractPricer returns a ContractPricer with a 20% discount on CPU-TOKYO
+func testContractPricer() *pricing.ContractPricer {
+	return pricing.NewContractPricer(&fakeAccountClient{prices: map[string]*pricing.BillingAccountPrice{
+		"CPU-TOKYO": {
+			CurrencyCode: "USD",
+			Rate: &pricing.Rate{
+				UnitInfo: pricing.UnitInfo{Unit: "s", UnitQuantity: pricing.Amount{Value: "1"}},
+				Tiers:    []pricing.Tier{{StartAmount: pricing.Amount{Value: "0"}, ListPrice: pricing.Money{CurrencyCode: "USD", Nanos: 19200}}},
+			},
+			PriceReason: &pricing.PriceReason{
+				Type:          "fixed-discount",
+				FixedDiscount: &pricing.PriceDiscount{DiscountPercent: pricing.Amount{Value: "20"}},
+			},
+		},
+	}}, "billingAccounts/0123AB-456789-CDEF01")
+}
+
+// runTool runs a tool with the given input and decodes its output into Out
+func runTool[Out any](t *testing.T, tool ai.Tool, input any) (*Out, error) {
+	t.Helper()
+
+	raw, err := tool.RunRaw(context.Background(), input)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(raw)
+	if err != nil {
+		t.Fatalf("failed to marshal tool output: %v", err)
+	}
+	var out Out
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatalf("failed to unmarshal tool output: %v", err)
+	}
+	return &out, nil
+}
+
+func newTestGenkit() *genkit.Genkit {
+	return genkit.Init(context.Background())
+}
+
+func approxEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-6
+}
+
+func TestListServicesTool_Snapshot(t *testing.T) {
+	tool := NewListServices(newTestGenkit(), pricing.NewSnapshotClient(testCatalog()))
+
+	out, err := runTool[ListServicesOutput](t, tool, ListServicesInput{CoreOnly: true})
+	if err != nil {
+		t.Fatalf("list_services failed: %v", err)
+	}
+	if out.TotalReturned != 2 {
+		t.Errorf("TotalReturned = %d, want 2 (marketplace image excluded)", out.TotalReturned)
+	}
+}
+
+func TestListSKUsTool_Snapshot(t *testing.T) {
+	tool := NewListSKUs(newTestGenkit(), pricing.NewSnapshotClient(testCatalog()))
+
+	out, err := runTool[ListSKUsOutput](t, tool, ListSKUsInput{ServiceID: "152E-C115-5142", Region: "asia-northeast1"})
+	if err != nil {
+		t.Fatalf("list_skus failed: %v", err)
+	}
+	if out.TotalReturned != 3 {
+		t.Errorf("TotalReturned = %d, want 3 Tokyo SKUs", out.TotalReturned)
+	}
+
+	if _, err := runTool[ListSKUsOutput](t, tool, ListSKUsInput{ServiceID: "UNKNOWN"}); err == nil {
+		t.Error("expected error for unknown service")
+	}
+}
+
+func TestGetSKUPriceTool_Snapshot(t *testing.T) {
+	tool := NewGetSKUPrice(newTestGenkit(), pricing.NewSnapshotClient(testCatalog()), nil)
+
+	out, err := runTool[GetSKUPriceOutput](t, tool, GetSKUPriceInput{SKUID: "REQUESTS"})
+	if err != nil {
+		t.Fatalf("get_sku_price failed: %v", err)
+	}
+	if len(out.Price.Tiers) != 2 {
+		t.Fatalf("got %d tiers, want 2", len(out.Price.Tiers))
+	}
+	if !approxEqual(out.Price.Tiers[1].PricePerUnit, 0.0000004) {
+		t.Errorf("tier 2 price = %v, want 0.0000004", out.Price.Tiers[1].PricePerUnit)
+	}
+}
+
+func TestGetSKUPriceTool_UnitQuantity(t *testing.T) {
+	tool := NewGetSKUPrice(newTestGenkit(), pricing.NewSnapshotClient(testCatalog()), nil)
+
+	out, err := runTool[GetSKUPriceOutput](t, tool, GetSKUPriceInput{SKUID: "REQUESTS-1M"})
+	if err != nil {
+		t.Fatalf("get_sku_price failed: %v", err)
+	}
+	if out.Price.UnitQuantity != 1000000 {
+		t.Errorf("UnitQuantity = %v, want 1000000", out.Price.UnitQuantity)
+	}
+	tier := out.Price.Tiers[1]
+	if !approxEqual(tier.ListPrice, 0.4) {
+		t.Errorf("ListPrice = %v, want 0.4 per 1M", tier.ListPrice)
+	}
+	if !approxEqual(tier.PricePerUnit, 0.0000004) {
+		t.Errorf("PricePerUnit = %v, want 0.0000004 per request", tier.PricePerUnit)
+	}
+}
+
+func TestEstimateCostTool_Snapshot(t *testing.T) {
+	tool := NewEstimateCost(newTestGenkit(), pricing.NewSnapshotClient(testCatalog()), nil, nil)
+
+	out, err := runTool[EstimateCostOutput](t, tool, EstimateCostInput{SKUID: "CPU-TOKYO", UsageAmount: 2628000})
+	if err != nil {
+		t.Fatalf("estimate_cost failed: %v", err)
+	}
+	if !approxEqual(out.Estimate.EstimatedCost, 63.072) {
+		t.Errorf("EstimatedCost = %v, want 63.072", out.Estimate.EstimatedCost)
+	}
+
+	out, err = runTool[EstimateCostOutput](t, tool, EstimateCostInput{SKUID: "REQUESTS", UsageAmount: 3000000})
+	if err != nil {
+		t.Fatalf("estimate_cost failed: %v", err)
+	}
+	if !approxEqual(out.Estimate.EstimatedCost, 0.4) {
+		t.Errorf("EstimatedCost = %v, want 0.4 (first 2M requests free)", out.Estimate.EstimatedCost)
+	}
+	breakdown := out.Estimate.TierBreakdown
+	if len(breakdown) != 2 {
+		t.Fatalf("TierBreakdown = %+v, want 2 tiers", breakdown)
+	}
+	if breakdown[0].EndAmount == nil || *breakdown[0].EndAmount != 2000000 || breakdown[0].Usage != 2000000 || breakdown[0].SubtotalExact != "0" {
+		t.Errorf("free tier line = %+v", breakdown[0])
+	}
+	if breakdown[1].EndAmount != nil || breakdown[1].Usage != 1000000 || breakdown[1].PricePerUnitExact != "0.0000004" || breakdown[1].SubtotalExact != "0.4" {
+		t.Errorf("paid tier line = %+v", breakdown[1])
+	}
+	if free := out.Estimate.CatalogFreeTier; free == nil || free.Amount != 2000000 || free.Applied != 2000000 {
+		t.Errorf("CatalogFreeTier = %+v, want 2,000,000 free requests used", free)
+	}
+
+	// A SKU priced per 1M requests must give the same result
+	out, err = runTool[EstimateCostOutput](t, tool, EstimateCostInput{SKUID: "REQUESTS-1M", UsageAmount: 3000000})
+	if err != nil {
+		t.Fatalf("estimate_cost failed: %v", err)
+	}
+	if !approxEqual(out.Estimate.EstimatedCost, 0.4) {
+		t.Errorf("EstimatedCost = %v, want 0.4 for per-1M SKU", out.Estimate.EstimatedCost)
+	}
+	if out.Estimate.UnitQuantity != 1000000 {
+		t.Errorf("UnitQuantity = %v, want 1000000", out.Estimate.UnitQuantity)
+	}
+}
+
+func TestEstimateCostTool_ExactAndFormatted(t *testing.T) {
+	tool := NewEstimateCost(newTestGenkit(), pricing.NewSnapshotClient(testCatalog()), nil, nil)
+
+	tests := []struct {
+		mode          string
+		wantFormatted string
+		wantErr       bool
+	}{
+		{mode: "", wantFormatted: "63.07 USD"},
+		{mode: "up", wantFormatted: "63.08 USD"},
+		{mode: "down", wantFormatted: "63.07 USD"},
+		{mode: "sideways", wantErr: true},
+	}
+	for _, tt := range tests {
+		out, err := runTool[EstimateCostOutput](t, tool, EstimateCostInput{SKUID: "CPU-TOKYO", UsageAmount: 2628000, RoundingMode: tt.mode})
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("rounding_mode %q: expected error", tt.mode)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("estimate_cost failed: %v", err)
+		}
+		if out.Estimate.EstimatedCostExact != "63.072" {
+			t.Errorf("EstimatedCostExact = %q, want 63.072", out.Estimate.EstimatedCostExact)
+		}
+		if out.Estimate.EstimatedCostFormatted != tt.wantFormatted {
+			t.Errorf("rounding_mode %q: EstimatedCostFormatted = %q, want %q", tt.mode, out.Estimate.EstimatedCostFormatted, tt.wantFormatted)
+		}
+	}
+}
+
+func TestEstimateCostTool_ConsumptionModels(t *testing.T) {
+	tool := NewEstimateCost(newTestGenkit(), pricing.NewSnapshotClient(testCatalog()), nil, nil)
+
+	// On-demand is used by default even when it is not the first model
+	out, err := runTool[EstimateCostOutput](t, tool, EstimateCostInput{SKUID: "VCPU-CUD", UsageAmount: 1000})
+	if err != nil {
+		t.Fatalf("estimate_cost failed: %v", err)
+	}
+	if out.Estimate.ConsumptionModel != "DEFAULT" || out.Estimate.EstimatedCostExact != "30" {
+		t.Errorf("default model = %s, cost %s; want DEFAULT, 30", out.Estimate.ConsumptionModel, out.Estimate.EstimatedCostExact)
+	}
+	if len(out.ModelComparison) != 0 {
+		t.Errorf("comparison returned without compare_consumption_models")
+	}
+
+	out, err = runTool[EstimateCostOutput](t, tool, EstimateCostInput{SKUID: "VCPU-CUD", UsageAmount: 1000, ConsumptionModel: "committed_use_3y", CompareModels: true})
+	if err != nil {
+		t.Fatalf("estimate_cost failed: %v", err)
+	}
+	if out.Estimate.ConsumptionModel != "COMMITTED_USE_3Y" || out.Estimate.EstimatedCostExact != "15" {
+		t.Errorf("selected model = %s, cost %s; want COMMITTED_USE_3Y, 15", out.Estimate.ConsumptionModel, out.Estimate.EstimatedCostExact)
+	}
+	if len(out.ModelComparison) != 3 {
+		t.Fatalf("got %d models in comparison, want 3", len(out.ModelComparison))
+	}
+	oneYear := out.ModelComparison[0]
+	if oneYear.ConsumptionModel != "COMMITTED_USE_1Y" || !approxEqual(oneYear.Savings, 9) || !approxEqual(oneYear.SavingsPercent, 30) {
+		t.Errorf("1y comparison = %+v, want savings 9 (30%%)", oneYear)
+	}
+
+	if _, err := runTool[EstimateCostOutput](t, tool, EstimateCostInput{SKUID: "VCPU-CUD", UsageAmount: 1, ConsumptionModel: "SPOT"}); err == nil {
+		t.Error("expected error for unavailable consumption model")
+	}
+}
+
+func TestGetSKUPriceTool_ContractPrice(t *testing.T) {
+	tool := NewGetSKUPrice(newTestGenkit(), pricing.NewSnapshotClient(testCatalog()), testContractPricer())
+
+	out, err := runTool[GetSKUPriceOutput](t, tool, GetSKUPriceInput{SKUID: "CPU-TOKYO"})
+	if err != nil {
+		t.Fatalf("get_sku_price failed: %v", err)
+	}
+	contract := out.Price.ContractPrice
+	if contract == nil || contract.BillingAccount != "0123AB-456789-CDEF01" {
+		t.Fatalf("ContractPrice = %+v", contract)
+	}
+	if contract.PriceReason != "fixed-discount" || contract.DiscountPercent != 20 {
+		t.Errorf("price reason = %s (%v%%), want fixed-discount (20%%)", contract.PriceReason, contract.DiscountPercent)
+	}
+	if !approxEqual(contract.Tiers[0].PricePerUnit, 0.0000192) || !approxEqual(out.Price.Tiers[0].PricePerUnit, 0.000024) {
+		t.Errorf("contract price %v, list price %v", contract.Tiers[0].PricePerUnit, out.Price.Tiers[0].PricePerUnit)
+	}
+
+	// Contract lookup failures do not hide the list price
+	out, err = runTool[GetSKUPriceOutput](t, tool, GetSKUPriceInput{SKUID: "REQUESTS"})
+	if err != nil {
+		t.Fatalf("get_sku_price failed: %v", err)
+	}
+	if out.Price.ContractPrice == nil || out.Price.ContractPrice.Note == "" || len(out.Price.Tiers) == 0 {
+		t.Errorf("expected list price with contract note, got %+v", out.Price)
+	}
+}
+
+func TestEstimateCostTool_ContractCost(t *testing.T) {
+	tool := NewEstimateCost(newTestGenkit(), pricing.NewSnapshotClient(testCatalog()), nil, testContractPricer())
+
+	out, err := runTool[EstimateCostOutput](t, tool, EstimateCostInput{SKUID: "CPU-TOKYO", UsageAmount: 2628000})
+	if err != nil {
+		t.Fatalf("estimate_cost failed: %v", err)
+	}
+	if out.Estimate.EstimatedCostExact != "63.072" {
+		t.Errorf("list cost = %s, want 63.072", out.Estimate.EstimatedCostExact)
+	}
+	contract := out.Estimate.Contract
+	if contract == nil || contract.EstimatedCostExact != "50.4576" {
+		t.Fatalf("Contract = %+v, want cost 50.4576", contract)
+	}
+	if !approxEqual(contract.Savings, 12.6144) || !approxEqual(contract.SavingsPercent, 20) {
+		t.Errorf("savings = %v (%v%%), want 12.6144 (20%%)", contract.Savings, contract.SavingsPercent)
+	}
+}
+
+func TestGetEstimationGuideTool_Snapshot(t *testing.T) {
+	tool := NewGetEstimationGuide(newTestGenkit(), pricing.NewSnapshotClient(testCatalog()), nil)
+
+	out, err := runTool[GetEstimationGuideOutput](t, tool, GetEstimationGuideInput{ServiceName: "cloud run"})
+	if err != nil {
+		t.Fatalf("get_estimation_guide failed: %v", err)
+	}
+	if out.Guide.ServiceID != "152E-C115-5142" {
+		t.Errorf("ServiceID = %q, want 152E-C115-5142", out.Guide.ServiceID)
+	}
+	if len(out.Guide.AvailableRegions) != 2 {
+		t.Errorf("AvailableRegions = %v, want asia-northeast1 and global", out.Guide.AvailableRegions)
+	}
+}
+
+func TestDiffCatalogTool_Live(t *testing.T) {
+	baseline := testCatalog()
+	path := filepath.Join(t.TempDir(), "baseline.json")
+	if err := pricing.SaveSnapshot(path, baseline); err != nil {
+		t.Fatalf("SaveSnapshot failed: %v", err)
+	}
+
+	current := testCatalog()
+	current.SetPrice("CPU-TOKYO", "USD", &pricing.GetPriceResponse{
+		CurrencyCode: "USD",
+		SKUPrices: []pricing.SKUPrice{{
+			ConsumptionModel: "DEFAULT",
+			Rate:             &pricing.Rate{Tiers: []pricing.Tier{{ListPrice: pricing.Money{Nanos: 18000}}}},
+		}},
+	})
+	tool := NewDiffCatalog(newTestGenkit(), pricing.NewSnapshotClient(current))
+
+	out, err := runTool[DiffCatalogOutput](t, tool, DiffCatalogInput{BaselineSnapshot: path})
+	if err != nil {
+		t.Fatalf("diff_catalog failed: %v", err)
+	}
+	if out.Diff.ChangedSKUs != 1 || out.Diff.AddedSKUs != 0 || out.Diff.RemovedSKUs != 0 {
+		t.Errorf("diff = %+v", out.Diff)
+	}
+
+	if _, err := runTool[DiffCatalogOutput](t, tool, DiffCatalogInput{}); err == nil {
+		t.Error("expected error without baseline_snapshot")
+	}
+}
+
+func TestGetCacheStatsTool(t *testing.T) {
+	cached, err := pricing.NewCachedClient(pricing.NewSnapshotClient(testCatalog()), pricing.CacheOptions{PriceTTL: time.Hour})
+	if err != nil {
+		t.Fatalf("NewCachedClient failed: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := cached.GetSKUPrice(context.Background(), "CPU-TOKYO", "USD"); err != nil {
+			t.Fatalf("GetSKUPrice failed: %v", err)
+		}
+	}
+
+	out, err := runTool[GetCacheStatsOutput](t, NewGetCacheStats(newTestGenkit(), cached), GetCacheStatsInput{})
+	if err != nil {
+		t.Fatalf("get_cache_stats failed: %v", err)
+	}
+	if !out.Enabled || out.Stats == nil || out.Stats.Hits != 1 || out.Stats.Misses != 1 || out.Stats.ValidEntries != 1 {
+		t.Errorf("output = %+v, stats = %+v", out, out.Stats)
+	}
+
+	// Snapshot mode does not cache
+	out, err = runTool[GetCacheStatsOutput](t, NewGetCacheStats(newTestGenkit(), pricing.NewSnapshotClient(testCatalog())), GetCacheStatsInput{})
+	if err != nil {
+		t.Fatalf("get_cache_stats failed: %v", err)
+	}
+	if out.Enabled || out.Stats != nil {
+		t.Errorf("output = %+v, want caching disabled", out)
+	}
+}
+
+// fakePriceListClient serves the catalog and lists the published prices of CPU-TOKYO
+type fakePriceListClient struct {
+	*pricing.SnapshotClient
+}
+
+func (c *fakePriceListClient) ListPrices(ctx context.Context, skuID string, currencyCode string, pageSize int, pageToken string) (*pricing.ListPricesResponse, error) {
+	resp := &pricing.ListPricesResponse{}
+	if skuID == "CPU-TOKYO" {
+		resp.Prices = append(resp.Prices, pricing.Price{
+			Name:         "skus/CPU-TOKYO/prices/USD",
+			CurrencyCode: "USD",
+			ValueType:    "rate",
+			Rate: &pricing.Rate{
+				UnitInfo: pricing.UnitInfo{Unit: "s", UnitQuantity: pricing.Amount{Value: "1"}},
+				Tiers:    []pricing.Tier{{StartAmount: pricing.Amount{Value: "0"}, ListPrice: pricing.Money{CurrencyCode: "USD", Nanos: 24000}}},
+			},
+		})
+	}
+	return resp, nil
+}
+
+func TestGetPriceHistoryTool(t *testing.T) {
+	tool := NewGetPriceHistory(newTestGenkit(), &fakePriceListClient{pricing.NewSnapshotClient(testCatalog())})
+
+	out, err := runTool[GetPriceHistoryOutput](t, tool, GetPriceHistoryInput{SKUID: "CPU-TOKYO"})
+	if err != nil {
+		t.Fatalf("get_price_history failed: %v", err)
+	}
+	if len(out.Prices) != 1 || out.Prices[0].Tiers[0].PricePerUnitExact != "0.000024" || out.Prices[0].ValueType != "rate" {
+		t.Errorf("Prices = %+v, want the published price", out.Prices)
+	}
+	if out.CurrencyCode != "USD" || out.Note == "" {
+		t.Errorf("output = %+v, want USD and a note that past prices are unavailable", out)
+	}
+
+	if _, err := runTool[GetPriceHistoryOutput](t, tool, GetPriceHistoryInput{}); err == nil {
+		t.Error("expected error without sku_id")
+	}
+
+	// Snapshot mode cannot list prices
+	snapshotTool := NewGetPriceHistory(newTestGenkit(), pricing.NewSnapshotClient(testCatalog()))
+	if _, err := runTool[GetPriceHistoryOutput](t, snapshotTool, GetPriceHistoryInput{SKUID: "CPU-TOKYO"}); err == nil {
+		t.Error("expected error in snapshot mode")
+	}
+}
+
+func TestEstimateCostTool_DailyAggregation(t *testing.T) {
+	catalog := testCatalog()
+	// 50,000 free requests per day, then 0.0000004 USD per request
+	catalog.SetPrice("REQUESTS", "USD", &pricing.GetPriceResponse{
+		CurrencyCode: "USD",
+		SKUPrices: []pricing.SKUPrice{{
+			ConsumptionModel: "DEFAULT",
+			Rate: &pricing.Rate{
+				UnitInfo:        pricing.UnitInfo{Unit: "count", UnitQuantity: pricing.Amount{Value: "1"}},
+				AggregationInfo: pricing.AggregationInfo{Level: "ACCOUNT", Interval: "DAILY"},
+				Tiers: []pricing.Tier{
+					{StartAmount: pricing.Amount{Value: "0"}, ListPrice: pricing.Money{CurrencyCode: "USD"}},
+					{StartAmount: pricing.Amount{Value: "50000"}, ListPrice: pricing.Money{CurrencyCode: "USD", Nanos: 400}},
+				},
+			},
+		}},
+	})
+	tool := NewEstimateCost(newTestGenkit(), pricing.NewSnapshotClient(catalog), nil, nil)
+
+	// 3,000,000 requests over 30 days = 100,000 per day, of which 50,000 are billable
+	out, err := runTool[EstimateCostOutput](t, tool, EstimateCostInput{SKUID: "REQUESTS", UsageAmount: 3000000, UsagePeriodDays: 30})
+	if err != nil {
+		t.Fatalf("estimate_cost failed: %v", err)
+	}
+	if out.Estimate.EstimatedCostExact != "0.6" {
+		t.Errorf("cost = %s, want 0.6 (30 days x 50,000 x 0.0000004)", out.Estimate.EstimatedCostExact)
+	}
+	distribution := out.Estimate.UsageDistribution
+	if distribution == nil || distribution.AggregationInterval != "DAILY" || distribution.Intervals != 30 || distribution.UsagePerInterval != 100000 {
+		t.Errorf("UsageDistribution = %+v", distribution)
+	}
+
+	// Below the daily free tier on every day of the period
+	out, err = runTool[EstimateCostOutput](t, tool, EstimateCostInput{SKUID: "REQUESTS", UsageAmount: 1200000, UsagePeriodDays: 30})
+	if err != nil {
+		t.Fatalf("estimate_cost failed: %v", err)
+	}
+	if out.Estimate.EstimatedCostExact != "0" {
+		t.Errorf("cost = %s, want 0", out.Estimate.EstimatedCostExact)
+	}
+	// The daily allowance is granted on each of the 30 days
+	if free := out.Estimate.CatalogFreeTier; free == nil || free.AmountPerInterval != 50000 || free.Amount != 1500000 || free.Applied != 1200000 {
+		t.Errorf("CatalogFreeTier = %+v", free)
+	}
+
+	// Half a day gets half of the daily free tier: (100,000 - 25,000) x 0.0000004
+	out, err = runTool[EstimateCostOutput](t, tool, EstimateCostInput{SKUID: "REQUESTS", UsageAmount: 100000, UsagePeriodDays: 0.5})
+	if err != nil {
+		t.Fatalf("estimate_cost failed: %v", err)
+	}
+	if out.Estimate.EstimatedCostExact != "0.03" {
+		t.Errorf("cost = %s, want 0.03", out.Estimate.EstimatedCostExact)
+	}
+	distribution = out.Estimate.UsageDistribution
+	if distribution == nil || distribution.Intervals != 0.5 || !strings.Contains(distribution.Note, "pro-rated") {
+		t.Errorf("UsageDistribution = %+v, want half a pro-rated interval", distribution)
+	}
+	if free := out.Estimate.CatalogFreeTier; free == nil || free.Amount != 25000 || free.Applied != 25000 {
+		t.Errorf("CatalogFreeTier = %+v", free)
+	}
+
+	if _, err := runTool[EstimateCostOutput](t, tool, EstimateCostInput{SKUID: "REQUESTS", UsageAmount: 1, UsagePeriodDays: -1}); err == nil {
+		t.Error("expected error for negative usage_period_days")
+	}
+}
+
+func TestEstimateArchitectureTool(t *testing.T) {
+	tool := NewEstimateArchitecture(newTestGenkit(), pricing.NewSnapshotClient(testCatalog()), nil, nil)
+
+	out, err := runTool[EstimateArchitectureOutput](t, tool, EstimateArchitectureInput{LineItems: []ArchitectureLineItem{
+		{SKUID: "CPU-TOKYO", UsageAmount: 2628000, ServiceName: "Cloud Run", Labels: map[string]string{"component": "api"}},
+		{SKUID: "REQUESTS", UsageAmount: 3000000, ServiceName: "Cloud Run"},
+		{SKUID: "VCPU-CUD", UsageAmount: 730, ServiceName: "Compute Engine", ConsumptionModel: "COMMITTED_USE_1Y"},
+	}})
+	if err != nil {
+		t.Fatalf("estimate_architecture failed: %v", err)
+	}
+
+	if len(out.LineItems) != 3 || out.LineItems[0].Labels["component"] != "api" || out.LineItems[2].Estimate.ConsumptionModel != "COMMITTED_USE_1Y" {
+		t.Fatalf("LineItems = %+v", out.LineItems)
+	}
+	// 63.072 + 0.4 for Cloud Run, 730 x 0.021 for Compute Engine
+	if len(out.Services) != 2 || out.Services[0].ServiceName != "Cloud Run" || out.Services[0].SubtotalExact != "63.472" || out.Services[0].LineItems != 2 {
+		t.Errorf("Services = %+v", out.Services)
+	}
+	if out.Services[1].SubtotalExact != "15.33" {
+		t.Errorf("Compute Engine subtotal = %s, want 15.33", out.Services[1].SubtotalExact)
+	}
+	if out.GrandTotalExact != "78.802" || out.GrandTotalFormatted != "78.80 USD" {
+		t.Errorf("grand total = %s (%s), want 78.802 (78.80 USD)", out.GrandTotalExact, out.GrandTotalFormatted)
+	}
+
+	if _, err := runTool[EstimateArchitectureOutput](t, tool, EstimateArchitectureInput{}); err == nil {
+		t.Error("expected error without line items")
+	}
+	if _, err := runTool[EstimateArchitectureOutput](t, tool, EstimateArchitectureInput{LineItems: []ArchitectureLineItem{
+		{SKUID: "CPU-TOKYO", UsageAmount: 1, ServiceName: "Cloud Run"},
+		{SKUID: "NO-SUCH-SKU", UsageAmount: 1, ServiceName: "Cloud Run"},
+	}}); err == nil {
+		t.Error("expected error for unknown SKU")
+	}
+}
+
+func TestEstimateArchitectureTool_ExactTotals(t *testing.T) {
+	catalog := testCatalog()
+	// 2,000,000 free requests per month, then 0.0000004 USD per request
+	catalog.SetPrice("REQUESTS", "USD", &pricing.GetPriceResponse{
+		CurrencyCode: "USD",
+		SKUPrices: []pricing.SKUPrice{{
+			ConsumptionModel: "DEFAULT",
+			Rate: &pricing.Rate{
+				UnitInfo:        pricing.UnitInfo{Unit: "count", UnitQuantity: pricing.Amount{Value: "1"}},
+				AggregationInfo: pricing.AggregationInfo{Level: "ACCOUNT", Interval: "MONTHLY"},
+				Tiers: []pricing.Tier{
+					{StartAmount: pricing.Amount{Value: "0"}, ListPrice: pricing.Money{CurrencyCode: "USD"}},
+					{StartAmount: pricing.Amount{Value: "2000000"}, ListPrice: pricing.Money{CurrencyCode: "USD", Nanos: 400}},
+				},
+			},
+		}},
+	})
+	tool := NewEstimateArchitecture(newTestGenkit(), pricing.NewSnapshotClient(catalog), nil, nil)
+
+	// Over 45 days (108/73 months), 2,000,000 x 108/73 requests are free and each cost has no
+	// finite decimal expansion
+	out, err := runTool[EstimateArchitectureOutput](t, tool, EstimateArchitectureInput{
+		UsagePeriodDays: 45,
+		LineItems: []ArchitectureLineItem{
+			{SKUID: "REQUESTS", UsageAmount: 4000000, ServiceName: "Cloud Run"},
+			{SKUID: "REQUESTS", UsageAmount: 4000000, ServiceName: "cloud run"},
+			{SKUID: "REQUESTS", UsageAmount: 4000000, ServiceName: " Cloud  Run "},
+		},
+	})
+	if err != nil {
+		t.Fatalf("estimate_architecture failed: %v", err)
+	}
+
+	cost := money.FromInt(4000000).Sub(money.FromInt(216000000).Quo(money.FromInt(73))).Mul(money.MustParse("0.0000004"))
+	if out.LineItems[0].Estimate.EstimatedCostExact != cost.String() {
+		t.Fatalf("line item cost = %s, want %s", out.LineItems[0].Estimate.EstimatedCostExact, cost)
+	}
+	// The total is the sum of the exact costs, not of their 18-digit representations
+	total := cost.Add(cost).Add(cost)
+	if rounded := money.MustParse(cost.String()).Mul(money.FromInt(3)); rounded.String() == total.String() {
+		t.Fatalf("summing rounded costs (%s) gives the exact total; the test does not tell them apart", rounded)
+	}
+	if out.GrandTotalExact != total.String() {
+		t.Errorf("grand total = %s, want %s", out.GrandTotalExact, total)
+	}
+
+	// Service names differing in case and spacing share one subtotal
+	if len(out.Services) != 1 || out.Services[0].ServiceName != "Cloud Run" || out.Services[0].LineItems != 3 || out.Services[0].SubtotalExact != total.String() {
+		t.Errorf("Services = %+v", out.Services)
+	}
+}
+
+func TestEstimateArchitectureTool_AliasedServices(t *testing.T) {
+	dataset, err := freetier.ParseDataset([]byte(`{"version": "test", "services": [{"service_name": "Cloud Run",
+		"aliases": ["run"], "scope": "account", "period": "month", "items": [
+		{"resource": "vCPU-seconds", "amount": 180000, "unit": "s", "sku_ids": ["CPU-TOKYO"]}]}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc := freetier.NewService(freetier.WithDataset(dataset), freetier.WithFreeProgramURL(""))
+	tool := NewEstimateArchitecture(newTestGenkit(), pricing.NewSnapshotClient(testCatalog()), svc, nil)
+
+	// Both line items resolve to the Cloud Run free tier, which is granted once
+	out, err := runTool[EstimateArchitectureOutput](t, tool, EstimateArchitectureInput{LineItems: []ArchitectureLineItem{
+		{SKUID: "CPU-TOKYO", UsageAmount: 150000, ServiceName: "Cloud Run"},
+		{SKUID: "CPU-TOKYO", UsageAmount: 150000, ServiceName: "run"},
+	}})
+	if err != nil {
+		t.Fatalf("estimate_architecture failed: %v", err)
+	}
+	if first, second := out.LineItems[0].Estimate, out.LineItems[1].Estimate; first.FreeTierApplied != 150000 || second.FreeTierApplied != 30000 {
+		t.Errorf("FreeTierApplied = %v and %v, want 150000 and 30000", first.FreeTierApplied, second.FreeTierApplied)
+	}
+	if len(out.FreeTierAllocations) != 1 {
+		t.Errorf("FreeTierAllocations = %+v, want one shared allowance", out.FreeTierAllocations)
+	}
+	// They also share one subtotal, reported under the canonical service name
+	if len(out.Services) != 1 || out.Services[0].ServiceName != "Cloud Run" || out.Services[0].LineItems != 2 {
+		t.Errorf("Services = %+v, want one Cloud Run subtotal", out.Services)
+	}
+}
+
+func TestEstimateArchitectureTool_SubtotalsByCanonicalService(t *testing.T) {
+	// The free tier is bound to another SKU, so neither line item gets it
+	dataset, err := freetier.ParseDataset([]byte(`{"version": "test", "services": [{"service_name": "Kubernetes Engine",
+		"aliases": ["GKE"], "scope": "account", "period": "month", "items": [
+		{"resource": "cluster management", "amount": 1, "unit": "h", "sku_ids": ["CLUSTER-FEE"]}]}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc := freetier.NewService(freetier.WithDataset(dataset), freetier.WithFreeProgramURL(""))
+	tool := NewEstimateArchitecture(newTestGenkit(), pricing.NewSnapshotClient(testCatalog()), svc, nil)
+
+	out, err := runTool[EstimateArchitectureOutput](t, tool, EstimateArchitectureInput{LineItems: []ArchitectureLineItem{
+		{SKUID: "CPU-TOKYO", UsageAmount: 100000, ServiceName: "GKE"},
+		{SKUID: "CPU-TOKYO", UsageAmount: 100000, ServiceName: "Kubernetes Engine"},
+	}})
+	if err != nil {
+		t.Fatalf("estimate_architecture failed: %v", err)
+	}
+	if len(out.Services) != 1 {
+		t.Fatalf("Services = %+v, want one Kubernetes Engine subtotal", out.Services)
+	}
+	if gke := out.Services[0]; gke.ServiceName != "Kubernetes Engine" || gke.LineItems != 2 || gke.SubtotalExact != "4.8" {
+		t.Errorf("Services[0] = %+v, want both GKE line items under Kubernetes Engine", gke)
+	}
+}
+
+func TestEstimator_SharedFreeTier(t *testing.T) {
+	e := &estimator{client: pricing.NewSnapshotClient(testCatalog())}
+	freeTier := &freetier.FreeTierInfo{
+		ServiceName: "Cloud Run",
+		Items:       []freetier.FreeTierItem{{Resource: "vCPU-seconds", Amount: 180000}},
+		Scope:       "account",
+		Period:      "month",
+	}
+
+	var items []*lineItem
+	for _, usage := range []float64{100000, 100000} {
+		item, err := e.resolve(context.Background(), EstimateCostInput{SKUID: "CPU-TOKYO", UsageAmount: usage, ServiceName: "Cloud Run"})
+		if err != nil {
+			t.Fatalf("resolve failed: %v", err)
+		}
+		item.freeTier, item.freeTierItem = freeTier, &freeTier.Items[0]
+		items = append(items, item)
+	}
+
+	// The first line item uses 100,000 of the 180,000 free vCPU-seconds, leaving 80,000 for the second
+	ledger := freetier.NewLedger()
+	if _, _, err := e.calculate(items[0], ledger); err != nil {
+		t.Fatalf("calculate failed: %v", err)
+	}
+	out, _, err := e.calculate(items[1], ledger)
+	if err != nil {
+		t.Fatalf("calculate failed: %v", err)
+	}
+	if out.Estimate.FreeTierApplied != 80000 {
+		t.Errorf("second FreeTierApplied = %v, want 80000", out.Estimate.FreeTierApplied)
+	}
+	if out.Estimate.BillableUsage != 20000 || out.Estimate.EstimatedCostExact != "0.48" {
+		t.Errorf("second estimate = billable %v, cost %s", out.Estimate.BillableUsage, out.Estimate.EstimatedCostExact)
+	}
+}
+
+func TestLedgerSessions(t *testing.T) {
+	sessions := newLedgerSessions()
+	if sessions.get("a") != sessions.get("a") {
+		t.Error("expected the same ledger for the same session")
+	}
+	if sessions.get("a") == sessions.get("b") {
+		t.Error("expected separate ledgers for different sessions")
+	}
+
+	// Unused sessions expire
+	sessions.ledgers["a"].lastUsed = time.Now().Add(-2 * sessionTTL)
+	sessions.get("b")
+	if _, ok := sessions.ledgers["a"]; ok {
+		t.Error("expected expired session to be discarded")
+	}
+}
+
+func TestParseUsagePeriod(t *testing.T) {
+	tests := []struct {
+		name     string
+		days     float64
+		wantDays string
+		wantName string
+		wantErr  bool
+	}{
+		{"", 0, "30.416666666666666667", "month", false},
+		{"Day", 0, "1", "day", false},
+		{"week", 0, "7", "week", false},
+		{"year", 0, "365", "year", false},
+		{"", 14, "14", "14 days", false},
+		{"fortnight", 0, "", "", true},
+		{"day", 14, "", "", true},
+		{"", -1, "", "", true},
+	}
+	for _, tt := range tests {
+		period, name, err := parseUsagePeriod(tt.name, tt.days)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseUsagePeriod(%q, %v) error = %v, wantErr %v", tt.name, tt.days, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && (period.Days.String() != tt.wantDays || name != tt.wantName) {
+			t.Errorf("parseUsagePeriod(%q, %v) = %s days (%s), want %s days (%s)", tt.name, tt.days, period.Days, name, tt.wantDays, tt.wantName)
+		}
+	}
+}
+
+func TestEstimator_DailyFreeTier(t *testing.T) {
+	e := &estimator{client: pricing.NewSnapshotClient(testCatalog())}
+	freeTier := &freetier.FreeTierInfo{
+		ServiceName: "Cloud Run",
+		Items:       []freetier.FreeTierItem{{Resource: "vCPU-seconds", Amount: 10000}},
+		Scope:       "account",
+		Period:      "day",
+	}
+
+	item, err := e.resolve(context.Background(), EstimateCostInput{SKUID: "CPU-TOKYO", UsageAmount: 1000000, ServiceName: "Cloud Run", UsagePeriod: "week"})
+	if err != nil {
+		t.Fatalf("resolve failed: %v", err)
+	}
+	item.freeTier, item.freeTierItem = freeTier, &freeTier.Items[0]
+
+	out, _, err := e.calculate(item, freetier.NewLedger())
+	if err != nil {
+		t.Fatalf("calculate failed: %v", err)
+	}
+	// 7 days x 10,000 free vCPU-seconds
+	if out.Estimate.FreeTierApplied != 70000 || out.Estimate.BillableUsage != 930000 {
+		t.Errorf("FreeTierApplied = %v, BillableUsage = %v, want 70000 and 930000", out.Estimate.FreeTierApplied, out.Estimate.BillableUsage)
+	}
+	if out.Estimate.UsagePeriod != "week" || out.Estimate.UsagePeriodDays != 7 {
+		t.Errorf("usage period = %q (%v days), want week (7 days)", out.Estimate.UsagePeriod, out.Estimate.UsagePeriodDays)
+	}
+	if !strings.Contains(out.Estimate.FreeTierNote, "daily allowance") {
+		t.Errorf("FreeTierNote = %q", out.Estimate.FreeTierNote)
+	}
+}
+
+func TestEstimateCostTool_UsageUnit(t *testing.T) {
+	tool := NewEstimateCost(newTestGenkit(), pricing.NewSnapshotClient(testCatalog()), nil, nil)
+
+	// VCPU-CUD is priced per hour: 7,200 seconds are 2 hours at $0.03
+	out, err := runTool[EstimateCostOutput](t, tool, EstimateCostInput{SKUID: "VCPU-CUD", UsageAmount: 7200, UsageUnit: "seconds"})
+	if err != nil {
+		t.Fatalf("estimate_cost failed: %v", err)
+	}
+	if out.Estimate.UsageAmount != 2 || out.Estimate.EstimatedCostExact != "0.06" {
+		t.Errorf("usage = %v, cost = %s, want 2 and 0.06", out.Estimate.UsageAmount, out.Estimate.EstimatedCostExact)
+	}
+	if out.Estimate.UsageConversion != "7200 seconds = 2 h" {
+		t.Errorf("UsageConversion = %q", out.Estimate.UsageConversion)
+	}
+
+	if _, err := runTool[EstimateCostOutput](t, tool, EstimateCostInput{SKUID: "VCPU-CUD", UsageAmount: 1, UsageUnit: "GiB"}); err == nil {
+		t.Error("expected an error for a usage unit that does not convert to hours")
+	}
+}
+
+func TestEstimator_FreeTierUnitConversion(t *testing.T) {
+	e := &estimator{client: pricing.NewSnapshotClient(testCatalog())}
+	freeTier := &freetier.FreeTierInfo{
+		ServiceName: "Compute Engine",
+		Items: []freetier.FreeTierItem{
+			{Resource: "vCPU-seconds", Amount: 36000, Unit: "seconds"},
+			{Resource: "GiB-seconds", Amount: 36000, Unit: "seconds"},
+		},
+		Scope:  "account",
+		Period: "month",
+	}
+
+	// 36,000 free vCPU-seconds cover 10 hours of an hourly SKU
+	item, err := e.resolve(context.Background(), EstimateCostInput{SKUID: "VCPU-CUD", UsageAmount: 100, ServiceName: "Compute Engine"})
+	if err != nil {
+		t.Fatalf("resolve failed: %v", err)
+	}
+	item.freeTier, item.freeTierItem = freeTier, &freeTier.Items[0]
+	ledger := freetier.NewLedger()
+	out, _, err := e.calculate(item, ledger)
+	if err != nil {
+		t.Fatalf("calculate failed: %v", err)
+	}
+	if out.Estimate.FreeTierApplied != 10 || out.Estimate.BillableUsage != 90 {
+		t.Errorf("FreeTierApplied = %v, BillableUsage = %v, want 10 and 90", out.Estimate.FreeTierApplied, out.Estimate.BillableUsage)
+	}
+	// The ledger tracks the allowance in the free tier's own unit
+	if report := ledger.Report(); len(report) != 1 || report[0].Used.String() != "36000" {
+		t.Errorf("ledger report = %+v, want 36000 seconds used", report)
+	}
+
+	// A memory allowance cannot be deducted from vCPU hours
+	item.freeTierItem = &freeTier.Items[1]
+	out, _, err = e.calculate(item, freetier.NewLedger())
+	if err != nil {
+		t.Fatalf("calculate failed: %v", err)
+	}
+	if out.Estimate.FreeTierApplied != 0 || !strings.Contains(out.Estimate.FreeTierNote, "not deducted") {
+		t.Errorf("FreeTierApplied = %v, note = %q, want no deduction", out.Estimate.FreeTierApplied, out.Estimate.FreeTierNote)
+	}
+}
+
+func TestEstimateCostTool_DatasetFreeTier(t *testing.T) {
+	tool := NewEstimateCost(newTestGenkit(), pricing.NewSnapshotClient(testCatalog()), freetier.NewService(), nil)
+
+	// The curated Cloud Run free tier binds the vCPU allowance to CPU SKUs by display name
+	out, err := runTool[EstimateCostOutput](t, tool, EstimateCostInput{SKUID: "CPU-TOKYO", UsageAmount: 2628000, ServiceName: "Cloud Run"})
+	if err != nil {
+		t.Fatalf("estimate_cost failed: %v", err)
+	}
+	estimate := out.Estimate
+	if estimate.FreeTierApplied != 180000 || estimate.EstimatedCostExact != "58.752" {
+		t.Errorf("FreeTierApplied = %v, cost = %s, want 180000 and 58.752", estimate.FreeTierApplied, estimate.EstimatedCostExact)
+	}
+	if estimate.FreeTierSource != freetier.SourceDataset || estimate.FreeTierMatchedBy != freetier.MatchedByPredicate {
+		t.Errorf("source = %q, matched by %q", estimate.FreeTierSource, estimate.FreeTierMatchedBy)
+	}
+	if !slices.Contains(estimate.FreeTierConditions, freetier.ConditionPerBillingAccount) {
+		t.Errorf("FreeTierConditions = %q", estimate.FreeTierConditions)
+	}
+}
+
+func TestEstimator_AmbiguousFreeTier(t *testing.T) {
+	e := &estimator{client: pricing.NewSnapshotClient(testCatalog())}
+	item, err := e.resolve(context.Background(), EstimateCostInput{SKUID: "VCPU-CUD", UsageAmount: 100})
+	if err != nil {
+		t.Fatalf("resolve failed: %v", err)
+	}
+	item.freeTier = &freetier.FreeTierInfo{ServiceName: "Compute Engine", Scope: "account", Period: "month"}
+	item.freeTierWarning = "SKU VCPU-CUD matches several free tier items (a, b) by unit; none was applied"
+
+	out, _, err := e.calculate(item, freetier.NewLedger())
+	if err != nil {
+		t.Fatalf("calculate failed: %v", err)
+	}
+	if out.Estimate.FreeTierApplied != 0 || len(out.Estimate.FreeTierWarnings) != 1 {
+		t.Errorf("FreeTierApplied = %v, warnings = %v", out.Estimate.FreeTierApplied, out.Estimate.FreeTierWarnings)
+	}
+	if !strings.HasPrefix(out.Estimate.FreeTierNote, "Free tier not applied") {
+		t.Errorf("FreeTierNote = %q", out.Estimate.FreeTierNote)
+	}
+}
+
+func TestEstimator_FreeTierRegions(t *testing.T) {
+	e := &estimator{client: pricing.NewSnapshotClient(testCatalog())}
+	freeTier := &freetier.FreeTierInfo{
+		ServiceName: "Compute Engine",
+		Items: []freetier.FreeTierItem{
+			{Resource: "vCPU-seconds", Amount: 36000, Unit: "seconds", Regions: []string{"us-west1", "us-central1", "us-east1"}},
+		},
+		Scope:  "account",
+		Period: "month",
+	}
+
+	tests := []struct {
+		region  string
+		applied float64
+		note    string
+	}{
+		{"us-central1", 10, "Free tier applied"},
+		{"us-central1-a", 10, "Free tier applied"},
+		{"asia-northeast1", 0, "only applies in us-west1, us-central1, us-east1, not in asia-northeast1"},
+		{"", 0, "the region of this usage is unknown"},
+	}
+	for _, tt := range tests {
+		item, err := e.resolve(context.Background(), EstimateCostInput{SKUID: "VCPU-CUD", UsageAmount: 100, Region: tt.region})
+		if err != nil {
+			t.Fatalf("resolve failed: %v", err)
+		}
+		item.freeTier, item.freeTierItem = freeTier, &freeTier.Items[0]
+		out, _, err := e.calculate(item, freetier.NewLedger())
+		if err != nil {
+			t.Fatalf("calculate failed: %v", err)
+		}
+		if out.Estimate.FreeTierApplied != tt.applied || !strings.Contains(out.Estimate.FreeTierNote, tt.note) {
+			t.Errorf("region %q: FreeTierApplied = %v, note = %q, want %v and %q",
+				tt.region, out.Estimate.FreeTierApplied, out.Estimate.FreeTierNote, tt.applied, tt.note)
+		}
+	}
+}
+
+func TestEstimateCostTool_FreeTierRegionFromSKU(t *testing.T) {
+	dataset, err := freetier.ParseDataset([]byte(`{"version": "test", "services": [{"service_name": "Cloud Run",
+		"scope": "account", "period": "month", "items": [
+		{"resource": "vCPU-seconds", "amount": 180000, "unit": "s", "regions": ["us-central1"]}]}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc := freetier.NewService(freetier.WithDataset(dataset), freetier.WithFreeProgramURL(""))
+	tool := NewEstimateCost(newTestGenkit(), pricing.NewSnapshotClient(testCatalog()), svc, nil)
+
+	// Without a region in the input, the SKU's region (asia-northeast1) is checked
+	out, err := runTool[EstimateCostOutput](t, tool, EstimateCostInput{SKUID: "CPU-TOKYO", UsageAmount: 1000, ServiceName: "Cloud Run"})
+	if err != nil {
+		t.Fatalf("estimate_cost failed: %v", err)
+	}
+	if out.Estimate.FreeTierApplied != 0 || !strings.Contains(out.Estimate.FreeTierNote, "not in asia-northeast1") {
+		t.Errorf("FreeTierApplied = %v, note = %q", out.Estimate.FreeTierApplied, out.Estimate.FreeTierNote)
+	}
+}
+
+func TestEstimator_FreeTierConditions(t *testing.T) {
+	e := &estimator{client: pricing.NewSnapshotClient(testCatalog())}
+	freeTier := &freetier.FreeTierInfo{
+		ServiceName: "Compute Engine",
+		Items:       []freetier.FreeTierItem{{Resource: "vCPU-hours", Amount: 10, Unit: "h"}},
+		Scope:       "account",
+		Period:      "month",
+		Conditions:  []string{freetier.ConditionNoCommittedUse},
+	}
+
+	tests := []struct {
+		model   string
+		applied float64
+		note    string
+	}{
+		{"", 10, "Free tier applied"},
+		{"COMMITTED_USE_1Y", 0, "not available with committed use discounts (consumption model COMMITTED_USE_1Y)"},
+	}
+	for _, tt := range tests {
+		item, err := e.resolve(context.Background(), EstimateCostInput{SKUID: "VCPU-CUD", UsageAmount: 100, ConsumptionModel: tt.model})
+		if err != nil {
+			t.Fatalf("resolve failed: %v", err)
+		}
+		item.freeTier, item.freeTierItem = freeTier, &freeTier.Items[0]
+		out, _, err := e.calculate(item, freetier.NewLedger())
+		if err != nil {
+			t.Fatalf("calculate failed: %v", err)
+		}
+		if out.Estimate.FreeTierApplied != tt.applied || !strings.Contains(out.Estimate.FreeTierNote, tt.note) {
+			t.Errorf("model %q: FreeTierApplied = %v, note = %q, want %v and %q",
+				tt.model, out.Estimate.FreeTierApplied, out.Estimate.FreeTierNote, tt.applied, tt.note)
+		}
+		// The conditions are reported whether or not the free tier was applied
+		if !reflect.DeepEqual(out.Estimate.FreeTierConditions, freeTier.Conditions) {
+			t.Errorf("model %q: FreeTierConditions = %q", tt.model, out.Estimate.FreeTierConditions)
+		}
+	}
+}
+
+func TestEstimateCostTool_BillingModeConditions(t *testing.T) {
+	catalog := testCatalog()
+	catalog.SKUs["152E-C115-5142"] = append(catalog.SKUs["152E-C115-5142"], pricing.SKU{
+		SKUID: "CPU-INSTANCE", DisplayName: "CPU Allocation Time (instance-based billing)", GeoTaxonomy: pricing.GeoTaxonomy{Type: "GLOBAL"},
+	})
+	price, err := pricing.NewSnapshotClient(catalog).GetSKUPrice(context.Background(), "CPU-TOKYO", "USD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	catalog.SetPrice("CPU-INSTANCE", "USD", price)
+
+	dataset, err := freetier.ParseDataset([]byte(`{"version": "test", "services": [{"service_name": "Cloud Run",
+		"scope": "account", "period": "month", "conditions": ["` + freetier.ConditionNoInstanceBased + `"], "items": [
+		{"resource": "vCPU-seconds", "amount": 180000, "unit": "s", "match": {"display_name_contains": ["CPU"]}}]}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc := freetier.NewService(freetier.WithDataset(dataset), freetier.WithFreeProgramURL(""))
+	tool := NewEstimateCost(newTestGenkit(), pricing.NewSnapshotClient(catalog), svc, nil)
+
+	out, err := runTool[EstimateCostOutput](t, tool, EstimateCostInput{SKUID: "CPU-TOKYO", UsageAmount: 100000, ServiceName: "Cloud Run"})
+	if err != nil {
+		t.Fatalf("estimate_cost failed: %v", err)
+	}
+	if out.Estimate.FreeTierApplied != 100000 {
+		t.Errorf("request-based FreeTierApplied = %v, want 100000", out.Estimate.FreeTierApplied)
+	}
+
+	out, err = runTool[EstimateCostOutput](t, tool, EstimateCostInput{SKUID: "CPU-INSTANCE", UsageAmount: 100000, ServiceName: "Cloud Run"})
+	if err != nil {
+		t.Fatalf("estimate_cost failed: %v", err)
+	}
+	if out.Estimate.FreeTierApplied != 0 || !strings.Contains(out.Estimate.FreeTierNote, "does not apply to instance-based billing") {
+		t.Errorf("instance-based FreeTierApplied = %v, note = %q, want the free tier excluded",
+			out.Estimate.FreeTierApplied, out.Estimate.FreeTierNote)
+	}
+}
+
+func TestBillingModeOf(t *testing.T) {
+	tests := map[string]billingMode{
+		"CPU Allocation Time (instance-based billing)": billingModeInstance,
+		"Services CPU Always-on":                       billingModeInstance,
+		"CPU Allocation Time (request-based billing)":  billingModeRequest,
+		"Requests":                     billingModeRequest,
+		"CPU Allocation Time (tier 1)": "",
+		"Network Requests Egress":      "",
+	}
+	for name, want := range tests {
+		if got := billingModeOf(name); got != want {
+			t.Errorf("billingModeOf(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestEstimator_CompareModelsWithFreeTier(t *testing.T) {
+	e := &estimator{client: pricing.NewSnapshotClient(testCatalog())}
+	freeTier := &freetier.FreeTierInfo{
+		ServiceName: "Compute Engine",
+		Items:       []freetier.FreeTierItem{{Resource: "vCPU-hours", Amount: 10, Unit: "h"}},
+		Scope:       "account",
+		Period:      "month",
+		Conditions:  []string{freetier.ConditionNoCommittedUse},
+	}
+
+	// Whichever model is selected, each model is priced after its own free tier deduction
+	for _, model := range []string{"", "COMMITTED_USE_1Y"} {
+		item, err := e.resolve(context.Background(), EstimateCostInput{SKUID: "VCPU-CUD", UsageAmount: 100, ConsumptionModel: model, CompareModels: true})
+		if err != nil {
+			t.Fatalf("resolve failed: %v", err)
+		}
+		item.freeTier, item.freeTierItem = freeTier, &freeTier.Items[0]
+		out, _, err := e.calculate(item, freetier.NewLedger())
+		if err != nil {
+			t.Fatalf("calculate failed: %v", err)
+		}
+
+		want := map[string]struct {
+			free    float64
+			cost    string
+			savings float64
+		}{
+			"DEFAULT":          {10, "2.7", 0},  // 90h x 0.03
+			"COMMITTED_USE_1Y": {0, "2.1", 0.6}, // 100h x 0.021: the free tier is not available with committed use
+			"COMMITTED_USE_3Y": {0, "1.5", 1.2}, // 100h x 0.015
+		}
+		for _, got := range out.ModelComparison {
+			w := want[got.ConsumptionModel]
+			if got.FreeTierApplied != w.free || got.BillableUsage != 100-w.free || got.EstimatedCostExact != w.cost || !approxEqual(got.Savings, w.savings) {
+				t.Errorf("model %q selected: comparison %+v, want free tier %v, cost %s, savings %v",
+					model, got, w.free, w.cost, w.savings)
+			}
+		}
+	}
+}
+
+func TestEstimator_CatalogFreeTierReconciliation(t *testing.T) {
+	e := &estimator{client: pricing.NewSnapshotClient(testCatalog())}
+
+	// REQUESTS prices the first 2,000,000 requests at zero
+	tests := []struct {
+		name    string
+		item    freetier.FreeTierItem
+		applied float64
+		cost    string
+		note    string
+	}{
+		{
+			name:    "Same allowance",
+			item:    freetier.FreeTierItem{Resource: "requests", Amount: 2000000, Unit: "count"},
+			applied: 0,
+			cost:    "0.4",
+			note:    "not deducted: the catalog already prices the first 2000000 count of this SKU at zero",
+		},
+		{
+			name:    "Smaller allowance",
+			item:    freetier.FreeTierItem{Resource: "requests", Amount: 500000, Unit: "count"},
+			applied: 0,
+			cost:    "0.4",
+			note:    "more than this free tier (500000 count), so only the catalog allowance applies",
+		},
+		{
+			// Only the 500,000 requests above the catalog allowance are deducted, not all 2,500,000
+			name:    "Larger allowance",
+			item:    freetier.FreeTierItem{Resource: "requests", Amount: 2500000, Unit: "count"},
+			applied: 500000,
+			cost:    "0.2",
+			note:    "so only the 500000 count above it are deducted",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			item, err := e.resolve(context.Background(), EstimateCostInput{SKUID: "REQUESTS", UsageAmount: 3000000})
+			if err != nil {
+				t.Fatalf("resolve failed: %v", err)
+			}
+			freeTier := &freetier.FreeTierInfo{ServiceName: "Cloud Run", Items: []freetier.FreeTierItem{tt.item}, Scope: "account", Period: "month"}
+			item.freeTier, item.freeTierItem = freeTier, &freeTier.Items[0]
+
+			out, _, err := e.calculate(item, freetier.NewLedger())
+			if err != nil {
+				t.Fatalf("calculate failed: %v", err)
+			}
+			if out.Estimate.FreeTierApplied != tt.applied || out.Estimate.EstimatedCostExact != tt.cost {
+				t.Errorf("FreeTierApplied = %v, cost = %s, want %v and %s",
+					out.Estimate.FreeTierApplied, out.Estimate.EstimatedCostExact, tt.applied, tt.cost)
+			}
+			if !strings.Contains(out.Estimate.FreeTierNote, tt.note) {
+				t.Errorf("FreeTierNote = %q, want it to contain %q", out.Estimate.FreeTierNote, tt.note)
+			}
+		})
+	}
+}
+
+func TestEstimator_CatalogFreeTierReconciliationOverPeriod(t *testing.T) {
+	catalog := testCatalog()
+	// 3,650,000 free requests per month, then 0.0000004 USD per request
+	catalog.SetPrice("REQUESTS", "USD", &pricing.GetPriceResponse{
+		CurrencyCode: "USD",
+		SKUPrices: []pricing.SKUPrice{{
+			ConsumptionModel: "DEFAULT",
+			Rate: &pricing.Rate{
+				UnitInfo:        pricing.UnitInfo{Unit: "count", UnitQuantity: pricing.Amount{Value: "1"}},
+				AggregationInfo: pricing.AggregationInfo{Level: "ACCOUNT", Interval: "MONTHLY"},
+				Tiers: []pricing.Tier{
+					{StartAmount: pricing.Amount{Value: "0"}, ListPrice: pricing.Money{CurrencyCode: "USD"}},
+					{StartAmount: pricing.Amount{Value: "3650000"}, ListPrice: pricing.Money{CurrencyCode: "USD", Nanos: 400}},
+				},
+			},
+		}},
+	})
+	e := &estimator{client: pricing.NewSnapshotClient(catalog)}
+
+	// 120,000 requests a day is the same allowance over a 730-hour month (x 365/12 days) and over a week
+	for _, period := range []float64{0, 7} {
+		item, err := e.resolve(context.Background(), EstimateCostInput{SKUID: "REQUESTS", UsageAmount: 5000000, UsagePeriodDays: period})
+		if err != nil {
+			t.Fatalf("resolve failed: %v", err)
+		}
+		freeTier := &freetier.FreeTierInfo{
+			ServiceName: "Cloud Run",
+			Items:       []freetier.FreeTierItem{{Resource: "requests", Amount: 120000, Unit: "count"}},
+			Scope:       "account",
+			Period:      "day",
+		}
+		item.freeTier, item.freeTierItem = freeTier, &freeTier.Items[0]
+
+		out, _, err := e.calculate(item, freetier.NewLedger())
+		if err != nil {
+			t.Fatalf("calculate failed: %v", err)
+		}
+		if out.Estimate.FreeTierApplied != 0 || !strings.Contains(out.Estimate.FreeTierNote, "not deducted: the catalog already prices") {
+			t.Errorf("period %v days: FreeTierApplied = %v, note = %q, want the catalog allowance only",
+				period, out.Estimate.FreeTierApplied, out.Estimate.FreeTierNote)
+		}
+	}
+}
+
+func TestEstimator_FractionalFreeTierNote(t *testing.T) {
+	e := &estimator{client: pricing.NewSnapshotClient(testCatalog())}
+	item, err := e.resolve(context.Background(), EstimateCostInput{SKUID: "VCPU-CUD", UsageAmount: 100})
+	if err != nil {
+		t.Fatalf("resolve failed: %v", err)
+	}
+	freeTier := &freetier.FreeTierInfo{
+		ServiceName: "Compute Engine",
+		Items:       []freetier.FreeTierItem{{Resource: "vCPU-hours", Amount: 0.5, Unit: "h"}},
+		Scope:       "account",
+		Period:      "month",
+	}
+	item.freeTier, item.freeTierItem = freeTier, &freeTier.Items[0]
+
+	out, _, err := e.calculate(item, freetier.NewLedger())
+	if err != nil {
+		t.Fatalf("calculate failed: %v", err)
+	}
+	if out.Estimate.FreeTierApplied != 0.5 || !strings.HasPrefix(out.Estimate.FreeTierNote, "Free tier applied: 0.5 vCPU-hours") {
+		t.Errorf("FreeTierApplied = %v, note = %q", out.Estimate.FreeTierApplied, out.Estimate.FreeTierNote)
+	}
+}
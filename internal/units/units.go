@@ -0,0 +1,275 @@
+// Package units parses the unit-of-measure codes used by the Cloud Billing Catalog
+// (e.g. "h", "GiBy", "GiBy.mo", "count") and converts amounts between compatible units.
+//
+// Billing unit codes follow UCUM conventions: "By" is a byte, binary prefixes such as "Gi"
+// multiply by powers of 1024 and decimal prefixes such as "G" by powers of 1000, and units
+// are combined with "." (product) and "/" (quotient). Annotations in curly braces such as
+// "{request}" denote dimensionless counts.
+package units
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nozomi-koborinai/gcp-cost-mcp-server/internal/money"
+)
+
+// Dimension is the physical dimension of a unit, as exponents of information (bytes) and time (seconds).
+// Counts are dimensionless.
+type Dimension struct {
+	Information int
+	Time        int
+}
+
+// String returns a readable form of the dimension, e.g. "information·time"
+func (d Dimension) String() string {
+	var parts []string
+	for _, p := range []struct {
+		name string
+		exp  int
+	}{{"information", d.Information}, {"time", d.Time}} {
+		switch {
+		case p.exp == 1:
+			parts = append(parts, p.name)
+		case p.exp != 0:
+			parts = append(parts, fmt.Sprintf("%s^%d", p.name, p.exp))
+		}
+	}
+	if len(parts) == 0 {
+		return "count"
+	}
+	return strings.Join(parts, "·")
+}
+
+// Unit is a parsed unit of measure
+type Unit struct {
+	// Code is the unit code the unit was parsed from
+	Code string
+	// Factor converts an amount in this unit to the base unit of its dimension (bytes, seconds, counts)
+	Factor    money.Decimal
+	Dimension Dimension
+}
+
+// String returns the unit code
+func (u Unit) String() string {
+	return u.Code
+}
+
+// Compatible reports whether amounts can be converted between u and other
+func (u Unit) Compatible(other Unit) bool {
+	return u.Dimension == other.Dimension
+}
+
+// Mul returns the product of two units, e.g. GiBy times mo is GiBy.mo
+func (u Unit) Mul(other Unit) Unit {
+	return Unit{
+		Code:   u.Code + "." + other.Code,
+		Factor: u.Factor.Mul(other.Factor),
+		Dimension: Dimension{
+			Information: u.Dimension.Information + other.Dimension.Information,
+			Time:        u.Dimension.Time + other.Dimension.Time,
+		},
+	}
+}
+
+// HoursPerMonth is the month length used by the Billing Catalog for "mo"
+const HoursPerMonth = 730
+
+// atom is a unit without prefix
+type atom struct {
+	factor    money.Decimal
+	dimension Dimension
+	// prefixable reports whether SI and binary prefixes may be applied
+	prefixable bool
+}
+
+// Dimensions of the units used by the Billing Catalog
+var (
+	Count        = Dimension{}
+	Data         = Dimension{Information: 1}
+	Duration     = Dimension{Time: 1}
+	DataDuration = Dimension{Information: 1, Time: 1}
+)
+
+// atoms are the units the Billing Catalog combines into unit codes
+var atoms = map[string]atom{
+	"By":    {money.FromInt(1), Data, true},
+	"bit":   {money.MustParse("0.125"), Data, true},
+	"s":     {money.FromInt(1), Duration, false},
+	"min":   {money.FromInt(60), Duration, false},
+	"h":     {money.FromInt(3600), Duration, false},
+	"d":     {money.FromInt(86400), Duration, false},
+	"mo":    {money.FromInt(HoursPerMonth * 3600), Duration, false},
+	"a":     {money.FromInt(12 * HoursPerMonth * 3600), Duration, false},
+	"1":     {money.FromInt(1), Count, false},
+	"count": {money.FromInt(1), Count, false},
+}
+
+// prefixes are the decimal and binary prefixes applicable to information units
+var prefixes = map[string]money.Decimal{
+	"k":  money.FromInt(1000),
+	"M":  money.FromInt(1000 * 1000),
+	"G":  money.FromInt(1000 * 1000 * 1000),
+	"T":  money.FromInt(1000 * 1000 * 1000 * 1000),
+	"P":  money.FromInt(1000 * 1000 * 1000 * 1000 * 1000),
+	"Ki": money.FromInt(1 << 10),
+	"Mi": money.FromInt(1 << 20),
+	"Gi": money.FromInt(1 << 30),
+	"Ti": money.FromInt(1 << 40),
+	"Pi": money.FromInt(1 << 50),
+}
+
+// aliases map unit names used in documentation to unit codes. Google Cloud pricing documentation
+// defines GB and TB as binary units (2^30 and 2^40 bytes), so they map to GiBy and TiBy.
+var aliases = map[string]string{
+	"seconds":    "s",
+	"second":     "s",
+	"sec":        "s",
+	"minutes":    "min",
+	"minute":     "min",
+	"hours":      "h",
+	"hour":       "h",
+	"hr":         "h",
+	"days":       "d",
+	"day":        "d",
+	"months":     "mo",
+	"month":      "mo",
+	"years":      "a",
+	"year":       "a",
+	"yr":         "a",
+	"bytes":      "By",
+	"byte":       "By",
+	"b":          "By",
+	"kb":         "KiBy",
+	"kib":        "KiBy",
+	"mb":         "MiBy",
+	"mib":        "MiBy",
+	"gb":         "GiBy",
+	"gib":        "GiBy",
+	"tb":         "TiBy",
+	"tib":        "TiBy",
+	"pb":         "PiBy",
+	"pib":        "PiBy",
+	"requests":   "count",
+	"request":    "count",
+	"operations": "count",
+	"operation":  "count",
+	"calls":      "count",
+	"units":      "count",
+}
+
+// Parse parses a Billing unit code or a common unit name (e.g. "GiB", "hours")
+func Parse(code string) (Unit, error) {
+	trimmed := strings.TrimSpace(code)
+	if trimmed == "" {
+		return Unit{}, fmt.Errorf("empty unit code")
+	}
+	if alias, ok := aliases[strings.ToLower(trimmed)]; ok {
+		u, err := parseExpression(alias)
+		if err != nil {
+			return Unit{}, err
+		}
+		u.Code = trimmed
+		return u, nil
+	}
+
+	u, err := parseExpression(trimmed)
+	if err != nil {
+		return Unit{}, fmt.Errorf("invalid unit code %q: %w", code, err)
+	}
+	return u, nil
+}
+
+// MustParse is like Parse but panics on error. It is intended for constants.
+func MustParse(code string) Unit {
+	u, err := Parse(code)
+	if err != nil {
+		panic(err)
+	}
+	return u
+}
+
+// parseExpression parses a product/quotient of terms such as "GiBy.mo" or "By/s"
+func parseExpression(code string) (Unit, error) {
+	result := Unit{Code: code, Factor: money.FromInt(1)}
+
+	sign := 1
+	start := 0
+	for i := 0; i <= len(code); i++ {
+		if i < len(code) && code[i] != '.' && code[i] != '/' {
+			continue
+		}
+
+		term, err := parseTerm(code[start:i])
+		if err != nil {
+			return Unit{}, err
+		}
+		if sign > 0 {
+			result.Factor = result.Factor.Mul(term.factor)
+		} else {
+			result.Factor = result.Factor.Quo(term.factor)
+		}
+		result.Dimension.Information += sign * term.dimension.Information
+		result.Dimension.Time += sign * term.dimension.Time
+
+		if i < len(code) && code[i] == '/' {
+			sign = -1
+		}
+		start = i + 1
+	}
+	return result, nil
+}
+
+// parseTerm parses a single, possibly prefixed unit such as "GiBy", "h" or "{request}"
+func parseTerm(term string) (atom, error) {
+	if term == "" {
+		return atom{}, fmt.Errorf("empty term")
+	}
+
+	// Annotations denote counts of something, e.g. "{request}" or "10^6.{request}"
+	if strings.HasPrefix(term, "{") && strings.HasSuffix(term, "}") {
+		return atoms["1"], nil
+	}
+	if strings.HasPrefix(term, "10^") {
+		var exp int
+		if _, err := fmt.Sscanf(term, "10^%d", &exp); err != nil || exp < 0 || exp > 18 {
+			return atom{}, fmt.Errorf("invalid power of ten %q", term)
+		}
+		factor := money.FromInt(1)
+		for range exp {
+			factor = factor.Mul(money.FromInt(10))
+		}
+		return atom{factor: factor}, nil
+	}
+
+	if a, ok := atoms[term]; ok {
+		return a, nil
+	}
+	for prefix, factor := range prefixes {
+		if a, ok := atoms[strings.TrimPrefix(term, prefix)]; ok && strings.HasPrefix(term, prefix) && a.prefixable {
+			return atom{factor: a.factor.Mul(factor), dimension: a.dimension, prefixable: false}, nil
+		}
+	}
+	return atom{}, fmt.Errorf("unknown unit %q", term)
+}
+
+// Convert converts an amount from one unit to another compatible unit
+func Convert(amount money.Decimal, from, to Unit) (money.Decimal, error) {
+	if !from.Compatible(to) {
+		return money.Zero, fmt.Errorf("cannot convert %s (%s) to %s (%s)", from, from.Dimension, to, to.Dimension)
+	}
+	return amount.Mul(from.Factor).Quo(to.Factor), nil
+}
+
+// ConvertCode parses the unit codes and converts an amount between them
+func ConvertCode(amount money.Decimal, from, to string) (money.Decimal, error) {
+	fromUnit, err := Parse(from)
+	if err != nil {
+		return money.Zero, err
+	}
+	toUnit, err := Parse(to)
+	if err != nil {
+		return money.Zero, err
+	}
+	return Convert(amount, fromUnit, toUnit)
+}
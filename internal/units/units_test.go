@@ -0,0 +1,110 @@
+package units
+
+import (
+	"testing"
+
+	"github.com/nozomi-koborinai/gcp-cost-mcp-server/internal/money"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		code      string
+		factor    string
+		dimension Dimension
+	}{
+		{"s", "1", Duration},
+		{"h", "3600", Duration},
+		{"mo", "2628000", Duration},
+		{"By", "1", Data},
+		{"GiBy", "1073741824", Data},
+		{"GBy", "1000000000", Data},
+		{"TiBy", "1099511627776", Data},
+		{"bit", "0.125", Data},
+		{"GiBy.s", "1073741824", DataDuration},
+		{"GiBy.mo", "2821793513472000", DataDuration},
+		{"GiBy/s", "1073741824", Dimension{Information: 1, Time: -1}},
+		{"count", "1", Count},
+		{"1", "1", Count},
+		{"{request}", "1", Count},
+		{"10^6.{request}", "1000000", Count},
+		// Common names used in documentation
+		{"seconds", "1", Duration},
+		{"hours", "3600", Duration},
+		{"GiB", "1073741824", Data},
+		{"GB", "1073741824", Data},
+		{"TiB", "1099511627776", Data},
+		{"requests", "1", Count},
+	}
+	for _, tt := range tests {
+		u, err := Parse(tt.code)
+		if err != nil {
+			t.Errorf("Parse(%q) error: %v", tt.code, err)
+			continue
+		}
+		if u.Factor.Cmp(money.MustParse(tt.factor)) != 0 {
+			t.Errorf("Parse(%q).Factor = %s, want %s", tt.code, u.Factor, tt.factor)
+		}
+		if u.Dimension != tt.dimension {
+			t.Errorf("Parse(%q).Dimension = %s, want %s", tt.code, u.Dimension, tt.dimension)
+		}
+		if u.Code != tt.code {
+			t.Errorf("Parse(%q).Code = %q", tt.code, u.Code)
+		}
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for _, code := range []string{"", "USD", "GiBy.", "Gis", "10^x", "million"} {
+		if _, err := Parse(code); err == nil {
+			t.Errorf("Parse(%q) succeeded, want error", code)
+		}
+	}
+}
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		amount   string
+		from, to string
+		want     string
+	}{
+		{"1", "h", "s", "3600"},
+		{"7200", "s", "h", "2"},
+		{"1", "TiBy", "GiBy", "1024"},
+		{"1", "TB", "GiBy", "1024"},
+		{"1536", "MiBy", "GiBy", "1.5"},
+		{"1", "GiBy.mo", "GiBy.h", "730"},
+		{"1", "GiBy.d", "GiBy.mo", "0.032876712328767123"},
+		{"3", "mo", "h", "2190"},
+		{"5", "requests", "count", "5"},
+		{"8", "bit", "By", "1"},
+	}
+	for _, tt := range tests {
+		got, err := ConvertCode(money.MustParse(tt.amount), tt.from, tt.to)
+		if err != nil {
+			t.Errorf("ConvertCode(%s, %s, %s) error: %v", tt.amount, tt.from, tt.to, err)
+			continue
+		}
+		if got.String() != tt.want {
+			t.Errorf("ConvertCode(%s, %s, %s) = %s, want %s", tt.amount, tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestConvert_Incompatible(t *testing.T) {
+	for _, pair := range [][2]string{{"s", "GiBy.s"}, {"GiBy", "h"}, {"count", "By"}, {"GiBy", "GiBy.mo"}} {
+		if _, err := ConvertCode(money.FromInt(1), pair[0], pair[1]); err == nil {
+			t.Errorf("ConvertCode(1, %s, %s) succeeded, want error", pair[0], pair[1])
+		}
+	}
+}
+
+func TestUnit_Mul(t *testing.T) {
+	u := MustParse("GiB").Mul(MustParse("mo"))
+	if u.Dimension != DataDuration {
+		t.Errorf("Dimension = %s, want %s", u.Dimension, DataDuration)
+	}
+	got, err := Convert(money.FromInt(1), u, MustParse("GiBy.mo"))
+	if err != nil || got.String() != "1" {
+		t.Errorf("Convert(1 GiB.mo, GiBy.mo) = %s, %v, want 1", got, err)
+	}
+}
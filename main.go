@@ -11,20 +11,42 @@
 //
 // Authentication:
 //
-//	Uses Application Default Credentials (ADC). Set up with:
+//	Uses Application Default Credentials (ADC) by default. Set up with:
 //	  gcloud auth application-default login
+//	API keys, explicit key files and impersonation can be configured instead (see below).
+//
+// Configuration (environment variables):
+//
+//	GCP_COST_BILLING_API_ENDPOINT         Cloud Billing API base URL (e.g. a local fake or proxy mirror)
+//	GCP_COST_API_KEY                      Authenticate with an API key instead of ADC
+//	GCP_COST_CREDENTIALS_FILE             Authenticate with an explicit service account JSON key
+//	GCP_COST_IMPERSONATE_SERVICE_ACCOUNT  Impersonate this service account (comma-separated delegation chain)
+//	GCP_COST_QUOTA_PROJECT                Project used for API quota and billing
+//	GCP_COST_CACHE_DIR                    Directory for the persistent catalog cache
+//	GCP_COST_CACHE_DISABLED               Set to "true" to disable catalog caching
+//	GCP_COST_CACHE_SERVICES_TTL           Cache TTL for the service list (e.g. "24h")
+//	GCP_COST_CACHE_SKUS_TTL               Cache TTL for SKU lists (e.g. "24h")
+//	GCP_COST_CACHE_PRICES_TTL             Cache TTL for SKU prices (e.g. "6h")
+//	GCP_COST_BILLING_ACCOUNT              Billing account ID whose contract prices are shown next to list prices
+//	GCP_COST_SNAPSHOT                     Serve the catalog from this snapshot file instead of the live API
 //
 // Usage:
 //
-//	go run main.go
+//	go run .                 Start the MCP server (stdio)
+//	go run . snapshot -h     Crawl the catalog into a snapshot file for GCP_COST_SNAPSHOT
+//	go run . diff -h         Report price changes between snapshots or against the live catalog
 package main
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
 	"syscall"
+	"time"
 
 	"github.com/firebase/genkit/go/ai"
 	"github.com/firebase/genkit/go/genkit"
@@ -44,17 +66,37 @@ func main() {
 	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer cancel()
 
+	// Subcommands
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "snapshot":
+			if err := runSnapshotCommand(ctx, os.Args[2:]); err != nil {
+				log.Fatalf("snapshot: %v", err)
+			}
+			return
+		case "diff":
+			if err := runDiffCommand(ctx, os.Args[2:]); err != nil {
+				log.Fatalf("diff: %v", err)
+			}
+			return
+		}
+	}
+
 	// Initialize Genkit
 	g := genkit.Init(ctx)
 
-	// Create Pricing API client with ADC
-	pricingClient, err := pricing.NewClient(ctx)
+	// Create the pricing client (live API or offline snapshot)
+	pricingClient, cleanup, err := newPricingClient(ctx)
 	if err != nil {
-		log.Fatalf("Failed to create Pricing API client: %v", err)
+		log.Fatalf("Failed to create pricing client: %v", err)
 	}
+	defer cleanup()
+
+	// Contract pricing for the configured billing account (optional)
+	contractPricer := newContractPricer(pricingClient)
 
 	// Create FreeTierService for free tier information retrieval
-	freeTierService := freetier.NewService()
+	freeTierService := freetier.NewService(freeTierOptionsFromEnv()...)
 	log.Println("FreeTierService initialized with 24h cache TTL")
 
 	// Define tools
@@ -62,8 +104,12 @@ func main() {
 		tools.NewGetEstimationGuide(g, pricingClient, freeTierService), // Should be called first to understand requirements
 		tools.NewListServices(g, pricingClient),
 		tools.NewListSKUs(g, pricingClient),
-		tools.NewGetSKUPrice(g, pricingClient),
-		tools.NewEstimateCost(g, pricingClient, freeTierService), // Now includes free tier auto-apply
+		tools.NewGetSKUPrice(g, pricingClient, contractPricer),
+		tools.NewEstimateCost(g, pricingClient, freeTierService, contractPricer), // Now includes free tier auto-apply
+		tools.NewEstimateArchitecture(g, pricingClient, freeTierService, contractPricer),
+		tools.NewDiffCatalog(g, pricingClient),
+		tools.NewGetPriceHistory(g, pricingClient),
+		tools.NewGetCacheStats(g, pricingClient),
 	}
 
 	// Log registered tools
@@ -88,3 +134,146 @@ func main() {
 
 	log.Println("Server shutdown complete.")
 }
+
+// newPricingClient creates the pricing client selected by the environment: an offline
+// catalog snapshot when GCP_COST_SNAPSHOT is set, otherwise the (cached) Cloud Billing API.
+// The returned cleanup function must be called on shutdown.
+func newPricingClient(ctx context.Context) (pricing.PricingClient, func(), error) {
+	if path := os.Getenv("GCP_COST_SNAPSHOT"); path != "" {
+		snapshot, err := pricing.LoadSnapshot(path)
+		if err != nil {
+			return nil, nil, err
+		}
+		log.Printf("Offline mode: serving catalog snapshot %q (created %s, %d services)",
+			path, snapshot.CreatedAt.Format(time.RFC3339), len(snapshot.Services))
+		return pricing.NewSnapshotClient(snapshot), func() {}, nil
+	}
+
+	// Create Pricing API client (ADC unless configured otherwise)
+	apiClient, err := pricing.NewClient(ctx, clientOptionsFromEnv()...)
+	if err != nil {
+		return nil, nil, err
+	}
+	if envBool("GCP_COST_CACHE_DISABLED") {
+		return apiClient, func() {}, nil
+	}
+
+	// Wrap the client with the persistent catalog cache
+	cacheOpts := pricing.DefaultCacheOptions()
+	if dir := os.Getenv("GCP_COST_CACHE_DIR"); dir != "" {
+		cacheOpts.Dir = dir
+	}
+	cacheOpts.ServicesTTL = envDuration("GCP_COST_CACHE_SERVICES_TTL", cacheOpts.ServicesTTL)
+	cacheOpts.SKUsTTL = envDuration("GCP_COST_CACHE_SKUS_TTL", cacheOpts.SKUsTTL)
+	cacheOpts.PriceTTL = envDuration("GCP_COST_CACHE_PRICES_TTL", cacheOpts.PriceTTL)
+
+	cachedClient, err := pricing.NewCachedClient(apiClient, cacheOpts)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create catalog cache: %w", err)
+	}
+	log.Printf("Catalog cache initialized (dir: %q)", cacheOpts.Dir)
+
+	return cachedClient, func() {
+		log.Printf("Catalog cache stats: %+v", cachedClient.GetCacheStats())
+	}, nil
+}
+
+// newContractPricer returns a ContractPricer for GCP_COST_BILLING_ACCOUNT, or nil when no
+// billing account is configured or the pricing client cannot read billing account prices
+func newContractPricer(client pricing.PricingClient) *pricing.ContractPricer {
+	billingAccount := os.Getenv("GCP_COST_BILLING_ACCOUNT")
+	if billingAccount == "" {
+		return nil
+	}
+
+	accountClient, ok := client.(pricing.AccountPricingClient)
+	if !ok {
+		log.Printf("Warning: GCP_COST_BILLING_ACCOUNT is ignored: contract pricing is not available with this pricing client")
+		return nil
+	}
+
+	contractPricer := pricing.NewContractPricer(accountClient, billingAccount)
+	log.Printf("Contract pricing enabled for billing account %s", contractPricer.BillingAccount())
+	return contractPricer
+}
+
+// clientOptionsFromEnv builds Pricing API client options from environment variables
+func clientOptionsFromEnv() []pricing.Option {
+	var opts []pricing.Option
+	if v := os.Getenv("GCP_COST_BILLING_API_ENDPOINT"); v != "" {
+		opts = append(opts, pricing.WithBaseURL(v))
+	}
+	if v := os.Getenv("GCP_COST_API_KEY"); v != "" {
+		opts = append(opts, pricing.WithAPIKey(v))
+	}
+	if v := os.Getenv("GCP_COST_CREDENTIALS_FILE"); v != "" {
+		opts = append(opts, pricing.WithCredentialsFile(v))
+	}
+	if v := os.Getenv("GCP_COST_IMPERSONATE_SERVICE_ACCOUNT"); v != "" {
+		// The last entry is the target; any preceding entries are delegates
+		chain := strings.Split(v, ",")
+		for i := range chain {
+			chain[i] = strings.TrimSpace(chain[i])
+		}
+		opts = append(opts, pricing.WithImpersonation(chain[len(chain)-1], chain[:len(chain)-1]...))
+	}
+	if v := os.Getenv("GCP_COST_QUOTA_PROJECT"); v != "" {
+		opts = append(opts, pricing.WithQuotaProject(v))
+	}
+	return opts
+}
+
+// freeTierOptionsFromEnv builds free tier service options from environment variables.
+// GCP_COST_FREE_TIER_URL_MAP replaces documentation search with a fixed map of page URLs, and
+// GCP_COST_FREE_TIER_PAGES_DIR serves saved pages from a directory instead of fetching them.
+// GCP_COST_FREE_PROGRAM_PAGE reads the free program catalog from a saved copy of the page.
+func freeTierOptionsFromEnv() []freetier.ServiceOption {
+	var opts []freetier.ServiceOption
+	if v := os.Getenv("GCP_COST_FREE_PROGRAM_PAGE"); v != "" {
+		program, err := freetier.LoadFreeProgramFile(v)
+		if err != nil {
+			log.Printf("Warning: GCP_COST_FREE_PROGRAM_PAGE is ignored: %v", err)
+		} else {
+			opts = append(opts, freetier.WithFreeProgram(program))
+			log.Printf("Free program catalog of %d products loaded from %q", len(program.Products), v)
+		}
+	}
+	urlMap := os.Getenv("GCP_COST_FREE_TIER_URL_MAP")
+	if urlMap != "" {
+		finder, err := freetier.LoadStaticPageFinder(urlMap)
+		if err != nil {
+			log.Printf("Warning: GCP_COST_FREE_TIER_URL_MAP is ignored: %v", err)
+		} else {
+			opts = append(opts, freetier.WithPageFinder(finder))
+		}
+	}
+	if dir := os.Getenv("GCP_COST_FREE_TIER_PAGES_DIR"); dir != "" {
+		pages := freetier.NewLocalPages(dir)
+		if urlMap == "" {
+			opts = append(opts, freetier.WithPageFinder(pages))
+		}
+		opts = append(opts, freetier.WithPageFetcher(pages))
+		log.Printf("Free tier documentation is read from saved pages in %q", dir)
+	}
+	return opts
+}
+
+// envBool reports whether the environment variable is set to a true value
+func envBool(name string) bool {
+	v, err := strconv.ParseBool(os.Getenv(name))
+	return err == nil && v
+}
+
+// envDuration parses a duration from the environment variable, falling back to def
+func envDuration(name string, def time.Duration) time.Duration {
+	v := os.Getenv(name)
+	if v == "" {
+		return def
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		log.Printf("Warning: invalid %s=%q, using default %s", name, v, def)
+		return def
+	}
+	return d
+}